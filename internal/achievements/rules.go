@@ -0,0 +1,129 @@
+package achievements
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/models"
+)
+
+// EventType is a kind of progress event achievements are evaluated on.
+type EventType string
+
+const (
+	EventLessonCompleted     EventType = "lesson_completed"
+	EventCourseCompleted     EventType = "course_completed"
+	EventAssessmentSubmitted EventType = "assessment_submitted"
+	EventExerciseAnswered    EventType = "exercise_answered"
+)
+
+// Event is a progress event of a user.
+type Event struct {
+	Type     EventType
+	UserID   uuid.UUID
+	CourseID *uuid.UUID
+	// Score is the percentage scored, for assessment events.
+	Score float64
+	At    time.Time
+}
+
+// Rule types.
+const (
+	RuleLessonsCompleted = "lessons_completed"
+	RuleLessonsPerDay    = "lessons_per_day"
+	RuleCoursesCompleted = "courses_completed"
+	RuleAssessmentScore  = "assessment_score"
+	RuleStreakDays       = "streak_days"
+)
+
+// ruleEvents lists the events each rule type is evaluated on.
+var ruleEvents = map[string][]EventType{
+	RuleLessonsCompleted: {EventLessonCompleted},
+	RuleLessonsPerDay:    {EventLessonCompleted},
+	RuleCoursesCompleted: {EventCourseCompleted},
+	RuleAssessmentScore:  {EventAssessmentSubmitted},
+	RuleStreakDays:       {EventLessonCompleted, EventAssessmentSubmitted, EventExerciseAnswered},
+}
+
+// Rule is the declarative condition of an achievement, stored as JSON, e.g.
+//
+//	{"type": "lessons_per_day", "count": 5}
+//	{"type": "assessment_score", "min_score": 100}
+//	{"type": "streak_days", "count": 7}
+type Rule struct {
+	Type     string  `json:"type"`
+	Count    int     `json:"count,omitempty"`
+	MinScore float64 `json:"min_score,omitempty"`
+}
+
+// ParseRule decodes and validates the rule of an achievement.
+func ParseRule(data models.JSONB) (Rule, error) {
+	var r Rule
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return r, err
+	}
+	return r, r.Validate()
+}
+
+// Validate checks that the rule is well-formed.
+func (r Rule) Validate() error {
+	if _, ok := ruleEvents[r.Type]; !ok {
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	switch r.Type {
+	case RuleAssessmentScore:
+		if r.MinScore <= 0 || r.MinScore > 100 {
+			return fmt.Errorf("%s rule needs a min_score between 0 and 100", r.Type)
+		}
+	default:
+		if r.Count <= 0 {
+			return fmt.Errorf("%s rule needs a positive count", r.Type)
+		}
+	}
+	return nil
+}
+
+// EvaluatedOn reports whether the rule is evaluated when the event occurs.
+func (r Rule) EvaluatedOn(t EventType) bool {
+	for _, e := range ruleEvents[r.Type] {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats are the user's statistics rules are checked against.
+type Stats struct {
+	LessonsCompleted int
+	LessonsToday     int
+	CoursesCompleted int
+	StreakDays       int
+}
+
+// Met reports whether the rule is satisfied by the event and the user's stats.
+func (r Rule) Met(e Event, s Stats) bool {
+	if !r.EvaluatedOn(e.Type) {
+		return false
+	}
+	switch r.Type {
+	case RuleLessonsCompleted:
+		return s.LessonsCompleted >= r.Count
+	case RuleLessonsPerDay:
+		return s.LessonsToday >= r.Count
+	case RuleCoursesCompleted:
+		return s.CoursesCompleted >= r.Count
+	case RuleAssessmentScore:
+		return e.Score >= r.MinScore
+	case RuleStreakDays:
+		return s.StreakDays >= r.Count
+	}
+	return false
+}
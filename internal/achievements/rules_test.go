@@ -0,0 +1,72 @@
+package achievements_test
+
+import (
+	"testing"
+
+	"mathtermind-go/internal/achievements"
+	"mathtermind-go/internal/models"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    models.JSONB
+		want    achievements.Rule
+		wantErr bool
+	}{
+		{
+			name: "lessons per day",
+			data: models.JSONB{"type": "lessons_per_day", "count": 5},
+			want: achievements.Rule{Type: achievements.RuleLessonsPerDay, Count: 5},
+		},
+		{
+			name: "assessment score",
+			data: models.JSONB{"type": "assessment_score", "min_score": 100},
+			want: achievements.Rule{Type: achievements.RuleAssessmentScore, MinScore: 100},
+		},
+		{name: "unknown type", data: models.JSONB{"type": "moon_landing", "count": 1}, wantErr: true},
+		{name: "missing count", data: models.JSONB{"type": "streak_days"}, wantErr: true},
+		{name: "score out of range", data: models.JSONB{"type": "assessment_score", "min_score": 120}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := achievements.ParseRule(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseRule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleMet(t *testing.T) {
+	lesson := achievements.Event{Type: achievements.EventLessonCompleted}
+	assessment := achievements.Event{Type: achievements.EventAssessmentSubmitted, Score: 100}
+
+	tests := []struct {
+		name  string
+		rule  achievements.Rule
+		event achievements.Event
+		stats achievements.Stats
+		want  bool
+	}{
+		{"five lessons today", achievements.Rule{Type: "lessons_per_day", Count: 5}, lesson, achievements.Stats{LessonsToday: 5}, true},
+		{"four lessons today", achievements.Rule{Type: "lessons_per_day", Count: 5}, lesson, achievements.Stats{LessonsToday: 4, LessonsCompleted: 20}, false},
+		{"perfect score", achievements.Rule{Type: "assessment_score", MinScore: 100}, assessment, achievements.Stats{}, true},
+		{"imperfect score", achievements.Rule{Type: "assessment_score", MinScore: 100}, achievements.Event{Type: achievements.EventAssessmentSubmitted, Score: 99.5}, achievements.Stats{}, false},
+		{"score rule ignores lessons", achievements.Rule{Type: "assessment_score", MinScore: 100}, lesson, achievements.Stats{}, false},
+		{"streak on answer", achievements.Rule{Type: "streak_days", Count: 7}, achievements.Event{Type: achievements.EventExerciseAnswered}, achievements.Stats{StreakDays: 7}, true},
+		{"course rule ignores lessons", achievements.Rule{Type: "courses_completed", Count: 1}, lesson, achievements.Stats{CoursesCompleted: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Met(tt.event, tt.stats); got != tt.want {
+				t.Errorf("Met() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
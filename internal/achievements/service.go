@@ -0,0 +1,175 @@
+// Package achievements awards achievements when their declarative rules are
+// met by a user's progress events.
+package achievements
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/gamification"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/notifications"
+	"mathtermind-go/internal/realtime"
+	"mathtermind-go/internal/study"
+)
+
+// maxStreakDays bounds how far back streaks are counted.
+const maxStreakDays = 366
+
+// Earned is an achievement awarded by an event.
+type Earned struct {
+	Achievement *models.Achievement     `json:"achievement"`
+	Record      *models.UserAchievement `json:"record"`
+	Award       *gamification.Award     `json:"award,omitempty"`
+}
+
+// Service evaluates achievement rules and lists achievements.
+type Service struct {
+	pool          *pgxpool.Pool
+	gamification  *gamification.Service
+	notifications *notifications.Service
+}
+
+// NewService creates an achievements service that credits achievement
+// points through awards and announces new achievements through notifier.
+func NewService(pool *pgxpool.Pool, awards *gamification.Service, notifier *notifications.Service) *Service {
+	return &Service{pool: pool, gamification: awards, notifications: notifier}
+}
+
+// Evaluate checks the rules of the achievements the user has not earned
+// yet against the event, in the transaction carried by ctx (see
+// db.ContextWithTx), and awards every achievement whose rule is met. Each achievement is awarded once per user; its points are
+// credited, connected clients are told and, unless the user turned
+// achievement alerts off, a notification is created. It returns db.ErrNoTx
+// outside a transaction.
+func (s *Service) Evaluate(ctx context.Context, e Event) ([]Earned, error) {
+	tx, err := db.RequireTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := db.ListUnearnedAchievements(ctx, tx, e.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		achievement models.Achievement
+		rule        Rule
+	}
+	var evaluated []candidate
+	for _, a := range candidates {
+		rule, err := ParseRule(a.Rule)
+		if err != nil {
+			// A broken rule must not block learning; skip it loudly.
+			slog.Warn("Invalid achievement rule", "achievement", a.Key, "error", err)
+			continue
+		}
+		if rule.EvaluatedOn(e.Type) {
+			evaluated = append(evaluated, candidate{achievement: a, rule: rule})
+		}
+	}
+	if len(evaluated) == 0 {
+		return nil, nil
+	}
+
+	stats, err := s.stats(ctx, tx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	var earned []Earned
+	for _, c := range evaluated {
+		if !c.rule.Met(e, stats) {
+			continue
+		}
+		record, err := db.InsertUserAchievement(ctx, tx, e.UserID, c.achievement.ID, e.CourseID)
+		if errors.Is(err, db.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		achievement := c.achievement
+		award, err := s.gamification.Credit(ctx, gamification.Credit{
+			UserID:   e.UserID,
+			Points:   achievement.Points,
+			Reason:   gamification.ReasonAchievement,
+			EventKey: fmt.Sprintf("achievement:%s", achievement.ID),
+			SourceID: &achievement.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.notifications.SendTx(ctx, tx, e.UserID, notifications.TypeAchievement,
+			"Achievement unlocked: "+achievement.Name, achievement.Description, &achievement.ID)
+		if err != nil {
+			return nil, err
+		}
+		item := Earned{Achievement: &achievement, Record: record, Award: award}
+		if err := realtime.Publish(ctx, tx, e.UserID, realtime.EventAchievement, item); err != nil {
+			return nil, err
+		}
+		earned = append(earned, item)
+	}
+	return earned, nil
+}
+
+// stats loads the user statistics rules are checked against. Days are
+// taken in the user's timezone.
+func (s *Service) stats(ctx context.Context, tx pgx.Tx, e Event) (Stats, error) {
+	var st Stats
+	timezone, _, err := db.GetStudyPreferences(ctx, tx, e.UserID)
+	if err != nil {
+		return st, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc, timezone = time.UTC, "UTC"
+	}
+	today := study.Date(e.At, loc)
+	startOfDay := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+
+	if st.LessonsCompleted, err = db.CountCompletedLessons(ctx, tx, e.UserID, time.Time{}); err != nil {
+		return st, err
+	}
+	if st.LessonsToday, err = db.CountCompletedLessons(ctx, tx, e.UserID, startOfDay); err != nil {
+		return st, err
+	}
+	if st.CoursesCompleted, err = db.CountCompletedCourses(ctx, tx, e.UserID); err != nil {
+		return st, err
+	}
+	days, err := db.ListActivityDays(ctx, tx, e.UserID, timezone, today.AddDate(0, 0, -maxStreakDays))
+	if err != nil {
+		return st, err
+	}
+	st.StreakDays, _ = study.Streaks(days, today)
+	return st, nil
+}
+
+// List returns all active achievements.
+func (s *Service) List(ctx context.Context) ([]models.Achievement, error) {
+	achievements, err := db.ListAchievements(ctx, s.pool)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to list achievements")
+	}
+	return achievements, nil
+}
+
+// ListEarned returns the achievements the user earned, newest first.
+func (s *Service) ListEarned(ctx context.Context, userID uuid.UUID) ([]models.UserAchievement, error) {
+	earned, err := db.ListUserAchievements(ctx, s.pool, userID)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to list earned achievements")
+	}
+	return earned, nil
+}
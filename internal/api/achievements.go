@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+
+	apperrors "mathtermind-go/internal/errors"
+)
+
+// ListAchievementsHandler handles GET /api/v1/achievements
+func ListAchievementsHandler(svc AchievementService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		list, err := svc.List(r.Context())
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, list)
+	}
+}
+
+// ListEarnedAchievementsHandler handles GET /api/v1/me/achievements
+func ListEarnedAchievementsHandler(svc AchievementService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+
+		earned, err := svc.ListEarned(r.Context(), identity.UserID)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, earned)
+	}
+}
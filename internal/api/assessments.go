@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apperrors "mathtermind-go/internal/errors"
+)
+
+type submitAttemptRequest struct {
+	Answers map[string]json.RawMessage `json:"answers" validate:"required"`
+}
+
+// StartAttemptHandler handles POST /api/v1/contents/{id}/attempts
+func StartAttemptHandler(svc AssessmentService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		contentID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		started, err := svc.StartAttempt(r.Context(), identity.UserID, contentID)
+		if err != nil {
+			return err
+		}
+
+		status := http.StatusCreated
+		if started.Resumed {
+			status = http.StatusOK
+		}
+		return writeJSON(w, status, started)
+	}
+}
+
+// SubmitAttemptHandler handles POST /api/v1/contents/{id}/attempts/{attemptID}/submit
+func SubmitAttemptHandler(svc AssessmentService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		contentID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		attemptID, err := uuidParam(r, "attemptID")
+		if err != nil {
+			return err
+		}
+		var req submitAttemptRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		outcome, err := svc.SubmitAttempt(r.Context(), identity.UserID, contentID, attemptID, req.Answers)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, outcome)
+	}
+}
+
+type submitAnswerRequest struct {
+	ProblemID string          `json:"problem_id" validate:"required,max=100"`
+	Answer    json.RawMessage `json:"answer" validate:"required"`
+}
+
+// SubmitAnswerHandler handles POST /api/v1/contents/{id}/answers
+func SubmitAnswerHandler(svc AssessmentService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		contentID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		var req submitAnswerRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		outcome, err := svc.SubmitExerciseAnswer(r.Context(), identity.UserID, contentID, req.ProblemID, req.Answer)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusCreated, outcome)
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+
+	"mathtermind-go/internal/auth"
+	apperrors "mathtermind-go/internal/errors"
+)
+
+type registerRequest struct {
+	Username  string  `json:"username" validate:"required,min=3,max=255,username"`
+	Email     string  `json:"email" validate:"required,email,max=255"`
+	Password  string  `json:"password" validate:"required,min=8,max=72"`
+	FirstName *string `json:"first_name" validate:"omitempty,max=100"`
+	LastName  *string `json:"last_name" validate:"omitempty,max=100"`
+	AgeGroup  string  `json:"age_group" validate:"required,max=50"`
+}
+
+type loginRequest struct {
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// RegisterHandler handles POST /api/v1/auth/register
+func RegisterHandler(svc AuthService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var req registerRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		user, tokens, err := svc.Register(r.Context(), auth.RegisterInput{
+			Username:  req.Username,
+			Email:     req.Email,
+			Password:  req.Password,
+			FirstName: req.FirstName,
+			LastName:  req.LastName,
+			AgeGroup:  req.AgeGroup,
+		})
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusCreated, map[string]any{
+			"user":   user,
+			"tokens": tokens,
+		})
+	}
+}
+
+// LoginHandler handles POST /api/v1/auth/login
+func LoginHandler(svc AuthService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var req loginRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		tokens, err := svc.Login(r.Context(), req.Login, req.Password)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, tokens)
+	}
+}
+
+// RefreshHandler handles POST /api/v1/auth/refresh
+func RefreshHandler(svc AuthService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var req refreshRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		tokens, err := svc.Refresh(r.Context(), req.RefreshToken)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, tokens)
+	}
+}
+
+// LogoutHandler handles POST /api/v1/auth/logout
+func LogoutHandler(svc AuthService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var req refreshRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		if err := svc.Logout(r.Context(), req.RefreshToken); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
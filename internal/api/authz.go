@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/auth"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/middleware"
+	"mathtermind-go/internal/repository"
+)
+
+// currentUser returns the authenticated user of the request.
+func currentUser(r *http.Request) (*auth.Identity, error) {
+	identity, ok := middleware.CurrentUser(r.Context())
+	if !ok {
+		return nil, apperrors.Unauthorized("")
+	}
+	return identity, nil
+}
+
+// authorizeCourseEdit checks that the current user may edit the course:
+// users with PermCoursesManage may edit any course, everyone else only the
+// courses they created.
+func authorizeCourseEdit(r *http.Request, courses repository.CourseRepository, courseID uuid.UUID) error {
+	if _, err := currentUser(r); err != nil {
+		return err
+	}
+	ok, err := canEditCourse(r, courses, courseID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.Forbidden("You can only edit courses you created")
+	}
+	return nil
+}
+
+// canEditCourse reports whether the current user, if any, passes
+// authorizeCourseEdit. Public reads use it to show answer keys to the
+// course's editors only.
+func canEditCourse(r *http.Request, courses repository.CourseRepository, courseID uuid.UUID) (bool, error) {
+	identity, ok := middleware.CurrentUser(r.Context())
+	if !ok {
+		return false, nil
+	}
+	if identity.Can(auth.PermCoursesManage) {
+		return true, nil
+	}
+
+	owner, err := courses.Owner(r.Context(), courseID)
+	if err != nil {
+		return false, dbError(err, "course", courseID, "failed to check course owner")
+	}
+	return owner != nil && *owner == identity.UserID, nil
+}
+
+// canEditLesson is canEditCourse for the course of a lesson. It looks the
+// lesson up only for authenticated users.
+func canEditLesson(r *http.Request, courses repository.CourseRepository, lessons repository.LessonRepository, lessonID uuid.UUID) (bool, error) {
+	if _, ok := middleware.CurrentUser(r.Context()); !ok {
+		return false, nil
+	}
+	lesson, err := lessons.Get(r.Context(), lessonID)
+	if err != nil {
+		return false, dbError(err, "lesson", lessonID, "failed to get lesson")
+	}
+	return canEditCourse(r, courses, lesson.CourseID)
+}
+
+// authorizeLessonEdit checks that the current user may edit the lesson's course.
+func authorizeLessonEdit(r *http.Request, courses repository.CourseRepository, lessons repository.LessonRepository, lessonID uuid.UUID) error {
+	lesson, err := lessons.Get(r.Context(), lessonID)
+	if err != nil {
+		return dbError(err, "lesson", lessonID, "failed to get lesson")
+	}
+	return authorizeCourseEdit(r, courses, lesson.CourseID)
+}
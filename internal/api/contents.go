@@ -0,0 +1,258 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/assessment"
+	"mathtermind-go/internal/content"
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/render"
+	"mathtermind-go/internal/repository"
+)
+
+// contentDetails are the type-specific details of a content request; only
+// the details of the content type may be set.
+type contentDetails struct {
+	Theory      *models.TheoryContent      `json:"theory"`
+	Exercise    *models.ExerciseContent    `json:"exercise"`
+	Assessment  *assessmentDetails         `json:"assessment"`
+	Interactive *models.InteractiveContent `json:"interactive"`
+	Resource    *models.ResourceContent    `json:"resource"`
+}
+
+type assessmentDetails struct {
+	Questions       models.JSONB `json:"questions"`
+	TimeLimit       *int         `json:"time_limit"`
+	PassingScore    *float64     `json:"passing_score"`
+	AttemptsAllowed *int         `json:"attempts_allowed"`
+}
+
+// assessment returns the assessment details with the column defaults
+// applied, or nil when they are not set.
+func (d contentDetails) assessment() *models.AssessmentContent {
+	if d.Assessment == nil {
+		return nil
+	}
+	a := &models.AssessmentContent{
+		Questions:       d.Assessment.Questions,
+		TimeLimit:       d.Assessment.TimeLimit,
+		PassingScore:    content.DefaultPassingScore,
+		AttemptsAllowed: content.DefaultAttemptsAllowed,
+	}
+	if d.Assessment.PassingScore != nil {
+		a.PassingScore = *d.Assessment.PassingScore
+	}
+	if d.Assessment.AttemptsAllowed != nil {
+		a.AttemptsAllowed = *d.Assessment.AttemptsAllowed
+	}
+	return a
+}
+
+type createContentRequest struct {
+	Title       string             `json:"title" validate:"required,max=255"`
+	Description *string            `json:"description"`
+	Order       int                `json:"order" validate:"gte=0"`
+	ContentType models.ContentType `json:"content_type" validate:"required"`
+	contentDetails
+}
+
+type updateContentRequest struct {
+	Title       *string `json:"title" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description"`
+	Order       *int    `json:"order" validate:"omitempty,gte=1"`
+	contentDetails
+}
+
+// contentError converts a content write error into an API error.
+func contentError(err error, resource string, id any, message string) error {
+	if errors.Is(err, db.ErrContentTypeMismatch) {
+		return apperrors.Validation("Validation failed", map[string]any{
+			"errors": map[string]string{"content_type": err.Error()},
+		})
+	}
+	return dbError(err, resource, id, message)
+}
+
+// renderedContent is a content item with its theory text rendered.
+type renderedContent struct {
+	models.Content
+	Rendered *render.Document `json:"rendered,omitempty"`
+}
+
+// renderParam reports whether the request asks for rendered theory text
+// with render=html.
+func renderParam(r *http.Request) (bool, error) {
+	switch v := r.URL.Query().Get("render"); v {
+	case "":
+		return false, nil
+	case "html":
+		return true, nil
+	default:
+		return false, apperrors.Errorf(apperrors.ErrCodeValidation, "invalid render parameter").WithDetails(map[string]any{"render": v})
+	}
+}
+
+// renderContents adds the rendered HTML of the theory items of a lesson
+// to its contents.
+func renderContents(r *http.Request, renders RenderService, lessonID uuid.UUID, items []models.Content) ([]renderedContent, error) {
+	docs, err := renders.Contents(r.Context(), lessonID, items)
+	if err != nil {
+		return nil, dbError(err, "lesson", lessonID, "failed to render contents")
+	}
+	out := make([]renderedContent, len(items))
+	for i, item := range items {
+		out[i] = renderedContent{Content: item, Rendered: docs[item.ID]}
+	}
+	return out, nil
+}
+
+// writeContent writes a content item, rendered when the request asks for it.
+func writeContent(w http.ResponseWriter, r *http.Request, status int, renders RenderService, c *models.Content) error {
+	if ok, _ := renderParam(r); !ok {
+		return writeJSON(w, status, c)
+	}
+	rendered, err := renderContents(r, renders, c.LessonID, []models.Content{*c})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, status, rendered[0])
+}
+
+// ListLessonContentsHandler handles GET /api/v1/lessons/{id}/contents
+//
+// Query parameters: render (html adds the rendered theory text, with its
+// table of contents, to the theory items).
+//
+// Questions and problems are listed without their answer keys unless the
+// user may edit the course.
+func ListLessonContentsHandler(courses repository.CourseRepository, lessons repository.LessonRepository, contents ContentService, renders RenderService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		lessonID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		rendered, err := renderParam(r)
+		if err != nil {
+			return err
+		}
+
+		list, err := contents.List(r.Context(), lessonID)
+		if err != nil {
+			return dbError(err, "lesson", lessonID, "failed to list contents")
+		}
+		editor, err := canEditLesson(r, courses, lessons, lessonID)
+		if err != nil {
+			return err
+		}
+		if !editor {
+			for i, item := range list {
+				list[i] = assessment.PublicContent(item)
+			}
+		}
+		if !rendered {
+			return writeJSON(w, http.StatusOK, map[string]any{"items": list})
+		}
+
+		items, err := renderContents(r, renders, lessonID, list)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, map[string]any{"items": items})
+	}
+}
+
+// CreateContentHandler handles POST /api/v1/lessons/{id}/contents
+//
+// Query parameters: render (see ListLessonContentsHandler).
+func CreateContentHandler(courses repository.CourseRepository, lessons repository.LessonRepository, contents ContentService, renders RenderService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		lessonID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		if _, err := renderParam(r); err != nil {
+			return err
+		}
+		if err := authorizeLessonEdit(r, courses, lessons, lessonID); err != nil {
+			return err
+		}
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		var req createContentRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		if req.Resource != nil {
+			req.Resource.CreatedBy = &identity.UserID
+		}
+		created, err := contents.Create(r.Context(), models.Content{
+			LessonID:    lessonID,
+			Title:       req.Title,
+			Description: req.Description,
+			Order:       req.Order,
+			ContentType: req.ContentType,
+			Theory:      req.Theory,
+			Exercise:    req.Exercise,
+			Assessment:  req.assessment(),
+			Interactive: req.Interactive,
+			Resource:    req.Resource,
+		})
+		if err != nil {
+			return contentError(err, "lesson", lessonID, "failed to create content")
+		}
+
+		return writeContent(w, r, http.StatusCreated, renders, created)
+	}
+}
+
+// UpdateContentHandler handles PATCH /api/v1/lessons/{id}/contents/{contentID}
+//
+// Details in the request replace the stored details of the content type;
+// the type of a content item cannot be changed. Query parameters: render
+// (see ListLessonContentsHandler).
+func UpdateContentHandler(courses repository.CourseRepository, lessons repository.LessonRepository, contents ContentService, renders RenderService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		lessonID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		id, err := uuidParam(r, "contentID")
+		if err != nil {
+			return err
+		}
+		if _, err := renderParam(r); err != nil {
+			return err
+		}
+		if err := authorizeLessonEdit(r, courses, lessons, lessonID); err != nil {
+			return err
+		}
+		var req updateContentRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		updated, err := contents.Update(r.Context(), lessonID, id, db.ContentPatch{
+			Title:       req.Title,
+			Description: req.Description,
+			Order:       req.Order,
+			Theory:      req.Theory,
+			Exercise:    req.Exercise,
+			Assessment:  req.assessment(),
+			Interactive: req.Interactive,
+			Resource:    req.Resource,
+		})
+		if err != nil {
+			return contentError(err, "content", id, "failed to update content")
+		}
+
+		return writeContent(w, r, http.StatusOK, renders, updated)
+	}
+}
@@ -0,0 +1,351 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/api"
+	"mathtermind-go/internal/auth"
+	"mathtermind-go/internal/content"
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/render"
+	"mathtermind-go/internal/repository"
+)
+
+// fakeTx runs units of work without a transaction.
+type fakeTx struct{}
+
+func (fakeTx) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) WithReadOnlyTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeLessons struct {
+	repository.LessonRepository
+
+	lessons map[uuid.UUID]*models.Lesson
+}
+
+func (f *fakeLessons) Get(_ context.Context, id uuid.UUID) (*models.Lesson, error) {
+	l, ok := f.lessons[id]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return l, nil
+}
+
+func (f *fakeLessons) ListByCourse(_ context.Context, courseID uuid.UUID) ([]models.Lesson, error) {
+	var lessons []models.Lesson
+	for _, l := range f.lessons {
+		if l.CourseID == courseID {
+			lessons = append(lessons, *l)
+		}
+	}
+	return lessons, nil
+}
+
+type fakeContents struct {
+	repository.ContentRepository
+
+	contents map[uuid.UUID]*models.Content
+	created  *models.Content
+	patch    *db.ContentPatch
+}
+
+func (f *fakeContents) Get(_ context.Context, id uuid.UUID) (*models.Content, error) {
+	c, ok := f.contents[id]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return c, nil
+}
+
+func (f *fakeContents) ListByLesson(_ context.Context, lessonID uuid.UUID) ([]models.Content, error) {
+	var contents []models.Content
+	for _, c := range f.contents {
+		if c.LessonID == lessonID {
+			contents = append(contents, *c)
+		}
+	}
+	return contents, nil
+}
+
+func (f *fakeContents) Create(_ context.Context, c models.Content) (*models.Content, error) {
+	c.ID = uuid.New()
+	f.created = &c
+	return &c, nil
+}
+
+func (f *fakeContents) Update(_ context.Context, id uuid.UUID, p db.ContentPatch) (*models.Content, error) {
+	f.patch = &p
+	return f.contents[id], nil
+}
+
+// contentFixture is a lesson owned by an author and a theory item in it.
+type contentFixture struct {
+	author   *auth.Identity
+	lesson   *models.Lesson
+	theory   *models.Content
+	courses  *fakeCourses
+	lessons  *fakeLessons
+	contents *fakeContents
+	service  *content.Service
+	renders  *render.Service
+}
+
+func newContentFixture() *contentFixture {
+	author := &auth.Identity{UserID: uuid.New(), Role: auth.RoleAuthor}
+	course := &models.Course{Base: models.Base{ID: uuid.New()}, Name: "Algebra", CreatedBy: &author.UserID}
+	lesson := &models.Lesson{Base: models.Base{ID: uuid.New()}, CourseID: course.ID, Title: "Fractions"}
+	theory := &models.Content{
+		Base:        models.Base{ID: uuid.New()},
+		LessonID:    lesson.ID,
+		Title:       "Intro",
+		ContentType: models.ContentTypeTheory,
+		Theory:      &models.TheoryContent{TextContent: "A fraction is..."},
+	}
+
+	f := &contentFixture{
+		author:   author,
+		lesson:   lesson,
+		theory:   theory,
+		courses:  &fakeCourses{courses: map[uuid.UUID]*models.Course{course.ID: course}},
+		lessons:  &fakeLessons{lessons: map[uuid.UUID]*models.Lesson{lesson.ID: lesson}},
+		contents: &fakeContents{contents: map[uuid.UUID]*models.Content{theory.ID: theory}},
+	}
+	repos := &repository.Repositories{Tx: fakeTx{}, Lessons: f.lessons, Contents: f.contents}
+	f.service = content.NewService(repos)
+	f.renders = render.NewService(repos, render.NewRenderer(render.DefaultCacheSize))
+	return f
+}
+
+func TestListLessonContentsHandler(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantHTML   func(f *contentFixture, next *models.Lesson) string
+	}{
+		{query: "", wantStatus: http.StatusOK},
+		{
+			query:      "?render=html",
+			wantStatus: http.StatusOK,
+			wantHTML: func(f *contentFixture, next *models.Lesson) string {
+				return `<p>A fraction <span class="math inline">\(\frac{a}{b}\)</span>, see <a href="` +
+					render.LessonURL(f.lesson.CourseID, next.ID) + `" class="lesson-link" rel="nofollow">next</a>.</p>` + "\n"
+			},
+		},
+		{query: "?render=pdf", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			f := newContentFixture()
+			f.theory.Theory.TextContent = "A fraction $\\frac{a}{b}$, see [next](lesson:2)."
+			next := &models.Lesson{Base: models.Base{ID: uuid.New()}, CourseID: f.lesson.CourseID, LessonOrder: 2}
+			f.lessons.lessons[next.ID] = next
+
+			rec := serve(http.MethodGet, "/lessons/{id}/contents", api.ListLessonContentsHandler(f.courses, f.lessons, f.service, f.renders),
+				"/lessons/"+f.lesson.ID.String()+"/contents"+tt.query, "", nil)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var res struct {
+				Items []struct {
+					Theory   *models.TheoryContent `json:"theory"`
+					Rendered *render.Document      `json:"rendered"`
+				} `json:"items"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&res); err != nil || len(res.Items) != 1 {
+				t.Fatalf("items = %+v (%v), want the theory item", res.Items, err)
+			}
+			item := res.Items[0]
+			if item.Theory == nil || item.Theory.TextContent != f.theory.Theory.TextContent {
+				t.Errorf("theory = %+v, want the raw text", item.Theory)
+			}
+			switch {
+			case tt.wantHTML == nil && item.Rendered != nil:
+				t.Errorf("rendered = %+v, want none", item.Rendered)
+			case tt.wantHTML != nil && (item.Rendered == nil || item.Rendered.HTML != tt.wantHTML(f, next)):
+				t.Errorf("rendered = %+v, want HTML %s", item.Rendered, tt.wantHTML(f, next))
+			}
+		})
+	}
+}
+
+func TestListLessonContentsHandlerAnswerKeys(t *testing.T) {
+	f := newContentFixture()
+	quiz := &models.Content{
+		Base:        models.Base{ID: uuid.New()},
+		LessonID:    f.lesson.ID,
+		Title:       "Quiz",
+		ContentType: models.ContentTypeAssessment,
+		Assessment: &models.AssessmentContent{Questions: models.JSONB{"questions": []any{
+			map[string]any{"id": "q1", "type": "numeric", "prompt": "1/4 = ?", "value": 0.25, "tolerance": 0.01},
+			map[string]any{"id": "q2", "type": "expression", "prompt": "Simplify 2x/2", "expression": "x", "variables": []any{"x"}},
+		}}},
+	}
+	f.contents.contents[quiz.ID] = quiz
+
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		wantKeys bool
+	}{
+		{"anonymous", nil, false},
+		{"learner", &auth.Identity{UserID: uuid.New(), Role: auth.RoleLearner}, false},
+		{"other author", &auth.Identity{UserID: uuid.New(), Role: auth.RoleAuthor}, false},
+		{"owner", f.author, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(http.MethodGet, "/lessons/{id}/contents", api.ListLessonContentsHandler(f.courses, f.lessons, f.service, f.renders),
+				"/lessons/"+f.lesson.ID.String()+"/contents?render=html", "", tt.identity)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+			}
+			body := rec.Body.String()
+			if !strings.Contains(body, `"prompt":"1/4 = ?"`) || !strings.Contains(body, `"rendered":`) {
+				t.Errorf("body lacks the question or the rendered theory: %s", body)
+			}
+			for _, key := range []string{`"value":`, `"tolerance":`, `"expression":`} {
+				if got := strings.Contains(body, key); got != tt.wantKeys {
+					t.Errorf("body has %s = %v, want %v", key, got, tt.wantKeys)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateContentHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		check      func(t *testing.T, f *contentFixture, created *models.Content)
+	}{
+		{
+			name:       "theory",
+			body:       `{"title": "Intro", "content_type": "theory", "theory": {"text_content": "A fraction is..."}}`,
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, f *contentFixture, created *models.Content) {
+				if created.LessonID != f.lesson.ID || created.Theory == nil {
+					t.Errorf("created = %+v, want theory in lesson %s", created, f.lesson.ID)
+				}
+			},
+		},
+		{
+			name: "assessment defaults",
+			body: `{"title": "Quiz", "content_type": "assessment", "assessment": {"questions": {"questions": [
+				{"id": "q1", "type": "numeric", "prompt": "1/2 = ?", "value": 0.5}
+			]}}}`,
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, _ *contentFixture, created *models.Content) {
+				if a := created.Assessment; a == nil || a.PassingScore != 70 || a.AttemptsAllowed != 3 {
+					t.Errorf("assessment = %+v, want the column defaults", a)
+				}
+			},
+		},
+		{
+			name:       "resource owner",
+			body:       `{"title": "Video", "content_type": "resource", "resource": {"resource_type": "video", "url": "https://example.com/v"}}`,
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, f *contentFixture, created *models.Content) {
+				if r := created.Resource; r == nil || r.CreatedBy == nil || *r.CreatedBy != f.author.UserID {
+					t.Errorf("resource = %+v, want created by %s", r, f.author.UserID)
+				}
+			},
+		},
+		{
+			name:       "details of another type",
+			body:       `{"title": "Intro", "content_type": "exercise", "theory": {"text_content": "A fraction is..."}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid problems",
+			body:       `{"title": "Practice", "content_type": "exercise", "exercise": {"problems": {"problems": []}}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newContentFixture()
+			rec := serve(http.MethodPost, "/lessons/{id}/contents",
+				api.CreateContentHandler(f.courses, f.lessons, f.service, f.renders),
+				"/lessons/"+f.lesson.ID.String()+"/contents", tt.body, f.author)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.check != nil {
+				tt.check(t, f, f.contents.created)
+			} else if f.contents.created != nil {
+				t.Errorf("created %+v, want nothing stored", f.contents.created)
+			}
+		})
+	}
+}
+
+func TestUpdateContentHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		lessonID   func(f *contentFixture) uuid.UUID
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "details of the content type",
+			body:       `{"title": "Introduction", "theory": {"text_content": "A fraction is a part of a whole."}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "details of another type",
+			body:       `{"exercise": {"problems": {"problems": [{"id": "p1", "prompt": "Simplify", "expression": "x"}]}}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "content of another lesson",
+			lessonID: func(f *contentFixture) uuid.UUID {
+				other := &models.Lesson{Base: models.Base{ID: uuid.New()}, CourseID: f.lesson.CourseID}
+				f.lessons.lessons[other.ID] = other
+				return other.ID
+			},
+			body:       `{"title": "Introduction"}`,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newContentFixture()
+			lessonID := f.lesson.ID
+			if tt.lessonID != nil {
+				lessonID = tt.lessonID(f)
+			}
+			rec := serve(http.MethodPatch, "/lessons/{id}/contents/{contentID}",
+				api.UpdateContentHandler(f.courses, f.lessons, f.service, f.renders),
+				"/lessons/"+lessonID.String()+"/contents/"+f.theory.ID.String(), tt.body, f.author)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if stored := f.contents.patch != nil; stored != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("patch stored = %v, want %v", stored, tt.wantStatus == http.StatusOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"mathtermind-go/internal/auth"
+	"mathtermind-go/internal/coursepack"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/repository"
+)
+
+// ImportCourseHandler handles POST /api/v1/courses/import
+//
+// The request body is a course package archive. With dry_run=true the
+// package is only validated and the response lists its problems; otherwise
+// an invalid package is rejected with its problems in the error details.
+// Only users who manage courses may import packages with new tags.
+func ImportCourseHandler(packs CoursePackService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		opts := coursepack.ImportOptions{Owner: identity.UserID, CreateTags: identity.Can(auth.PermCoursesManage)}
+		if v := r.URL.Query().Get("dry_run"); v != "" {
+			if opts.DryRun, err = strconv.ParseBool(v); err != nil {
+				return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid dry_run parameter").WithDetails(map[string]any{"dry_run": v})
+			}
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, coursepack.MaxPackageSize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return apperrors.Errorf(apperrors.ErrCodeValidation, "course package is larger than %d MiB", coursepack.MaxPackageSize>>20)
+		}
+		if err != nil {
+			return apperrors.BadRequest("failed to read course package").WithDetails(map[string]any{"error": err.Error()})
+		}
+
+		res, err := packs.Import(r.Context(), data, opts)
+		if err != nil {
+			return apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to import course")
+		}
+		switch {
+		case opts.DryRun:
+			return writeJSON(w, http.StatusOK, res)
+		case !res.Valid:
+			return apperrors.Validation("Invalid course package", map[string]any{"problems": res.Problems})
+		default:
+			return writeJSON(w, http.StatusCreated, res)
+		}
+	}
+}
+
+// ExportCourseHandler handles GET /api/v1/courses/{id}/export
+//
+// Query parameters: format (zip or tar.gz, default zip).
+func ExportCourseHandler(courses repository.CourseRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		format, err := coursepack.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid format parameter").WithDetails(map[string]any{"format": r.URL.Query().Get("format")})
+		}
+		if err := authorizeCourseEdit(r, courses, id); err != nil {
+			return err
+		}
+
+		course, err := courses.Get(r.Context(), id)
+		if err != nil {
+			return dbError(err, "course", id, "failed to get course")
+		}
+		// Build the archive first, errors cannot be reported once the
+		// response has started.
+		var buf bytes.Buffer
+		if err := coursepack.Write(&buf, course, format); err != nil {
+			return apperrors.Wrap(err, apperrors.ErrCodeInternal, "failed to export course")
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": coursepack.FileName(course, format),
+		}))
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.WriteHeader(http.StatusOK)
+		_, err = buf.WriteTo(w)
+		return err
+	}
+}
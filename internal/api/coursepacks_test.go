@@ -0,0 +1,67 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/api"
+	"mathtermind-go/internal/auth"
+	"mathtermind-go/internal/coursepack"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/repository"
+)
+
+func TestImportCourseHandlerProblems(t *testing.T) {
+	packs := coursepack.NewService(&repository.Repositories{Tx: fakeTx{}})
+	author := &auth.Identity{UserID: uuid.New(), Role: auth.RoleAuthor}
+
+	tests := []struct {
+		query      string
+		wantStatus int
+	}{
+		{"?dry_run=true", http.StatusOK},
+		{"", http.StatusBadRequest},
+		{"?dry_run=maybe", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := serve(http.MethodPost, "/courses/import", api.ImportCourseHandler(packs),
+			"/courses/import"+tt.query, "not an archive", author)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("POST %s status = %d, want %d: %s", tt.query, rec.Code, tt.wantStatus, rec.Body)
+			continue
+		}
+		if tt.wantStatus != http.StatusOK {
+			continue
+		}
+		var res coursepack.ImportResult
+		if err := json.NewDecoder(rec.Body).Decode(&res); err != nil || res.Valid || !res.DryRun || len(res.Problems) == 0 {
+			t.Errorf("dry run result = %+v (%v), want problems", res, err)
+		}
+	}
+}
+
+func TestExportCourseHandler(t *testing.T) {
+	owner := uuid.New()
+	course := &models.Course{Base: models.Base{ID: uuid.New()}, Topic: "MATH", Name: "Linear algebra", Description: "Vectors.", CreatedBy: &owner}
+	courses := &fakeCourses{courses: map[uuid.UUID]*models.Course{course.ID: course}}
+	identity := &auth.Identity{UserID: owner, Role: auth.RoleAuthor}
+	target := "/courses/" + course.ID.String() + "/export"
+
+	rec := serve(http.MethodGet, "/courses/{id}/export", api.ExportCourseHandler(courses), target+"?format=tar.gz", "", identity)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename=linear-algebra.tar.gz` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if pkg, err := coursepack.Read(rec.Body.Bytes()); err != nil || pkg.Course.Name != course.Name {
+		t.Errorf("read exported package: %v", err)
+	}
+
+	if rec := serve(http.MethodGet, "/courses/{id}/export", api.ExportCourseHandler(courses), target+"?format=rar", "", identity); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown format status = %d, want 400", rec.Code)
+	}
+}
@@ -1,47 +1,190 @@
 package api
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
-	"strconv"
-
-	"github.com/jackc/pgx/v5/pgxpool"
 
+	"mathtermind-go/internal/assessment"
 	"mathtermind-go/internal/db"
 	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/pagination"
+	"mathtermind-go/internal/repository"
 )
 
 // ListCoursesHandler handles GET /api/v1/courses
-func ListCoursesHandler(pool *pgxpool.Pool) apperrors.HandlerFunc {
+//
+// Query parameters: topic, tag (tag names, repeated or comma-separated),
+// tag_match (any or all; default any), sort (name, duration_min or
+// created_at, prefixed with "-" for descending order; default -created_at),
+// cursor (the next_cursor of the previous page), limit and offset. offset
+// is kept for older clients and cannot be combined with cursor.
+func ListCoursesHandler(courses repository.CourseRepository) apperrors.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
-		limit := 20
-		offset := 0
-		if v := r.URL.Query().Get("limit"); v != "" {
-			lv, err := strconv.Atoi(v)
-			if err != nil || lv <= 0 || lv > 100 {
-				return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid limit parameter").WithDetails(map[string]any{"limit": v})
-			}
-			limit = lv
+		limit, offset, err := pageParams(r)
+		if err != nil {
+			return err
+		}
+
+		query := r.URL.Query()
+		filter := db.CourseFilter{Topic: query.Get("topic"), Tags: listParam(r, "tag")}
+		switch v := query.Get("tag_match"); v {
+		case "", "any":
+		case "all":
+			filter.MatchAllTags = true
+		default:
+			return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid tag_match parameter").WithDetails(map[string]any{"tag_match": v})
+		}
+
+		page := db.CoursePage{Limit: limit, Offset: offset}
+		if page.Sort, err = pagination.ParseSort(query.Get("sort"), db.CourseSorts, db.DefaultCourseSort); err != nil {
+			return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid sort parameter").WithDetails(map[string]any{"sort": query.Get("sort")})
 		}
-		if v := r.URL.Query().Get("offset"); v != "" {
-			ov, err := strconv.Atoi(v)
-			if err != nil || ov < 0 {
-				return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid offset parameter").WithDetails(map[string]any{"offset": v})
+		if v := query.Get("cursor"); v != "" {
+			if offset != 0 {
+				return apperrors.Errorf(apperrors.ErrCodeValidation, "cursor and offset parameters cannot be combined")
+			}
+			if page.After, err = pagination.Decode(v, page.Sort); err != nil {
+				return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid cursor parameter").WithDetails(map[string]any{"cursor": v})
 			}
-			offset = ov
 		}
 
-		courses, err := db.ListCourses(r.Context(), pool, limit, offset)
+		list, err := courses.List(r.Context(), filter, page)
 		if err != nil {
 			return apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to list courses")
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		return json.NewEncoder(w).Encode(map[string]any{
-			"items":  courses,
-			"limit":  limit,
-			"offset": offset,
+		resp := map[string]any{
+			"items":    list.Items,
+			"has_more": list.HasMore,
+			"limit":    limit,
+			"offset":   offset,
+		}
+		if list.NextCursor != nil {
+			resp["next_cursor"] = *list.NextCursor
+		}
+		return writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// GetCourseHandler handles GET /api/v1/courses/{id}
+//
+// The answer keys of assessments and exercises are only included for the
+// course's editors.
+func GetCourseHandler(courses repository.CourseRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		course, err := courses.Get(r.Context(), id)
+		if errors.Is(err, db.ErrNotFound) {
+			return apperrors.NotFound("course", id)
+		}
+		if err != nil {
+			return apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to get course")
+		}
+		editor, err := canEditCourse(r, courses, id)
+		if err != nil {
+			return err
+		}
+		if !editor {
+			public := assessment.PublicCourse(*course)
+			course = &public
+		}
+
+		return writeJSON(w, http.StatusOK, course)
+	}
+}
+
+type createCourseRequest struct {
+	Topic       string `json:"topic" validate:"required,max=50"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"required"`
+	DurationMin int    `json:"duration_min" validate:"gte=0"`
+}
+
+type updateCourseRequest struct {
+	Topic       *string `json:"topic" validate:"omitempty,min=1,max=50"`
+	Name        *string `json:"name" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description" validate:"omitempty,min=1"`
+	DurationMin *int    `json:"duration_min" validate:"omitempty,gte=0"`
+}
+
+// CreateCourseHandler handles POST /api/v1/courses
+func CreateCourseHandler(courses repository.CourseRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		var req createCourseRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		course, err := courses.Create(r.Context(), models.Course{
+			Topic:       req.Topic,
+			Name:        req.Name,
+			Description: req.Description,
+			DurationMin: req.DurationMin,
+			CreatedBy:   &identity.UserID,
 		})
+		if err != nil {
+			return apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to create course")
+		}
+
+		return writeJSON(w, http.StatusCreated, course)
+	}
+}
+
+// UpdateCourseHandler handles PATCH /api/v1/courses/{id}
+func UpdateCourseHandler(courses repository.CourseRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		if err := authorizeCourseEdit(r, courses, id); err != nil {
+			return err
+		}
+		var req updateCourseRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		course, err := courses.Update(r.Context(), id, db.CoursePatch{
+			Topic:       req.Topic,
+			Name:        req.Name,
+			Description: req.Description,
+			DurationMin: req.DurationMin,
+		})
+		if err != nil {
+			return dbError(err, "course", id, "failed to update course")
+		}
+
+		return writeJSON(w, http.StatusOK, course)
+	}
+}
+
+// DeleteCourseHandler handles DELETE /api/v1/courses/{id}
+func DeleteCourseHandler(courses repository.CourseRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		if err := authorizeCourseEdit(r, courses, id); err != nil {
+			return err
+		}
+
+		if err := courses.Delete(r.Context(), id); err != nil {
+			return dbError(err, "course", id, "failed to delete course")
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
 	}
 }
@@ -0,0 +1,240 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/api"
+	"mathtermind-go/internal/auth"
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/middleware"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/pagination"
+	"mathtermind-go/internal/repository"
+)
+
+// fakeCourses is an in-memory CourseRepository; methods the tests do not
+// use panic through the nil embedded interface.
+type fakeCourses struct {
+	repository.CourseRepository
+
+	courses map[uuid.UUID]*models.Course
+	filter  db.CourseFilter
+	page    db.CoursePage
+}
+
+func (f *fakeCourses) List(_ context.Context, filter db.CourseFilter, page db.CoursePage) (*pagination.Page[models.Course], error) {
+	f.filter, f.page = filter, page
+	return &pagination.Page[models.Course]{Items: []models.Course{}}, nil
+}
+
+func (f *fakeCourses) Get(_ context.Context, id uuid.UUID) (*models.Course, error) {
+	c, ok := f.courses[id]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return c, nil
+}
+
+func (f *fakeCourses) Owner(_ context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	c, ok := f.courses[id]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return c.CreatedBy, nil
+}
+
+func (f *fakeCourses) Update(_ context.Context, id uuid.UUID, p db.CoursePatch) (*models.Course, error) {
+	c := *f.courses[id]
+	if p.Name != nil {
+		c.Name = *p.Name
+	}
+	return &c, nil
+}
+
+// serve routes a request to handler mounted at pattern, as identity when it
+// is not nil.
+func serve(method, pattern string, handler apperrors.HandlerFunc, target, body string, identity *auth.Identity) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Method(method, pattern, apperrors.Middleware(handler))
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if identity != nil {
+		req = req.WithContext(middleware.WithIdentity(req.Context(), identity))
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListCoursesHandler(t *testing.T) {
+	byName := pagination.Sort{Field: "name"}
+	cursor, err := pagination.Encode(byName, "Algebra", uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantFilter db.CourseFilter
+		wantSort   pagination.Sort
+		wantCursor bool
+	}{
+		{
+			name:       "defaults",
+			wantStatus: http.StatusOK,
+			wantSort:   db.DefaultCourseSort,
+		},
+		{
+			name:       "filters",
+			query:      "?topic=MATH&tag=algebra,geometry&tag=trigonometry&tag_match=all&sort=-duration_min",
+			wantStatus: http.StatusOK,
+			wantFilter: db.CourseFilter{Topic: "MATH", Tags: []string{"algebra", "geometry", "trigonometry"}, MatchAllTags: true},
+			wantSort:   pagination.Sort{Field: "duration_min", Desc: true},
+		},
+		{
+			name:       "cursor",
+			query:      "?sort=name&cursor=" + cursor,
+			wantStatus: http.StatusOK,
+			wantSort:   byName,
+			wantCursor: true,
+		},
+		{name: "unknown sort", query: "?sort=password_hash", wantStatus: http.StatusBadRequest},
+		{name: "unknown tag match", query: "?tag=algebra&tag_match=some", wantStatus: http.StatusBadRequest},
+		{name: "cursor of another sort", query: "?sort=-name&cursor=" + cursor, wantStatus: http.StatusBadRequest},
+		{name: "cursor and offset", query: "?sort=name&offset=20&cursor=" + cursor, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			courses := &fakeCourses{}
+			rec := serve(http.MethodGet, "/courses", api.ListCoursesHandler(courses), "/courses"+tt.query, "", nil)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if !reflect.DeepEqual(courses.filter, tt.wantFilter) {
+				t.Errorf("filter = %+v, want %+v", courses.filter, tt.wantFilter)
+			}
+			if courses.page.Sort != tt.wantSort || courses.page.Limit != 20 {
+				t.Errorf("page = %+v, want sort %+v and limit 20", courses.page, tt.wantSort)
+			}
+			if (courses.page.After != nil) != tt.wantCursor {
+				t.Errorf("page.After = %v, want cursor %v", courses.page.After, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestGetCourseHandler(t *testing.T) {
+	course := &models.Course{Base: models.Base{ID: uuid.New()}, Name: "Algebra"}
+	courses := &fakeCourses{courses: map[uuid.UUID]*models.Course{course.ID: course}}
+	handler := api.GetCourseHandler(courses)
+
+	rec := serve(http.MethodGet, "/courses/{id}", handler, "/courses/"+course.ID.String(), "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got models.Course
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil || got.ID != course.ID || got.Name != course.Name {
+		t.Errorf("body = %+v (%v), want %+v", got, err, course)
+	}
+
+	for target, want := range map[string]int{
+		"/courses/" + uuid.NewString(): http.StatusNotFound,
+		"/courses/not-a-uuid":          http.StatusBadRequest,
+	} {
+		if rec := serve(http.MethodGet, "/courses/{id}", handler, target, "", nil); rec.Code != want {
+			t.Errorf("GET %s status = %d, want %d", target, rec.Code, want)
+		}
+	}
+}
+
+func TestGetCourseHandlerAnswerKeys(t *testing.T) {
+	owner := uuid.New()
+	course := &models.Course{
+		Base: models.Base{ID: uuid.New()}, Name: "Algebra", CreatedBy: &owner,
+		Lessons: []models.Lesson{{Contents: []models.Content{
+			{ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{Questions: models.JSONB{"questions": []any{
+				map[string]any{"id": "q1", "type": "single_choice", "prompt": "1/2 = ?",
+					"options": []any{map[string]any{"id": "a", "text": "0.5"}}, "correct": []any{"a"}},
+				map[string]any{"id": "q2", "type": "short_text", "prompt": "Name it", "accepted": []any{"half"}},
+			}}}},
+			{ContentType: models.ContentTypeExercise, Exercise: &models.ExerciseContent{Problems: models.JSONB{"problems": []any{
+				map[string]any{"id": "p1", "prompt": "Simplify 2x/2", "expression": "x", "variables": []any{"x"}},
+			}}}},
+		}}},
+	}
+	courses := &fakeCourses{courses: map[uuid.UUID]*models.Course{course.ID: course}}
+
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		wantKeys bool
+	}{
+		{"anonymous", nil, false},
+		{"learner", &auth.Identity{UserID: uuid.New(), Role: auth.RoleLearner}, false},
+		{"other author", &auth.Identity{UserID: uuid.New(), Role: auth.RoleAuthor}, false},
+		{"owner", &auth.Identity{UserID: owner, Role: auth.RoleAuthor}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(http.MethodGet, "/courses/{id}", api.GetCourseHandler(courses),
+				"/courses/"+course.ID.String(), "", tt.identity)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+			}
+			body := rec.Body.String()
+			if !strings.Contains(body, `"prompt":"Simplify 2x/2"`) {
+				t.Errorf("body lacks the problem prompt: %s", body)
+			}
+			for _, key := range []string{`"correct":`, `"accepted":`, `"expression":`} {
+				if got := strings.Contains(body, key); got != tt.wantKeys {
+					t.Errorf("body has %s = %v, want %v", key, got, tt.wantKeys)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateCourseHandlerAuthorization(t *testing.T) {
+	owner := uuid.New()
+	course := &models.Course{Base: models.Base{ID: uuid.New()}, Name: "Algebra", CreatedBy: &owner}
+	courses := &fakeCourses{courses: map[uuid.UUID]*models.Course{course.ID: course}}
+
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		want     int
+	}{
+		{"owner", &auth.Identity{UserID: owner, Role: auth.RoleAuthor}, http.StatusOK},
+		{"other author", &auth.Identity{UserID: uuid.New(), Role: auth.RoleAuthor}, http.StatusForbidden},
+		{"course manager", &auth.Identity{UserID: uuid.New(), Role: auth.RoleAdmin}, http.StatusOK},
+		{"anonymous", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(http.MethodPatch, "/courses/{id}", api.UpdateCourseHandler(courses),
+				"/courses/"+course.ID.String(), `{"name": "Linear algebra"}`, tt.identity)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+)
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+// uuidParam parses the named chi URL parameter as a UUID.
+func uuidParam(r *http.Request, name string) (uuid.UUID, error) {
+	v := chi.URLParam(r, name)
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return uuid.Nil, apperrors.Errorf(apperrors.ErrCodeValidation, "invalid %s parameter", name).WithDetails(map[string]any{name: v})
+	}
+	return id, nil
+}
+
+// decodeJSON decodes the request body into dst and validates it.
+func decodeJSON(r *http.Request, dst any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return apperrors.BadRequest("invalid request body").WithDetails(map[string]any{"error": err.Error()})
+	}
+	return apperrors.ValidateStruct(dst)
+}
+
+// dbError converts an error returned by the db package into an API error.
+func dbError(err error, resource string, id any, message string) error {
+	if errors.Is(err, db.ErrNotFound) {
+		return apperrors.NotFound(resource, id)
+	}
+	return apperrors.WrapDB(err, message)
+}
+
+// pageParams parses the limit and offset query parameters. limit defaults
+// to 20 and may not exceed 100.
+func pageParams(r *http.Request) (limit, offset int, err error) {
+	limit = 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		lv, err := strconv.Atoi(v)
+		if err != nil || lv <= 0 || lv > 100 {
+			return 0, 0, apperrors.Errorf(apperrors.ErrCodeValidation, "invalid limit parameter").WithDetails(map[string]any{"limit": v})
+		}
+		limit = lv
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		ov, err := strconv.Atoi(v)
+		if err != nil || ov < 0 {
+			return 0, 0, apperrors.Errorf(apperrors.ErrCodeValidation, "invalid offset parameter").WithDetails(map[string]any{"offset": v})
+		}
+		offset = ov
+	}
+	return limit, offset, nil
+}
+
+// listParam returns the values of a query parameter that may be repeated or
+// hold comma-separated values. Blank values are dropped.
+func listParam(r *http.Request, name string) []string {
+	var values []string
+	for _, v := range r.URL.Query()[name] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
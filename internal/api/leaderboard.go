@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/leaderboard"
+)
+
+// LeaderboardHandler handles GET /api/v1/leaderboard
+//
+// Query parameters: period (week, month or all; default week), topic,
+// age_group and limit (at most 100).
+func LeaderboardHandler(svc LeaderboardService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+
+		query := r.URL.Query()
+		period, err := leaderboard.ParsePeriod(query.Get("period"))
+		if err != nil {
+			return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid period parameter").WithDetails(map[string]any{"period": query.Get("period")})
+		}
+		q := leaderboard.Query{Period: period, Topic: query.Get("topic"), AgeGroup: query.Get("age_group")}
+		if v := query.Get("limit"); v != "" {
+			if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit <= 0 || q.Limit > 100 {
+				return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid limit parameter").WithDetails(map[string]any{"limit": v})
+			}
+		}
+
+		board, err := svc.Get(r.Context(), identity.UserID, q)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, board)
+	}
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/repository"
+)
+
+type createLessonRequest struct {
+	Title            string `json:"title" validate:"required,max=255"`
+	LessonOrder      int    `json:"lesson_order" validate:"gte=0"`
+	EstimatedTimeMin int    `json:"estimated_time_min" validate:"gte=0"`
+	PointsReward     *int   `json:"points_reward" validate:"omitempty,gte=0"`
+}
+
+type updateLessonRequest struct {
+	Title            *string `json:"title" validate:"omitempty,min=1,max=255"`
+	LessonOrder      *int    `json:"lesson_order" validate:"omitempty,gte=1"`
+	EstimatedTimeMin *int    `json:"estimated_time_min" validate:"omitempty,gte=0"`
+	PointsReward     *int    `json:"points_reward" validate:"omitempty,gte=0"`
+}
+
+type reorderLessonsRequest struct {
+	LessonIDs []uuid.UUID `json:"lesson_ids" validate:"required"`
+}
+
+// defaultLessonPoints mirrors the lessons.points_reward column default.
+const defaultLessonPoints = 10
+
+// CreateLessonHandler handles POST /api/v1/courses/{id}/lessons
+func CreateLessonHandler(courses repository.CourseRepository, lessons repository.LessonRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		courseID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		if err := authorizeCourseEdit(r, courses, courseID); err != nil {
+			return err
+		}
+		var req createLessonRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		points := defaultLessonPoints
+		if req.PointsReward != nil {
+			points = *req.PointsReward
+		}
+
+		lesson, err := lessons.Create(r.Context(), models.Lesson{
+			CourseID:         courseID,
+			Title:            req.Title,
+			LessonOrder:      req.LessonOrder,
+			EstimatedTimeMin: req.EstimatedTimeMin,
+			PointsReward:     points,
+		})
+		if err != nil {
+			return dbError(err, "course", courseID, "failed to create lesson")
+		}
+
+		return writeJSON(w, http.StatusCreated, lesson)
+	}
+}
+
+// UpdateLessonHandler handles PATCH /api/v1/lessons/{id}
+func UpdateLessonHandler(courses repository.CourseRepository, lessons repository.LessonRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		if err := authorizeLessonEdit(r, courses, lessons, id); err != nil {
+			return err
+		}
+		var req updateLessonRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		lesson, err := lessons.Update(r.Context(), id, db.LessonPatch{
+			Title:            req.Title,
+			LessonOrder:      req.LessonOrder,
+			EstimatedTimeMin: req.EstimatedTimeMin,
+			PointsReward:     req.PointsReward,
+		})
+		if err != nil {
+			return dbError(err, "lesson", id, "failed to update lesson")
+		}
+
+		return writeJSON(w, http.StatusOK, lesson)
+	}
+}
+
+// DeleteLessonHandler handles DELETE /api/v1/lessons/{id}
+func DeleteLessonHandler(courses repository.CourseRepository, lessons repository.LessonRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		if err := authorizeLessonEdit(r, courses, lessons, id); err != nil {
+			return err
+		}
+
+		if err := lessons.Delete(r.Context(), id); err != nil {
+			return dbError(err, "lesson", id, "failed to delete lesson")
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
+
+// ReorderLessonsHandler handles PUT /api/v1/courses/{id}/lessons/order
+func ReorderLessonsHandler(courses repository.CourseRepository, lessons repository.LessonRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		courseID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		if err := authorizeCourseEdit(r, courses, courseID); err != nil {
+			return err
+		}
+		var req reorderLessonsRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		ordered, err := lessons.Reorder(r.Context(), courseID, req.LessonIDs)
+		if errors.Is(err, db.ErrInvalidLessonOrder) {
+			return apperrors.Validation(err.Error(), map[string]any{"lesson_ids": req.LessonIDs})
+		}
+		if err != nil {
+			return dbError(err, "course", courseID, "failed to reorder lessons")
+		}
+
+		return writeJSON(w, http.StatusOK, map[string]any{"items": ordered})
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/notifications"
+)
+
+// ListNotificationsHandler handles GET /api/v1/me/notifications
+//
+// Query parameters: is_read (true or false), type, cursor (the next_cursor
+// of the previous page) and limit.
+func ListNotificationsHandler(svc NotificationService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		limit, _, err := pageParams(r)
+		if err != nil {
+			return err
+		}
+
+		query := r.URL.Query()
+		lq := notifications.ListQuery{Type: query.Get("type"), Cursor: query.Get("cursor"), Limit: limit}
+		if v := query.Get("is_read"); v != "" {
+			isRead, err := strconv.ParseBool(v)
+			if err != nil {
+				return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid is_read parameter").WithDetails(map[string]any{"is_read": v})
+			}
+			lq.IsRead = &isRead
+		}
+
+		page, err := svc.List(r.Context(), identity.UserID, lq)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, page)
+	}
+}
+
+// UnreadNotificationsHandler handles GET /api/v1/me/notifications/unread-count
+func UnreadNotificationsHandler(svc NotificationService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+
+		n, err := svc.UnreadCount(r.Context(), identity.UserID)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, map[string]int{"unread_count": n})
+	}
+}
+
+// MarkNotificationReadHandler handles POST /api/v1/me/notifications/{id}/read
+func MarkNotificationReadHandler(svc NotificationService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		n, err := svc.MarkRead(r.Context(), identity.UserID, id)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, n)
+	}
+}
+
+// MarkAllNotificationsReadHandler handles POST /api/v1/me/notifications/read
+//
+// The optional type query parameter limits marking to one notification type.
+func MarkAllNotificationsReadHandler(svc NotificationService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+
+		marked, err := svc.MarkAllRead(r.Context(), identity.UserID, r.URL.Query().Get("type"))
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, map[string]int64{"marked": marked})
+	}
+}
+
+// DeleteNotificationHandler handles DELETE /api/v1/me/notifications/{id}
+func DeleteNotificationHandler(svc NotificationService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		if err := svc.Delete(r.Context(), identity.UserID, id); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	apperrors "mathtermind-go/internal/errors"
+)
+
+// PointsHistoryHandler handles GET /api/v1/me/points/history
+func PointsHistoryHandler(svc PointsService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		limit, offset, err := pageParams(r)
+		if err != nil {
+			return err
+		}
+
+		history, err := svc.History(r.Context(), identity.UserID, limit, offset)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, history)
+	}
+}
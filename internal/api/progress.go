@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+
+	apperrors "mathtermind-go/internal/errors"
+)
+
+type completeContentRequest struct {
+	TimeSpentMin int `json:"time_spent_min" validate:"min=0,max=1440"`
+}
+
+// CompleteContentHandler handles POST /api/v1/contents/{id}/complete
+func CompleteContentHandler(svc ProgressService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		contentID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		var req completeContentRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		update, err := svc.CompleteContent(r.Context(), identity.UserID, contentID, req.TimeSpentMin)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, update)
+	}
+}
+
+// ListProgressHandler handles GET /api/v1/me/progress
+func ListProgressHandler(svc ProgressService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+
+		progress, err := svc.ListProgress(r.Context(), identity.UserID)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, progress)
+	}
+}
+
+// GetCourseProgressHandler handles GET /api/v1/me/progress/{courseID}
+func GetCourseProgressHandler(svc ProgressService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		courseID, err := uuidParam(r, "courseID")
+		if err != nil {
+			return err
+		}
+
+		progress, err := svc.GetCourseProgress(r.Context(), identity.UserID, courseID)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, progress)
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/realtime"
+)
+
+// RealtimeHandler handles GET /api/v1/realtime
+//
+// Clients requesting a WebSocket upgrade receive events as JSON messages;
+// other clients, which should send "Accept: text/event-stream", receive them
+// as Server-Sent Events. The access token may be passed in the access_token
+// query parameter.
+func RealtimeHandler(hub RealtimeHub) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+
+		err = hub.Serve(w, r, identity.UserID)
+		if errors.Is(err, realtime.ErrClosed) {
+			return apperrors.New(apperrors.ErrCodeInvalidState, "Server is shutting down")
+		}
+		return err
+	}
+}
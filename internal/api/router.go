@@ -5,20 +5,23 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
-	"github.com/jackc/pgx/v5/pgxpool"
 
+	"mathtermind-go/internal/auth"
 	apperrors "mathtermind-go/internal/errors"
 	"mathtermind-go/internal/middleware"
 )
 
-func NewRouter(pool *pgxpool.Pool) *chi.Mux {
+// NewRouter builds the HTTP handler of the API from its services.
+func NewRouter(s *Services) *chi.Mux {
+	repos := s.Repos
+
 	r := chi.NewRouter()
 
 	middleware.AddMiddleware(r)
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"}, // TODO: replace with your frontend URL
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
 		AllowCredentials: true,
 		MaxAge:           300,
@@ -30,8 +33,114 @@ func NewRouter(pool *pgxpool.Pool) *chi.Mux {
 	})
 
 	r.Route("/api/v1", func(r chi.Router) {
+		// Auth
+		r.Method(http.MethodPost, "/auth/register", apperrors.Middleware(RegisterHandler(s.Auth)))
+		r.Method(http.MethodPost, "/auth/login", apperrors.Middleware(LoginHandler(s.Auth)))
+		r.Method(http.MethodPost, "/auth/refresh", apperrors.Middleware(RefreshHandler(s.Auth)))
+		r.Method(http.MethodPost, "/auth/logout", apperrors.Middleware(LogoutHandler(s.Auth)))
+
 		// Courses
-		r.Method(http.MethodGet, "/courses", apperrors.Middleware(ListCoursesHandler(pool)))
+		r.Method(http.MethodGet, "/courses", apperrors.Middleware(ListCoursesHandler(repos.Courses)))
+		r.With(middleware.OptionalAuthenticate(s.Auth.Tokens())).
+			Method(http.MethodGet, "/courses/{id}", apperrors.Middleware(GetCourseHandler(repos.Courses)))
+		r.With(middleware.OptionalAuthenticate(s.Auth.Tokens())).
+			Method(http.MethodGet, "/lessons/{id}/contents", apperrors.Middleware(ListLessonContentsHandler(repos.Courses, repos.Lessons, s.Content, s.Render)))
+		r.Method(http.MethodGet, "/tags", apperrors.Middleware(ListTagsHandler(repos.Tags)))
+		r.Method(http.MethodGet, "/search", apperrors.Middleware(SearchHandler(s.Search)))
+
+		// Achievements
+		r.Method(http.MethodGet, "/achievements", apperrors.Middleware(ListAchievementsHandler(s.Achievements)))
+
+		// Realtime events
+		r.With(middleware.AuthenticateStream(s.Auth.Tokens())).
+			Method(http.MethodGet, "/realtime", apperrors.Middleware(RealtimeHandler(s.Hub)))
+
+		// Authenticated routes
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Authenticate(s.Auth.Tokens()))
+
+			// Learning
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequirePermission(auth.PermLearn))
+
+				r.Method(http.MethodPost, "/contents/{id}/attempts", apperrors.Middleware(StartAttemptHandler(s.Assessment)))
+				r.Method(http.MethodPost, "/contents/{id}/attempts/{attemptID}/submit", apperrors.Middleware(SubmitAttemptHandler(s.Assessment)))
+				r.Method(http.MethodPost, "/contents/{id}/answers", apperrors.Middleware(SubmitAnswerHandler(s.Assessment)))
+				r.Method(http.MethodPost, "/contents/{id}/complete", apperrors.Middleware(CompleteContentHandler(s.Progress)))
+
+				r.Method(http.MethodGet, "/contents/{id}/states", apperrors.Middleware(ListContentStatesHandler(s.Progress)))
+				r.Method(http.MethodPut, "/contents/{id}/states/{type}", apperrors.Middleware(SaveContentStateHandler(s.Progress)))
+				r.Method(http.MethodDelete, "/contents/{id}/states/{type}", apperrors.Middleware(DeleteContentStateHandler(s.Progress)))
+
+				r.Method(http.MethodGet, "/me/resume", apperrors.Middleware(ResumeHandler(s.Progress)))
+				r.Method(http.MethodGet, "/me/points/history", apperrors.Middleware(PointsHistoryHandler(s.Gamification)))
+				r.Method(http.MethodGet, "/me/achievements", apperrors.Middleware(ListEarnedAchievementsHandler(s.Achievements)))
+
+				r.Method(http.MethodPost, "/me/study-sessions", apperrors.Middleware(StartStudySessionHandler(s.Study)))
+				r.Method(http.MethodPost, "/me/study-sessions/{id}/heartbeat", apperrors.Middleware(StudyHeartbeatHandler(s.Study)))
+				r.Method(http.MethodPost, "/me/study-sessions/{id}/end", apperrors.Middleware(EndStudySessionHandler(s.Study)))
+				r.Method(http.MethodGet, "/me/study-stats", apperrors.Middleware(StudyStatsHandler(s.Study)))
+				r.Method(http.MethodGet, "/me/progress", apperrors.Middleware(ListProgressHandler(s.Progress)))
+				r.Method(http.MethodGet, "/me/progress/{courseID}", apperrors.Middleware(GetCourseProgressHandler(s.Progress)))
+
+				r.Method(http.MethodGet, "/leaderboard", apperrors.Middleware(LeaderboardHandler(s.Leaderboard)))
+			})
+
+			// Settings and notification inbox; every signed-in user has them
+			r.Method(http.MethodGet, "/me/settings", apperrors.Middleware(GetSettingsHandler(repos.Users)))
+			r.Method(http.MethodPatch, "/me/settings", apperrors.Middleware(UpdateSettingsHandler(repos.Users)))
+
+			r.Method(http.MethodGet, "/me/notifications", apperrors.Middleware(ListNotificationsHandler(s.Notifications)))
+			r.Method(http.MethodGet, "/me/notifications/unread-count", apperrors.Middleware(UnreadNotificationsHandler(s.Notifications)))
+			r.Method(http.MethodPost, "/me/notifications/read", apperrors.Middleware(MarkAllNotificationsReadHandler(s.Notifications)))
+			r.Method(http.MethodPost, "/me/notifications/{id}/read", apperrors.Middleware(MarkNotificationReadHandler(s.Notifications)))
+			r.Method(http.MethodDelete, "/me/notifications/{id}", apperrors.Middleware(DeleteNotificationHandler(s.Notifications)))
+
+			// Course authoring; handlers also check course ownership
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequirePermission(auth.PermCoursesWrite))
+
+				r.Method(http.MethodPost, "/courses", apperrors.Middleware(CreateCourseHandler(repos.Courses)))
+				r.Method(http.MethodPatch, "/courses/{id}", apperrors.Middleware(UpdateCourseHandler(repos.Courses)))
+				r.Method(http.MethodDelete, "/courses/{id}", apperrors.Middleware(DeleteCourseHandler(repos.Courses)))
+				r.Method(http.MethodPost, "/courses/import", apperrors.Middleware(ImportCourseHandler(s.CoursePacks)))
+				r.Method(http.MethodGet, "/courses/{id}/export", apperrors.Middleware(ExportCourseHandler(repos.Courses)))
+				r.Method(http.MethodPut, "/courses/{id}/tags/{tagID}", apperrors.Middleware(AttachTagHandler(repos.Courses, repos.Tags)))
+				r.Method(http.MethodDelete, "/courses/{id}/tags/{tagID}", apperrors.Middleware(DetachTagHandler(repos.Courses, repos.Tags)))
+
+				r.Method(http.MethodPost, "/courses/{id}/lessons", apperrors.Middleware(CreateLessonHandler(repos.Courses, repos.Lessons)))
+				r.Method(http.MethodPut, "/courses/{id}/lessons/order", apperrors.Middleware(ReorderLessonsHandler(repos.Courses, repos.Lessons)))
+				r.Method(http.MethodPatch, "/lessons/{id}", apperrors.Middleware(UpdateLessonHandler(repos.Courses, repos.Lessons)))
+				r.Method(http.MethodDelete, "/lessons/{id}", apperrors.Middleware(DeleteLessonHandler(repos.Courses, repos.Lessons)))
+				r.Method(http.MethodPost, "/lessons/{id}/contents", apperrors.Middleware(CreateContentHandler(repos.Courses, repos.Lessons, s.Content, s.Render)))
+				r.Method(http.MethodPatch, "/lessons/{id}/contents/{contentID}", apperrors.Middleware(UpdateContentHandler(repos.Courses, repos.Lessons, s.Content, s.Render)))
+			})
+
+			// Tags are shared by every course
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequirePermission(auth.PermCoursesManage))
+
+				r.Method(http.MethodPost, "/tags", apperrors.Middleware(CreateTagHandler(repos.Tags)))
+				r.Method(http.MethodPatch, "/tags/{id}", apperrors.Middleware(UpdateTagHandler(repos.Tags)))
+				r.Method(http.MethodDelete, "/tags/{id}", apperrors.Middleware(DeleteTagHandler(repos.Tags)))
+			})
+
+			// User administration
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequirePermission(auth.PermUsersManage))
+
+				r.Method(http.MethodPatch, "/users/{id}/role", apperrors.Middleware(UpdateUserRoleHandler(repos.Users)))
+			})
+
+			// Application settings
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequirePermission(auth.PermSettingsManage))
+
+				r.Method(http.MethodGet, "/settings", apperrors.Middleware(ListSettingsHandler(s.Settings)))
+				r.Method(http.MethodPut, "/settings/{key}", apperrors.Middleware(SetSettingHandler(s.Settings)))
+				r.Method(http.MethodDelete, "/settings/{key}", apperrors.Middleware(ResetSettingHandler(s.Settings)))
+			})
+		})
 	})
 
 	return r
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/search"
+)
+
+// SearchHandler handles GET /api/v1/search
+//
+// Query parameters: q (the query text), type (course, lesson or content;
+// repeated or comma-separated; default all), limit and offset.
+//
+// English words match in any inflected form. Ukrainian words do so only
+// when the database server has the hunspell uk_UA dictionary installed;
+// otherwise they match only as written.
+func SearchHandler(svc SearchService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		limit, offset, err := pageParams(r)
+		if err != nil {
+			return err
+		}
+		types, err := search.ParseTypes(listParam(r, "type"))
+		if err != nil {
+			return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid type parameter").WithDetails(map[string]any{"type": r.URL.Query()["type"]})
+		}
+
+		results, err := svc.Search(r.Context(), search.Query{
+			Text:   r.URL.Query().Get("q"),
+			Types:  types,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, results)
+	}
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/assessment"
+	"mathtermind-go/internal/auth"
+	"mathtermind-go/internal/coursepack"
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/gamification"
+	"mathtermind-go/internal/leaderboard"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/notifications"
+	"mathtermind-go/internal/progress"
+	"mathtermind-go/internal/render"
+	"mathtermind-go/internal/repository"
+	"mathtermind-go/internal/search"
+	"mathtermind-go/internal/study"
+)
+
+// Services are the dependencies the API is built from. Handlers receive
+// only the repositories and services they use, through the interfaces
+// below, so they can be tested with fakes.
+type Services struct {
+	Repos *repository.Repositories
+
+	Auth          AuthService
+	Content       ContentService
+	CoursePacks   CoursePackService
+	Render        RenderService
+	Settings      SettingsService
+	Gamification  PointsService
+	Notifications NotificationService
+	Achievements  AchievementService
+	Progress      ProgressService
+	Assessment    AssessmentService
+	Study         StudyService
+	Leaderboard   LeaderboardService
+	Search        SearchService
+	Hub           RealtimeHub
+}
+
+// AuthService registers users and issues and revokes their tokens. It is
+// implemented by *auth.Service.
+type AuthService interface {
+	// Tokens returns the manager verifying access tokens.
+	Tokens() *auth.TokenManager
+	Register(ctx context.Context, in auth.RegisterInput) (*models.User, *auth.TokenPair, error)
+	Login(ctx context.Context, login, password string) (*auth.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+// ContentService manages the contents of lessons. It is implemented by
+// *content.Service.
+type ContentService interface {
+	List(ctx context.Context, lessonID uuid.UUID) ([]models.Content, error)
+	Create(ctx context.Context, c models.Content) (*models.Content, error)
+	Update(ctx context.Context, lessonID, id uuid.UUID, p db.ContentPatch) (*models.Content, error)
+}
+
+// CoursePackService imports course packages. It is implemented by
+// *coursepack.Service.
+type CoursePackService interface {
+	Import(ctx context.Context, data []byte, opts coursepack.ImportOptions) (*coursepack.ImportResult, error)
+}
+
+// RenderService renders theory content. It is implemented by
+// *render.Service.
+type RenderService interface {
+	Contents(ctx context.Context, lessonID uuid.UUID, items []models.Content) (map[uuid.UUID]*render.Document, error)
+}
+
+// SettingsService manages the runtime settings. It is implemented by
+// *settings.Service.
+type SettingsService interface {
+	List(ctx context.Context) ([]models.Setting, error)
+	Set(ctx context.Context, key, value string, description *string) (*models.Setting, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// PointsService reports the points users earned. It is implemented by
+// *gamification.Service.
+type PointsService interface {
+	History(ctx context.Context, userID uuid.UUID, limit, offset int) (*gamification.History, error)
+}
+
+// NotificationService manages users' notifications. It is implemented by
+// *notifications.Service.
+type NotificationService interface {
+	List(ctx context.Context, userID uuid.UUID, lq notifications.ListQuery) (*notifications.Page, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) (*models.UserNotification, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID, notificationType string) (int64, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// AchievementService lists achievements. It is implemented by
+// *achievements.Service.
+type AchievementService interface {
+	List(ctx context.Context) ([]models.Achievement, error)
+	ListEarned(ctx context.Context, userID uuid.UUID) ([]models.UserAchievement, error)
+}
+
+// ProgressService records and reports learner progress. It is implemented
+// by *progress.Service.
+type ProgressService interface {
+	CompleteContent(ctx context.Context, userID, contentID uuid.UUID, timeSpentMin int) (*progress.Update, error)
+	ListProgress(ctx context.Context, userID uuid.UUID) ([]models.Progress, error)
+	GetCourseProgress(ctx context.Context, userID, courseID uuid.UUID) (*progress.CourseProgress, error)
+	SaveState(ctx context.Context, userID, contentID uuid.UUID, stateType string, value progress.StateValue) (*models.ContentState, error)
+	ListStates(ctx context.Context, userID, contentID uuid.UUID) ([]models.ContentState, error)
+	DeleteState(ctx context.Context, userID, contentID uuid.UUID, stateType string) error
+	Resume(ctx context.Context, userID uuid.UUID) (*progress.Resume, error)
+}
+
+// AssessmentService grades exercises and assessments. It is implemented
+// by *assessment.Service.
+type AssessmentService interface {
+	SubmitExerciseAnswer(ctx context.Context, userID, contentID uuid.UUID, problemID string, answer json.RawMessage) (*assessment.AnswerOutcome, error)
+	StartAttempt(ctx context.Context, userID, contentID uuid.UUID) (*assessment.StartedAttempt, error)
+	SubmitAttempt(ctx context.Context, userID, contentID, attemptID uuid.UUID, answers map[string]json.RawMessage) (*assessment.Outcome, error)
+}
+
+// StudyService tracks study sessions. It is implemented by *study.Service.
+type StudyService interface {
+	Start(ctx context.Context, userID uuid.UUID, contentID *uuid.UUID) (*models.StudySession, error)
+	Heartbeat(ctx context.Context, userID, sessionID uuid.UUID) (*models.StudySession, error)
+	End(ctx context.Context, userID, sessionID uuid.UUID) (*models.StudySession, error)
+	Stats(ctx context.Context, userID uuid.UUID, weeks int) (*study.Stats, error)
+}
+
+// LeaderboardService ranks users. It is implemented by
+// *leaderboard.Service.
+type LeaderboardService interface {
+	Get(ctx context.Context, userID uuid.UUID, q leaderboard.Query) (*leaderboard.Board, error)
+}
+
+// SearchService searches the catalog. It is implemented by
+// *search.Service.
+type SearchService interface {
+	Search(ctx context.Context, q search.Query) (*search.Results, error)
+}
+
+// RealtimeHub streams events to connected clients. It is implemented by
+// *realtime.Hub.
+type RealtimeHub interface {
+	Serve(w http.ResponseWriter, r *http.Request, userID uuid.UUID) error
+}
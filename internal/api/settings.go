@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/repository"
+)
+
+type updateSettingsRequest struct {
+	Theme                         *string `json:"theme" validate:"omitempty,oneof=light dark"`
+	NotificationDailyReminder     *bool   `json:"notification_daily_reminder"`
+	NotificationAchievementAlerts *bool   `json:"notification_achievement_alerts"`
+	NotificationStudyTime         *string `json:"notification_study_time" validate:"omitempty,hhmm"`
+	AccessibilityFontSize         *string `json:"accessibility_font_size" validate:"omitempty,oneof=small medium large"`
+	AccessibilityHighContrast     *bool   `json:"accessibility_high_contrast"`
+	StudyDailyGoalMin             *int    `json:"study_daily_goal_min" validate:"omitempty,gte=1,lte=1440"`
+	StudyPreferredSubject         *string `json:"study_preferred_subject" validate:"omitempty,oneof=MATH INFORMATICS"`
+	Timezone                      *string `json:"timezone" validate:"omitempty,max=64,timezone"`
+}
+
+// GetSettingsHandler handles GET /api/v1/me/settings
+//
+// Users who never changed their settings get the defaults, which are stored
+// on first access.
+func GetSettingsHandler(users repository.UserRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+
+		settings, err := users.Settings(r.Context(), identity.UserID)
+		if err != nil {
+			return dbError(err, "settings", identity.UserID, "failed to get settings")
+		}
+
+		return writeJSON(w, http.StatusOK, settings)
+	}
+}
+
+// UpdateSettingsHandler handles PATCH /api/v1/me/settings
+func UpdateSettingsHandler(users repository.UserRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		var req updateSettingsRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		settings, err := users.UpdateSettings(r.Context(), identity.UserID, db.UserSettingPatch{
+			Theme:                         req.Theme,
+			NotificationDailyReminder:     req.NotificationDailyReminder,
+			NotificationAchievementAlerts: req.NotificationAchievementAlerts,
+			NotificationStudyTime:         req.NotificationStudyTime,
+			AccessibilityFontSize:         req.AccessibilityFontSize,
+			AccessibilityHighContrast:     req.AccessibilityHighContrast,
+			StudyDailyGoalMin:             req.StudyDailyGoalMin,
+			StudyPreferredSubject:         req.StudyPreferredSubject,
+			Timezone:                      req.Timezone,
+		})
+		if err != nil {
+			return dbError(err, "settings", identity.UserID, "failed to update settings")
+		}
+
+		return writeJSON(w, http.StatusOK, settings)
+	}
+}
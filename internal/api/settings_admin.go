@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	apperrors "mathtermind-go/internal/errors"
+)
+
+type setSettingRequest struct {
+	Value       string  `json:"value" validate:"max=10000"`
+	Description *string `json:"description"`
+}
+
+// ListSettingsHandler handles GET /api/v1/settings
+func ListSettingsHandler(svc SettingsService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		list, err := svc.List(r.Context())
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, list)
+	}
+}
+
+// SetSettingHandler handles PUT /api/v1/settings/{key}
+//
+// Protected settings cannot be changed.
+func SetSettingHandler(svc SettingsService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var req setSettingRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		setting, err := svc.Set(r.Context(), chi.URLParam(r, "key"), req.Value, req.Description)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, setting)
+	}
+}
+
+// ResetSettingHandler handles DELETE /api/v1/settings/{key}
+//
+// Deleting a setting restores its default.
+func ResetSettingHandler(svc SettingsService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if err := svc.Reset(r.Context(), chi.URLParam(r, "key")); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
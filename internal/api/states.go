@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+
+	"mathtermind-go/internal/assessment"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/progress"
+)
+
+// stateTypePattern matches the state types clients may use, e.g.
+// "scroll_position" or "graph.viewport".
+var stateTypePattern = regexp.MustCompile(`^[a-z0-9_.-]{1,50}$`)
+
+type saveStateRequest struct {
+	NumericValue *float64     `json:"numeric_value"`
+	JSONValue    models.JSONB `json:"json_value"`
+	TextValue    *string      `json:"text_value"`
+}
+
+// stateTypeParam returns the validated {type} URL parameter.
+func stateTypeParam(r *http.Request) (string, error) {
+	v := chi.URLParam(r, "type")
+	if !stateTypePattern.MatchString(v) {
+		return "", apperrors.New(apperrors.ErrCodeValidation, "invalid type parameter").
+			WithDetails(map[string]any{"type": v, "pattern": stateTypePattern.String()})
+	}
+	return v, nil
+}
+
+// SaveContentStateHandler handles PUT /api/v1/contents/{id}/states/{type}
+func SaveContentStateHandler(svc ProgressService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		contentID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		stateType, err := stateTypeParam(r)
+		if err != nil {
+			return err
+		}
+		var req saveStateRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		state, err := svc.SaveState(r.Context(), identity.UserID, contentID, stateType, progress.StateValue{
+			NumericValue: req.NumericValue,
+			JSONValue:    req.JSONValue,
+			TextValue:    req.TextValue,
+		})
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, state)
+	}
+}
+
+// ListContentStatesHandler handles GET /api/v1/contents/{id}/states
+func ListContentStatesHandler(svc ProgressService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		contentID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		states, err := svc.ListStates(r.Context(), identity.UserID, contentID)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, states)
+	}
+}
+
+// DeleteContentStateHandler handles DELETE /api/v1/contents/{id}/states/{type}
+func DeleteContentStateHandler(svc ProgressService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		contentID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		stateType, err := stateTypeParam(r)
+		if err != nil {
+			return err
+		}
+
+		if err := svc.DeleteState(r.Context(), identity.UserID, contentID, stateType); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
+
+// ResumeHandler handles GET /api/v1/me/resume
+//
+// The content item is returned without the answer keys of its questions or
+// problems.
+func ResumeHandler(svc ProgressService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+
+		resume, err := svc.Resume(r.Context(), identity.UserID)
+		if err != nil {
+			return err
+		}
+		content := assessment.PublicContent(*resume.Content)
+		resume.Content = &content
+
+		return writeJSON(w, http.StatusOK, resume)
+	}
+}
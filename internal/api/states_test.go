@@ -0,0 +1,56 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/api"
+	"mathtermind-go/internal/auth"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/progress"
+)
+
+// fakeProgress is a ProgressService resuming at a fixed content item;
+// methods the tests do not use panic.
+type fakeProgress struct {
+	api.ProgressService
+
+	resume *progress.Resume
+}
+
+func (f *fakeProgress) Resume(context.Context, uuid.UUID) (*progress.Resume, error) {
+	return f.resume, nil
+}
+
+func TestResumeHandlerAnswerKeys(t *testing.T) {
+	svc := &fakeProgress{resume: &progress.Resume{
+		Content: &models.Content{
+			Base:        models.Base{ID: uuid.New()},
+			ContentType: models.ContentTypeExercise,
+			Exercise: &models.ExerciseContent{Problems: models.JSONB{"problems": []any{
+				map[string]any{"id": "p1", "type": "numeric", "prompt": "1/4 = ?", "value": 0.25, "tolerance": 0.01},
+				map[string]any{"id": "p2", "type": "expression", "prompt": "Simplify 2x/2", "expression": "x", "variables": []any{"x"}},
+			}}},
+		},
+		States: []models.ContentState{},
+	}}
+	learner := &auth.Identity{UserID: uuid.New(), Role: auth.RoleLearner}
+
+	rec := serve(http.MethodGet, "/me/resume", api.ResumeHandler(svc), "/me/resume", "", learner)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"prompt":"1/4 = ?"`) || !strings.Contains(body, `"variables":["x"]`) {
+		t.Errorf("body lacks the problems: %s", body)
+	}
+	for _, key := range []string{`"value":`, `"tolerance":`, `"expression":`} {
+		if strings.Contains(body, key) {
+			t.Errorf("body has the answer key %s: %s", key, body)
+		}
+	}
+}
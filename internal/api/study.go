@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	apperrors "mathtermind-go/internal/errors"
+)
+
+type startStudyRequest struct {
+	ContentID *uuid.UUID `json:"content_id"`
+}
+
+// StartStudySessionHandler handles POST /api/v1/me/study-sessions
+func StartStudySessionHandler(svc StudyService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		var req startStudyRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		session, err := svc.Start(r.Context(), identity.UserID, req.ContentID)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusCreated, session)
+	}
+}
+
+// StudyHeartbeatHandler handles POST /api/v1/me/study-sessions/{id}/heartbeat
+func StudyHeartbeatHandler(svc StudyService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		session, err := svc.Heartbeat(r.Context(), identity.UserID, id)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, session)
+	}
+}
+
+// EndStudySessionHandler handles POST /api/v1/me/study-sessions/{id}/end
+func EndStudySessionHandler(svc StudyService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		session, err := svc.End(r.Context(), identity.UserID, id)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, session)
+	}
+}
+
+// StudyStatsHandler handles GET /api/v1/me/study-stats
+//
+// The weeks query parameter selects how many weeks of daily totals are
+// returned (default 4, at most 52).
+func StudyStatsHandler(svc StudyService) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		identity, err := currentUser(r)
+		if err != nil {
+			return err
+		}
+		weeks := 4
+		if v := r.URL.Query().Get("weeks"); v != "" {
+			wv, err := strconv.Atoi(v)
+			if err != nil || wv <= 0 || wv > 52 {
+				return apperrors.Errorf(apperrors.ErrCodeValidation, "invalid weeks parameter").WithDetails(map[string]any{"weeks": v})
+			}
+			weeks = wv
+		}
+
+		stats, err := svc.Stats(r.Context(), identity.UserID, weeks)
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(w, http.StatusOK, stats)
+	}
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/repository"
+)
+
+// defaultTagCategory mirrors the tags.category column default.
+const defaultTagCategory = "TOPIC"
+
+type createTagRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Category string `json:"category" validate:"max=50"`
+}
+
+type updateTagRequest struct {
+	Name     *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Category *string `json:"category" validate:"omitempty,min=1,max=50"`
+}
+
+// tagError converts a tag write error into an API error.
+func tagError(err error, id any, message string) error {
+	if errors.Is(err, db.ErrTagNameTaken) {
+		return apperrors.Validation("Validation failed", map[string]any{
+			"errors": map[string]string{"name": err.Error()},
+		})
+	}
+	return dbError(err, "tag", id, message)
+}
+
+// ListTagsHandler handles GET /api/v1/tags
+//
+// Query parameters: category.
+func ListTagsHandler(tags repository.TagRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		list, err := tags.List(r.Context(), r.URL.Query().Get("category"))
+		if err != nil {
+			return apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to list tags")
+		}
+
+		return writeJSON(w, http.StatusOK, map[string]any{"items": list})
+	}
+}
+
+// CreateTagHandler handles POST /api/v1/tags
+func CreateTagHandler(tags repository.TagRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var req createTagRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+		if req.Category == "" {
+			req.Category = defaultTagCategory
+		}
+
+		tag, err := tags.Create(r.Context(), req.Name, req.Category)
+		if err != nil {
+			return tagError(err, nil, "failed to create tag")
+		}
+
+		return writeJSON(w, http.StatusCreated, tag)
+	}
+}
+
+// UpdateTagHandler handles PATCH /api/v1/tags/{id}
+func UpdateTagHandler(tags repository.TagRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		var req updateTagRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		tag, err := tags.Update(r.Context(), id, req.Name, req.Category)
+		if err != nil {
+			return tagError(err, id, "failed to update tag")
+		}
+
+		return writeJSON(w, http.StatusOK, tag)
+	}
+}
+
+// DeleteTagHandler handles DELETE /api/v1/tags/{id}
+func DeleteTagHandler(tags repository.TagRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+
+		if err := tags.Delete(r.Context(), id); err != nil {
+			return dbError(err, "tag", id, "failed to delete tag")
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
+
+// AttachTagHandler handles PUT /api/v1/courses/{id}/tags/{tagID} and
+// responds with the course tags.
+func AttachTagHandler(courses repository.CourseRepository, tags repository.TagRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		courseID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		tagID, err := uuidParam(r, "tagID")
+		if err != nil {
+			return err
+		}
+		if err := authorizeCourseEdit(r, courses, courseID); err != nil {
+			return err
+		}
+		if _, err := tags.Get(r.Context(), tagID); err != nil {
+			return dbError(err, "tag", tagID, "failed to get tag")
+		}
+
+		if err := tags.Attach(r.Context(), courseID, tagID); err != nil {
+			return dbError(err, "course", courseID, "failed to attach tag")
+		}
+		attached, err := tags.ListByCourse(r.Context(), courseID)
+		if err != nil {
+			return apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to list course tags")
+		}
+
+		return writeJSON(w, http.StatusOK, map[string]any{"items": attached})
+	}
+}
+
+// DetachTagHandler handles DELETE /api/v1/courses/{id}/tags/{tagID}
+func DetachTagHandler(courses repository.CourseRepository, tags repository.TagRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		courseID, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		tagID, err := uuidParam(r, "tagID")
+		if err != nil {
+			return err
+		}
+		if err := authorizeCourseEdit(r, courses, courseID); err != nil {
+			return err
+		}
+
+		if err := tags.Detach(r.Context(), courseID, tagID); err != nil {
+			return dbError(err, "tag", tagID, "failed to detach tag")
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/repository"
+)
+
+type updateRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=learner author teacher admin"`
+}
+
+// UpdateUserRoleHandler handles PATCH /api/v1/users/{id}/role
+//
+// The new role applies to access tokens issued after the change.
+func UpdateUserRoleHandler(users repository.UserRepository) apperrors.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id, err := uuidParam(r, "id")
+		if err != nil {
+			return err
+		}
+		var req updateRoleRequest
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		user, err := users.UpdateRole(r.Context(), id, req.Role)
+		if err != nil {
+			return dbError(err, "user", id, "failed to update user role")
+		}
+
+		return writeJSON(w, http.StatusOK, user)
+	}
+}
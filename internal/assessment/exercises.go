@@ -0,0 +1,130 @@
+package assessment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/achievements"
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/gamification"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/progress"
+)
+
+// AnswerOutcome is the graded result of an exercise answer.
+type AnswerOutcome struct {
+	Answer *models.UserAnswer `json:"answer"`
+	Result Result             `json:"result"`
+	// Award is set when the answer earned points.
+	Award *gamification.Award `json:"award,omitempty"`
+	// Achievements lists the achievements earned by answering.
+	Achievements []achievements.Earned `json:"achievements,omitempty"`
+	// Progress is set when this answer completed the exercise.
+	Progress *progress.Update `json:"progress,omitempty"`
+}
+
+// SubmitExerciseAnswer grades an answer to one problem of an exercise and
+// records it. Exercises may be retried freely, but points are only awarded
+// for the first correct answer to each problem. The exercise is completed
+// once every problem has been answered correctly.
+func (s *Service) SubmitExerciseAnswer(ctx context.Context, userID, contentID uuid.UUID, problemID string, answer json.RawMessage) (*AnswerOutcome, error) {
+	var outcome *AnswerOutcome
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		content, err := db.GetContent(ctx, tx, contentID)
+		if errors.Is(err, db.ErrNotFound) || (err == nil && content.Exercise == nil) {
+			return apperrors.NotFound("exercise", contentID)
+		}
+		if err != nil {
+			return err
+		}
+
+		problems, err := ParseProblems(content.Exercise.Problems)
+		if err != nil {
+			return apperrors.Wrap(err, apperrors.ErrCodeInvalidState, "exercise problems are invalid")
+		}
+		var problem *Question
+		for i := range problems {
+			if problems[i].ID == problemID {
+				problem = &problems[i]
+				break
+			}
+		}
+		if problem == nil {
+			return apperrors.NotFound("problem", problemID)
+		}
+
+		res, err := Grade(*problem, answer)
+		if err != nil {
+			return apperrors.Wrap(err, apperrors.ErrCodeInvalidState, "exercise problems are invalid")
+		}
+
+		// Locking the progress rows serializes answers by the same user, so
+		// two concurrent correct answers cannot both earn points.
+		if err := lockCourseProgress(ctx, tx, userID, contentID); err != nil {
+			return err
+		}
+		contentProgress, err := db.LockContentProgress(ctx, tx, userID, contentID)
+		if err != nil {
+			return err
+		}
+		if res.Correct {
+			answered, err := db.HasCorrectAnswer(ctx, tx, userID, contentID, problemID)
+			if err != nil {
+				return err
+			}
+			if answered {
+				res.Points = 0
+			}
+		}
+
+		ua := &models.UserAnswer{
+			UserID:       userID,
+			ContentID:    contentID,
+			QuestionID:   problemID,
+			AnswerData:   models.JSONB{"answer": answer},
+			IsCorrect:    res.Correct,
+			PointsEarned: res.Points,
+		}
+		if err := db.InsertUserAnswer(ctx, tx, ua); err != nil {
+			return err
+		}
+		if err := db.IncrementContentAttempts(ctx, tx, userID, contentID); err != nil {
+			return err
+		}
+
+		outcome = &AnswerOutcome{Answer: ua, Result: res}
+		if outcome.Award, err = s.credit(ctx, tx, userID, ua); err != nil {
+			return err
+		}
+		outcome.Achievements, err = s.evaluate(ctx, tx, achievements.EventExerciseAnswered, userID, contentID, 0)
+		if err != nil {
+			return err
+		}
+		if !res.Correct || contentProgress.IsCompleted {
+			return nil
+		}
+
+		ids := make([]string, len(problems))
+		for i, p := range problems {
+			ids[i] = p.ID
+		}
+		solved, err := db.CountCorrectlyAnswered(ctx, tx, userID, contentID, ids)
+		if err != nil {
+			return err
+		}
+		if solved < len(problems) {
+			return nil
+		}
+		outcome.Progress, err = s.progress.Complete(db.ContextWithTx(ctx, tx), userID, content, 0)
+		return err
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to submit answer")
+	}
+	return outcome, nil
+}
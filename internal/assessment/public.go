@@ -0,0 +1,48 @@
+package assessment
+
+import "mathtermind-go/internal/models"
+
+// PublicContent returns a content item as shown to learners: the questions
+// of an assessment and the problems of an exercise lose their answer keys.
+// Items that cannot be decoded are dropped rather than shown.
+func PublicContent(c models.Content) models.Content {
+	if c.Assessment != nil {
+		a := *c.Assessment
+		a.Questions = publicItems(a.Questions, "questions", ParseQuestions)
+		c.Assessment = &a
+	}
+	if c.Exercise != nil {
+		e := *c.Exercise
+		e.Problems = publicItems(e.Problems, "problems", ParseProblems)
+		c.Exercise = &e
+	}
+	return c
+}
+
+// PublicCourse returns a course with PublicContent applied to the contents
+// of its lessons.
+func PublicCourse(c models.Course) models.Course {
+	lessons := make([]models.Lesson, len(c.Lessons))
+	for i, l := range c.Lessons {
+		contents := make([]models.Content, len(l.Contents))
+		for j, item := range l.Contents {
+			contents[j] = PublicContent(item)
+		}
+		l.Contents = contents
+		lessons[i] = l
+	}
+	c.Lessons = lessons
+	return c
+}
+
+func publicItems(data models.JSONB, key string, parse func(models.JSONB) ([]Question, error)) models.JSONB {
+	if data == nil {
+		return nil
+	}
+	items, _ := parse(data)
+	public := make([]PublicQuestion, len(items))
+	for i, q := range items {
+		public[i] = q.Public()
+	}
+	return models.JSONB{key: public}
+}
@@ -0,0 +1,243 @@
+package assessment
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"mathtermind-go/internal/mathexpr"
+	"mathtermind-go/internal/models"
+)
+
+// QuestionType identifies how a question is answered and graded.
+type QuestionType string
+
+const (
+	QuestionSingleChoice   QuestionType = "single_choice"
+	QuestionMultipleChoice QuestionType = "multiple_choice"
+	QuestionNumeric        QuestionType = "numeric"
+	QuestionShortText      QuestionType = "short_text"
+	// QuestionExpression accepts any expression mathematically equivalent
+	// to Expression over the declared Variables.
+	QuestionExpression QuestionType = "expression"
+)
+
+// Option is a choice offered by a choice question.
+type Option struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Question is a single question of an assessment or problem of an
+// exercise. Questions are stored in AssessmentContent.Questions as
+// {"questions": [...]} and problems in ExerciseContent.Problems as
+// {"problems": [...]}.
+//
+// The answer key depends on the type: Correct lists the correct option IDs
+// of choice questions, Value and Tolerance define numeric answers, Accepted
+// lists the accepted short text answers, and Expression with Variables
+// defines expression answers.
+type Question struct {
+	ID      string       `json:"id"`
+	Type    QuestionType `json:"type"`
+	Prompt  string       `json:"prompt"`
+	Options []Option     `json:"options,omitempty"`
+	Points  int          `json:"points,omitempty"`
+
+	Correct       []string `json:"correct,omitempty"`
+	Value         *float64 `json:"value,omitempty"`
+	Tolerance     float64  `json:"tolerance,omitempty"`
+	Accepted      []string `json:"accepted,omitempty"`
+	CaseSensitive bool     `json:"case_sensitive,omitempty"`
+	Expression    string   `json:"expression,omitempty"`
+	Variables     []string `json:"variables,omitempty"`
+}
+
+// PublicQuestion is a question as shown to a learner, without its answer key.
+type PublicQuestion struct {
+	ID        string       `json:"id"`
+	Type      QuestionType `json:"type"`
+	Prompt    string       `json:"prompt"`
+	Options   []Option     `json:"options,omitempty"`
+	Variables []string     `json:"variables,omitempty"`
+	Points    int          `json:"points"`
+}
+
+// Public strips the answer key from the question.
+func (q Question) Public() PublicQuestion {
+	return PublicQuestion{
+		ID:        q.ID,
+		Type:      q.Type,
+		Prompt:    q.Prompt,
+		Options:   q.Options,
+		Variables: q.Variables,
+		Points:    q.points(),
+	}
+}
+
+// points returns the points awarded for a correct answer, defaulting to 1.
+func (q Question) points() int {
+	if q.Points <= 0 {
+		return 1
+	}
+	return q.Points
+}
+
+// ParseQuestions decodes the questions of an assessment.
+func ParseQuestions(data models.JSONB) ([]Question, error) {
+	return parseItems(data, "questions")
+}
+
+// ParseProblems decodes the problems of an exercise. Problems without a
+// type are expression problems.
+func ParseProblems(data models.JSONB) ([]Question, error) {
+	problems, err := parseItems(data, "problems")
+	if err != nil {
+		return nil, err
+	}
+	for i := range problems {
+		if problems[i].Type == "" {
+			problems[i].Type = QuestionExpression
+		}
+	}
+	return problems, nil
+}
+
+func parseItems(data models.JSONB, key string) ([]Question, error) {
+	raw, err := json.Marshal(data[key])
+	if err != nil {
+		return nil, err
+	}
+	var items []Question
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	seen := make(map[string]bool, len(items))
+	for _, q := range items {
+		if q.ID == "" {
+			return nil, fmt.Errorf("%s item without id", key)
+		}
+		if seen[q.ID] {
+			return nil, fmt.Errorf("duplicate %s id %q", key, q.ID)
+		}
+		seen[q.ID] = true
+	}
+	return items, nil
+}
+
+// Result is the outcome of grading one answer.
+type Result struct {
+	QuestionID string `json:"question_id"`
+	Correct    bool   `json:"correct"`
+	Points     int    `json:"points"`
+}
+
+// Grade grades an answer to the question. A missing or malformed answer is
+// graded as incorrect; an error is only returned for an invalid question.
+func Grade(q Question, answer json.RawMessage) (Result, error) {
+	res := Result{QuestionID: q.ID}
+	var correct bool
+
+	switch q.Type {
+	case QuestionSingleChoice:
+		if len(q.Correct) != 1 {
+			return res, fmt.Errorf("question %q must have exactly one correct option", q.ID)
+		}
+		var choice string
+		if json.Unmarshal(answer, &choice) == nil {
+			correct = choice == q.Correct[0]
+		}
+	case QuestionMultipleChoice:
+		var choices []string
+		if json.Unmarshal(answer, &choices) == nil {
+			correct = sameSet(choices, q.Correct)
+		}
+	case QuestionNumeric:
+		if q.Value == nil {
+			return res, fmt.Errorf("question %q has no numeric value", q.ID)
+		}
+		if v, ok := parseNumber(answer); ok {
+			correct = math.Abs(v-*q.Value) <= math.Abs(q.Tolerance)
+		}
+	case QuestionShortText:
+		var text string
+		if json.Unmarshal(answer, &text) == nil {
+			correct = matchText(text, q.Accepted, q.CaseSensitive)
+		}
+	case QuestionExpression:
+		expected, err := mathexpr.Parse(q.Expression)
+		if err != nil {
+			return res, fmt.Errorf("question %q has an invalid expression: %w", q.ID, err)
+		}
+		if given, ok := parseExpression(answer); ok {
+			// Undeclared variables in the answer make it incorrect.
+			correct, _ = mathexpr.Equivalent(expected, given, q.Variables)
+		}
+	default:
+		return res, fmt.Errorf("question %q has unsupported type %q", q.ID, q.Type)
+	}
+
+	res.Correct = correct
+	if correct {
+		res.Points = q.points()
+	}
+	return res, nil
+}
+
+// parseNumber accepts a JSON number or a numeric string, with either a
+// decimal point or a comma as separator.
+func parseNumber(answer json.RawMessage) (float64, bool) {
+	var v float64
+	if json.Unmarshal(answer, &v) == nil {
+		return v, true
+	}
+	var s string
+	if json.Unmarshal(answer, &s) != nil {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+	return v, err == nil
+}
+
+// parseExpression accepts an expression string or a plain JSON number.
+func parseExpression(answer json.RawMessage) (mathexpr.Node, bool) {
+	var text string
+	if json.Unmarshal(answer, &text) != nil {
+		var v float64
+		if json.Unmarshal(answer, &v) != nil {
+			return nil, false
+		}
+		return mathexpr.Number{Value: v}, true
+	}
+	n, err := mathexpr.Parse(text)
+	return n, err == nil
+}
+
+func matchText(text string, accepted []string, caseSensitive bool) bool {
+	text = strings.Join(strings.Fields(text), " ")
+	for _, a := range accepted {
+		a = strings.Join(strings.Fields(a), " ")
+		if text == a || (!caseSensitive && strings.EqualFold(text, a)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameSet(a, b []string) bool {
+	set := make(map[string]bool, len(b))
+	for _, v := range b {
+		set[v] = true
+	}
+	seen := make(map[string]bool, len(a))
+	for _, v := range a {
+		if !set[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return len(seen) == len(set)
+}
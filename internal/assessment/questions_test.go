@@ -0,0 +1,110 @@
+package assessment_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"mathtermind-go/internal/assessment"
+)
+
+func TestGrade(t *testing.T) {
+	pi := 3.14159
+	tests := []struct {
+		name     string
+		question assessment.Question
+		answer   string
+		want     bool
+	}{
+		{
+			name:     "single choice correct",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionSingleChoice, Correct: []string{"b"}},
+			answer:   `"b"`,
+			want:     true,
+		},
+		{
+			name:     "single choice wrong",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionSingleChoice, Correct: []string{"b"}},
+			answer:   `"a"`,
+		},
+		{
+			name:     "multiple choice in any order",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionMultipleChoice, Correct: []string{"a", "c"}},
+			answer:   `["c", "a"]`,
+			want:     true,
+		},
+		{
+			name:     "multiple choice missing option",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionMultipleChoice, Correct: []string{"a", "c"}},
+			answer:   `["a"]`,
+		},
+		{
+			name:     "multiple choice extra option",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionMultipleChoice, Correct: []string{"a", "c"}},
+			answer:   `["a", "b", "c"]`,
+		},
+		{
+			name:     "numeric within tolerance",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionNumeric, Value: &pi, Tolerance: 0.01},
+			answer:   `3.14`,
+			want:     true,
+		},
+		{
+			name:     "numeric string with comma",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionNumeric, Value: &pi, Tolerance: 0.01},
+			answer:   `"3,14"`,
+			want:     true,
+		},
+		{
+			name:     "numeric outside tolerance",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionNumeric, Value: &pi, Tolerance: 0.001},
+			answer:   `3.14`,
+		},
+		{
+			name:     "short text ignores case and spacing",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionShortText, Accepted: []string{"Pythagorean theorem"}},
+			answer:   `"  pythagorean   THEOREM "`,
+			want:     true,
+		},
+		{
+			name:     "short text case sensitive",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionShortText, Accepted: []string{"NaCl"}, CaseSensitive: true},
+			answer:   `"nacl"`,
+		},
+		{
+			name:     "expression equivalent",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionExpression, Expression: "2x+2", Variables: []string{"x"}},
+			answer:   `"2(x+1)"`,
+			want:     true,
+		},
+		{
+			name:     "expression not equivalent",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionExpression, Expression: "2x+2", Variables: []string{"x"}},
+			answer:   `"2x+1"`,
+		},
+		{
+			name:     "expression unparsable",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionExpression, Expression: "2x+2", Variables: []string{"x"}},
+			answer:   `"2x+"`,
+		},
+		{
+			name:     "missing answer",
+			question: assessment.Question{ID: "q", Type: assessment.QuestionSingleChoice, Correct: []string{"b"}},
+			answer:   ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := assessment.Grade(tt.question, json.RawMessage(tt.answer))
+			if err != nil {
+				t.Fatalf("Grade() error = %v", err)
+			}
+			if res.Correct != tt.want {
+				t.Errorf("Grade() correct = %v, want %v", res.Correct, tt.want)
+			}
+			if tt.want && res.Points != 1 {
+				t.Errorf("Grade() points = %d, want 1", res.Points)
+			}
+		})
+	}
+}
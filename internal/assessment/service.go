@@ -0,0 +1,278 @@
+package assessment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/achievements"
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/gamification"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/progress"
+)
+
+// submitGrace tolerates submissions that arrive shortly after the deadline
+// because of network latency.
+const submitGrace = 5 * time.Second
+
+// StartedAttempt is an attempt together with the questions to answer.
+type StartedAttempt struct {
+	Attempt         *models.AssessmentAttempt `json:"attempt"`
+	Questions       []PublicQuestion          `json:"questions"`
+	AttemptsAllowed int                       `json:"attempts_allowed"`
+	// Resumed is true when an attempt that was already in progress is returned.
+	Resumed bool `json:"resumed"`
+}
+
+// Outcome is the graded result of a submitted attempt.
+type Outcome struct {
+	Attempt      *models.AssessmentAttempt `json:"attempt"`
+	Results      []Result                  `json:"results"`
+	Score        float64                   `json:"score"`
+	Passed       bool                      `json:"passed"`
+	PointsEarned int                       `json:"points_earned"`
+	// Awards lists the points credited for questions answered correctly
+	// for the first time.
+	Awards []*gamification.Award `json:"awards,omitempty"`
+	// Achievements lists the achievements earned by the score.
+	Achievements []achievements.Earned `json:"achievements,omitempty"`
+	// Progress is set when the attempt passed and completed the assessment.
+	Progress *progress.Update `json:"progress,omitempty"`
+}
+
+// Service starts and grades assessment attempts.
+type Service struct {
+	pool         *pgxpool.Pool
+	progress     *progress.Service
+	gamification *gamification.Service
+	achievements *achievements.Service
+}
+
+// NewService creates an assessment service. Completed exercises and passed
+// assessments are recorded through tracker, correct answers are credited
+// through awards and answers are evaluated against achievementSvc.
+func NewService(pool *pgxpool.Pool, tracker *progress.Service, awards *gamification.Service, achievementSvc *achievements.Service) *Service {
+	return &Service{pool: pool, progress: tracker, gamification: awards, achievements: achievementSvc}
+}
+
+// evaluate evaluates an answer event against achievements.
+func (s *Service) evaluate(ctx context.Context, tx pgx.Tx, t achievements.EventType, userID, contentID uuid.UUID, score float64) ([]achievements.Earned, error) {
+	courseID, err := db.GetContentCourseID(ctx, tx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.achievements.Evaluate(db.ContextWithTx(ctx, tx), achievements.Event{
+		Type:     t,
+		UserID:   userID,
+		CourseID: &courseID,
+		Score:    score,
+		At:       time.Now(),
+	})
+}
+
+// lockCourseProgress locks the user's progress row of the content item's
+// course. Answers take it before any other lock, in the lock order of
+// db.LockCourseProgress, since grading them may credit points and complete
+// the item.
+func lockCourseProgress(ctx context.Context, tx pgx.Tx, userID, contentID uuid.UUID) error {
+	courseID, err := db.GetContentCourseID(ctx, tx, contentID)
+	if errors.Is(err, db.ErrNotFound) {
+		return apperrors.NotFound("content", contentID)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = db.LockCourseProgress(ctx, tx, userID, courseID)
+	return err
+}
+
+// credit awards the points of a correct answer. Each question is credited
+// only once per user, however often it is answered correctly.
+func (s *Service) credit(ctx context.Context, tx pgx.Tx, userID uuid.UUID, a *models.UserAnswer) (*gamification.Award, error) {
+	if !a.IsCorrect {
+		return nil, nil
+	}
+	courseID, err := db.GetContentCourseID(ctx, tx, a.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.gamification.Credit(db.ContextWithTx(ctx, tx), gamification.CorrectAnswer(userID, courseID, a))
+}
+
+// loadAssessment returns an assessment content item and its parsed questions.
+func loadAssessment(ctx context.Context, q db.Querier, contentID uuid.UUID) (*models.Content, []Question, error) {
+	content, err := db.GetContent(ctx, q, contentID)
+	if errors.Is(err, db.ErrNotFound) || (err == nil && content.Assessment == nil) {
+		return nil, nil, apperrors.NotFound("assessment", contentID)
+	}
+	if err != nil {
+		return nil, nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to get assessment")
+	}
+
+	questions, err := ParseQuestions(content.Assessment.Questions)
+	if err != nil {
+		return nil, nil, apperrors.Wrap(err, apperrors.ErrCodeInvalidState, "assessment questions are invalid")
+	}
+	if len(questions) == 0 {
+		return nil, nil, apperrors.New(apperrors.ErrCodeInvalidState, "assessment has no questions")
+	}
+	return content, questions, nil
+}
+
+// StartAttempt starts a timed attempt, or returns the attempt the user
+// already has in progress. It fails with ErrCodeInvalidState once all
+// allowed attempts have been used.
+func (s *Service) StartAttempt(ctx context.Context, userID, contentID uuid.UUID) (*StartedAttempt, error) {
+	var started *StartedAttempt
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		content, questions, err := loadAssessment(ctx, tx, contentID)
+		if err != nil {
+			return err
+		}
+		assessment := content.Assessment
+
+		// Locking the progress row serializes concurrent starts by the same user.
+		if _, err := db.LockContentProgress(ctx, tx, userID, contentID); err != nil {
+			return err
+		}
+
+		public := make([]PublicQuestion, len(questions))
+		for i, q := range questions {
+			public[i] = q.Public()
+		}
+		started = &StartedAttempt{Questions: public, AttemptsAllowed: assessment.AttemptsAllowed}
+
+		open, err := db.GetOpenAttempt(ctx, tx, userID, contentID)
+		if err == nil {
+			started.Attempt = open
+			started.Resumed = true
+			return nil
+		}
+		if !errors.Is(err, db.ErrNotFound) {
+			return err
+		}
+
+		used, err := db.CountAttempts(ctx, tx, userID, contentID)
+		if err != nil {
+			return err
+		}
+		if assessment.AttemptsAllowed > 0 && used >= assessment.AttemptsAllowed {
+			return apperrors.New(apperrors.ErrCodeInvalidState, "No attempts left for this assessment").
+				WithDetails(map[string]any{"attempts_allowed": assessment.AttemptsAllowed, "attempts_used": used})
+		}
+
+		var deadline *time.Time
+		if assessment.TimeLimit != nil && *assessment.TimeLimit > 0 {
+			d := time.Now().Add(time.Duration(*assessment.TimeLimit) * time.Minute)
+			deadline = &d
+		}
+
+		if started.Attempt, err = db.CreateAttempt(ctx, tx, userID, contentID, used+1, deadline); err != nil {
+			return err
+		}
+		return db.IncrementContentAttempts(ctx, tx, userID, contentID)
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to start attempt")
+	}
+	return started, nil
+}
+
+// SubmitAttempt grades the answers of an attempt, keyed by question ID, and
+// records them. Attempts that were already submitted or whose deadline has
+// passed are rejected with ErrCodeInvalidState.
+func (s *Service) SubmitAttempt(ctx context.Context, userID, contentID, attemptID uuid.UUID, answers map[string]json.RawMessage) (*Outcome, error) {
+	var outcome *Outcome
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if err := lockCourseProgress(ctx, tx, userID, contentID); err != nil {
+			return err
+		}
+		attempt, err := db.LockAttempt(ctx, tx, attemptID)
+		if errors.Is(err, db.ErrNotFound) || (err == nil && (attempt.UserID != userID || attempt.ContentID != contentID)) {
+			return apperrors.NotFound("attempt", attemptID)
+		}
+		if err != nil {
+			return err
+		}
+		if attempt.SubmittedAt != nil {
+			return apperrors.New(apperrors.ErrCodeInvalidState, "Attempt has already been submitted")
+		}
+		if attempt.DeadlineAt != nil && time.Now().After(attempt.DeadlineAt.Add(submitGrace)) {
+			return apperrors.New(apperrors.ErrCodeInvalidState, "Attempt deadline has passed").
+				WithDetails(map[string]any{"deadline_at": attempt.DeadlineAt})
+		}
+
+		content, questions, err := loadAssessment(ctx, tx, contentID)
+		if err != nil {
+			return err
+		}
+
+		outcome = &Outcome{Results: make([]Result, 0, len(questions))}
+		total := 0
+		for _, q := range questions {
+			answer := answers[q.ID]
+			res, err := Grade(q, answer)
+			if err != nil {
+				return apperrors.Wrap(err, apperrors.ErrCodeInvalidState, "assessment questions are invalid")
+			}
+			total += q.points()
+			outcome.PointsEarned += res.Points
+			outcome.Results = append(outcome.Results, res)
+
+			if answer == nil {
+				answer = json.RawMessage("null")
+			}
+			ua := &models.UserAnswer{
+				UserID:       userID,
+				ContentID:    contentID,
+				QuestionID:   q.ID,
+				AnswerData:   models.JSONB{"answer": answer},
+				IsCorrect:    res.Correct,
+				PointsEarned: res.Points,
+				AttemptID:    &attempt.ID,
+			}
+			if err := db.InsertUserAnswer(ctx, tx, ua); err != nil {
+				return err
+			}
+			award, err := s.credit(ctx, tx, userID, ua)
+			if err != nil {
+				return err
+			}
+			if award != nil {
+				outcome.Awards = append(outcome.Awards, award)
+			}
+		}
+
+		outcome.Score = float64(outcome.PointsEarned) / float64(total) * 100
+		outcome.Passed = outcome.Score >= content.Assessment.PassingScore
+
+		if outcome.Attempt, err = db.FinishAttempt(ctx, tx, attempt.ID, outcome.Score, outcome.Passed); err != nil {
+			return err
+		}
+		if err := db.RecordContentScore(ctx, tx, userID, contentID, outcome.Score); err != nil {
+			return err
+		}
+		outcome.Achievements, err = s.evaluate(ctx, tx, achievements.EventAssessmentSubmitted, userID, contentID, outcome.Score)
+		if err != nil {
+			return err
+		}
+		if !outcome.Passed {
+			return nil
+		}
+
+		spent := int(outcome.Attempt.SubmittedAt.Sub(attempt.StartedAt).Minutes())
+		outcome.Progress, err = s.progress.Complete(db.ContextWithTx(ctx, tx), userID, content, spent)
+		return err
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to submit attempt")
+	}
+	return outcome, nil
+}
@@ -0,0 +1,59 @@
+package auth
+
+// Role is the role of a user, stored in users.role.
+type Role string
+
+const (
+	RoleLearner Role = "learner"
+	RoleAuthor  Role = "author"
+	RoleTeacher Role = "teacher"
+	RoleAdmin   Role = "admin"
+)
+
+// Permission is an action a role may perform.
+type Permission string
+
+const (
+	// PermLearn allows taking courses: answering, tracking progress and so on.
+	PermLearn Permission = "learn"
+	// PermCoursesWrite allows creating courses and editing the ones you created.
+	PermCoursesWrite Permission = "courses:write"
+	// PermCoursesManage allows editing any course regardless of its owner.
+	PermCoursesManage Permission = "courses:manage"
+	// PermProgressView allows viewing the progress of other learners.
+	PermProgressView Permission = "progress:view"
+	// PermUsersManage allows changing user roles.
+	PermUsersManage Permission = "users:manage"
+	// PermSettingsManage allows editing application settings.
+	PermSettingsManage Permission = "settings:manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleLearner: {PermLearn},
+	RoleAuthor:  {PermLearn, PermCoursesWrite},
+	RoleTeacher: {PermLearn, PermCoursesWrite, PermProgressView},
+	RoleAdmin: {
+		PermLearn,
+		PermCoursesWrite,
+		PermCoursesManage,
+		PermProgressView,
+		PermUsersManage,
+		PermSettingsManage,
+	},
+}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants the permission.
+func (r Role) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
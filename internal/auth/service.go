@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/repository"
+)
+
+// TokenPair is returned to clients after a successful login or refresh.
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// RegisterInput holds the data needed to create an account.
+type RegisterInput struct {
+	Username  string
+	Email     string
+	Password  string
+	FirstName *string
+	LastName  *string
+	AgeGroup  string
+}
+
+// Service implements registration, login and token rotation.
+type Service struct {
+	repos      *repository.Repositories
+	tokens     *TokenManager
+	refreshTTL time.Duration
+}
+
+// NewService creates an authentication service storing users and their
+// refresh tokens in repos.
+func NewService(repos *repository.Repositories, tokens *TokenManager, refreshTTL time.Duration) *Service {
+	return &Service{repos: repos, tokens: tokens, refreshTTL: refreshTTL}
+}
+
+// Tokens returns the manager used to verify access tokens.
+func (s *Service) Tokens() *TokenManager {
+	return s.tokens
+}
+
+// Register creates a new user and signs them in.
+func (s *Service) Register(ctx context.Context, in RegisterInput) (*models.User, *TokenPair, error) {
+	hash, err := HashPassword(in.Password)
+	if err != nil {
+		return nil, nil, apperrors.Internal("failed to hash password", err)
+	}
+
+	user, err := s.repos.Users.Create(ctx, models.User{
+		Username:     in.Username,
+		Email:        strings.ToLower(in.Email),
+		PasswordHash: hash,
+		FirstName:    in.FirstName,
+		LastName:     in.LastName,
+		AgeGroup:     in.AgeGroup,
+	})
+	switch {
+	case errors.Is(err, db.ErrUsernameTaken):
+		return nil, nil, apperrors.Validation("Validation failed", map[string]any{
+			"errors": map[string]string{"username": err.Error()},
+		})
+	case errors.Is(err, db.ErrEmailTaken):
+		return nil, nil, apperrors.Validation("Validation failed", map[string]any{
+			"errors": map[string]string{"email": err.Error()},
+		})
+	case err != nil:
+		return nil, nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to create user")
+	}
+
+	pair, err := s.issue(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, pair, nil
+}
+
+// Login verifies a username or email and password and issues a token pair.
+// Logins containing @ are emails, since usernames cannot contain it.
+func (s *Service) Login(ctx context.Context, login, password string) (*TokenPair, error) {
+	var user *models.User
+	var err error
+	if strings.Contains(login, "@") {
+		user, err = s.repos.Users.GetByEmail(ctx, login)
+	} else {
+		user, err = s.repos.Users.GetByUsername(ctx, login)
+	}
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, apperrors.New(apperrors.ErrCodeLoginError, "Invalid login or password")
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to look up user")
+	}
+
+	ok, err := CheckPassword(user.PasswordHash, password)
+	if err != nil {
+		return nil, apperrors.Internal("failed to verify password", err)
+	}
+	if !ok {
+		return nil, apperrors.New(apperrors.ErrCodeLoginError, "Invalid login or password")
+	}
+
+	return s.issue(ctx, user)
+}
+
+// Refresh exchanges a refresh token for a new token pair. The presented
+// token is revoked; presenting it again revokes the whole token family.
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	newToken, err := newRefreshToken()
+	if err != nil {
+		return nil, apperrors.Internal("failed to generate refresh token", err)
+	}
+	refreshExpiresAt := time.Now().Add(s.refreshTTL)
+
+	userID, err := s.repos.Sessions.Rotate(ctx, hashRefreshToken(refreshToken), hashRefreshToken(newToken), refreshExpiresAt)
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return nil, apperrors.New(apperrors.ErrCodeTokenError, "Invalid refresh token")
+	case errors.Is(err, db.ErrRefreshTokenExpired), errors.Is(err, db.ErrRefreshTokenReused):
+		return nil, apperrors.New(apperrors.ErrCodeTokenError, err.Error())
+	case err != nil:
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to rotate refresh token")
+	}
+
+	user, err := s.repos.Users.Get(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to look up user")
+	}
+
+	access, expiresAt, err := s.tokens.IssueAccessToken(user)
+	if err != nil {
+		return nil, apperrors.Internal("failed to sign access token", err)
+	}
+	return &TokenPair{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshToken:     newToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// Logout revokes the session the refresh token belongs to.
+func (s *Service) Logout(ctx context.Context, refreshToken string) error {
+	err := s.repos.Sessions.Revoke(ctx, hashRefreshToken(refreshToken))
+	if errors.Is(err, db.ErrNotFound) {
+		return apperrors.New(apperrors.ErrCodeTokenError, "Invalid refresh token")
+	}
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to revoke refresh token")
+	}
+	return nil
+}
+
+// issue creates a new token family for the user.
+func (s *Service) issue(ctx context.Context, user *models.User) (*TokenPair, error) {
+	access, expiresAt, err := s.tokens.IssueAccessToken(user)
+	if err != nil {
+		return nil, apperrors.Internal("failed to sign access token", err)
+	}
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, apperrors.Internal("failed to generate refresh token", err)
+	}
+	refreshExpiresAt := time.Now().Add(s.refreshTTL)
+	if err := s.repos.Sessions.Start(ctx, user.ID, hashRefreshToken(refresh), refreshExpiresAt); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to store refresh token")
+	}
+
+	return &TokenPair{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
@@ -0,0 +1,134 @@
+package auth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/auth"
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/repository"
+)
+
+// fakeUsers is an in-memory UserRepository; methods the tests do not use
+// panic.
+type fakeUsers struct {
+	repository.UserRepository
+
+	users []*models.User
+}
+
+func (f *fakeUsers) find(match func(u *models.User) bool) (*models.User, error) {
+	for _, u := range f.users {
+		if match(u) {
+			return u, nil
+		}
+	}
+	return nil, db.ErrNotFound
+}
+
+func (f *fakeUsers) Create(_ context.Context, u models.User) (*models.User, error) {
+	if _, err := f.GetByUsername(context.Background(), u.Username); err == nil {
+		return nil, db.ErrUsernameTaken
+	}
+	u.ID = uuid.New()
+	u.Role = string(auth.RoleLearner)
+	f.users = append(f.users, &u)
+	return &u, nil
+}
+
+func (f *fakeUsers) Get(_ context.Context, id uuid.UUID) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.ID == id })
+}
+
+func (f *fakeUsers) GetByUsername(_ context.Context, username string) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.Username == username })
+}
+
+func (f *fakeUsers) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return strings.EqualFold(u.Email, email) })
+}
+
+// fakeSessions stores refresh token hashes without families.
+type fakeSessions struct {
+	owners map[string]uuid.UUID
+}
+
+func (f *fakeSessions) Start(_ context.Context, userID uuid.UUID, tokenHash string, _ time.Time) error {
+	f.owners[tokenHash] = userID
+	return nil
+}
+
+func (f *fakeSessions) Rotate(_ context.Context, oldHash, newHash string, _ time.Time) (uuid.UUID, error) {
+	userID, ok := f.owners[oldHash]
+	if !ok {
+		return uuid.Nil, db.ErrNotFound
+	}
+	delete(f.owners, oldHash)
+	f.owners[newHash] = userID
+	return userID, nil
+}
+
+func (f *fakeSessions) Revoke(_ context.Context, tokenHash string) error {
+	if _, ok := f.owners[tokenHash]; !ok {
+		return db.ErrNotFound
+	}
+	delete(f.owners, tokenHash)
+	return nil
+}
+
+func TestLogin(t *testing.T) {
+	repos := &repository.Repositories{Users: &fakeUsers{}, Sessions: &fakeSessions{owners: map[string]uuid.UUID{}}}
+	svc := auth.NewService(repos, auth.NewTokenManager("secret", time.Minute), time.Hour)
+	ctx := context.Background()
+
+	user, _, err := svc.Register(ctx, auth.RegisterInput{Username: "ada", Email: "Ada@Example.com", Password: "correct horse", AgeGroup: "adult"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "ada@example.com" {
+		t.Errorf("email = %q, want it lowercased", user.Email)
+	}
+
+	tests := []struct {
+		name     string
+		login    string
+		password string
+		ok       bool
+	}{
+		{"username", "ada", "correct horse", true},
+		{"email", "ADA@example.com", "correct horse", true},
+		{"wrong password", "ada", "battery staple", false},
+		{"unknown user", "grace", "correct horse", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair, err := svc.Login(ctx, tt.login, tt.password)
+			if !tt.ok {
+				if e, ok := apperrors.As(err); !ok || e.Code != apperrors.ErrCodeLoginError {
+					t.Fatalf("Login() error = %v, want a login error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+
+			refreshed, err := svc.Refresh(ctx, pair.RefreshToken)
+			if err != nil {
+				t.Fatalf("Refresh() error = %v", err)
+			}
+			if err := svc.Logout(ctx, refreshed.RefreshToken); err != nil {
+				t.Errorf("Logout() error = %v", err)
+			}
+			if _, err := svc.Refresh(ctx, pair.RefreshToken); err == nil {
+				t.Error("Refresh() accepted a rotated token")
+			}
+		})
+	}
+}
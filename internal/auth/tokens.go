@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/models"
+)
+
+// Identity is the authenticated user carried by an access token. The role
+// is the one the user had when the token was issued.
+type Identity struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Role     Role      `json:"role"`
+}
+
+// Can reports whether the identity's role grants the permission.
+func (i *Identity) Can(p Permission) bool {
+	return i.Role.Can(p)
+}
+
+// claims are the JWT claims of an access token.
+type claims struct {
+	jwt.RegisteredClaims
+	Username string `json:"username"`
+	Role     Role   `json:"role"`
+}
+
+// TokenManager issues and verifies signed access tokens.
+type TokenManager struct {
+	secret    []byte
+	accessTTL time.Duration
+}
+
+// NewTokenManager creates a TokenManager that signs tokens with HMAC-SHA256.
+func NewTokenManager(secret string, accessTTL time.Duration) *TokenManager {
+	return &TokenManager{secret: []byte(secret), accessTTL: accessTTL}
+}
+
+// IssueAccessToken returns a signed access token for the user and its expiry.
+func (m *TokenManager) IssueAccessToken(u *models.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.accessTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   u.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        uuid.NewString(),
+		},
+		Username: u.Username,
+		Role:     Role(u.Role),
+	})
+	signed, err := token.SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseAccessToken verifies an access token and returns its identity.
+func (m *TokenManager) ParseAccessToken(token string) (*Identity, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (any, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token subject: %w", err)
+	}
+	if !c.Role.Valid() {
+		return nil, fmt.Errorf("invalid token role %q", c.Role)
+	}
+	return &Identity{UserID: userID, Username: c.Username, Role: c.Role}, nil
+}
+
+// newRefreshToken returns a random opaque refresh token.
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken returns the hex SHA-256 of a refresh token. Only hashes
+// are stored so a database leak does not expose usable tokens.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,65 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/auth"
+	"mathtermind-go/internal/models"
+)
+
+func TestAccessTokens(t *testing.T) {
+	user := &models.User{Base: models.Base{ID: uuid.New()}, Username: "ada", Role: string(auth.RoleAuthor)}
+	tokens := auth.NewTokenManager("secret", time.Minute)
+
+	token, expiresAt, err := tokens.IssueAccessToken(user)
+	if err != nil {
+		t.Fatalf("IssueAccessToken() error = %v", err)
+	}
+	if !expiresAt.After(time.Now()) {
+		t.Errorf("token expires in the past: %v", expiresAt)
+	}
+
+	identity, err := tokens.ParseAccessToken(token)
+	if err != nil {
+		t.Fatalf("ParseAccessToken() error = %v", err)
+	}
+	if identity.UserID != user.ID || identity.Username != user.Username || identity.Role != auth.RoleAuthor {
+		t.Errorf("ParseAccessToken() = %+v, want user %s", identity, user.ID)
+	}
+
+	expired, _, _ := auth.NewTokenManager("secret", -time.Minute).IssueAccessToken(user)
+	foreign, _, _ := auth.NewTokenManager("other-secret", time.Minute).IssueAccessToken(user)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "expired", token: expired},
+		{name: "wrong signature", token: foreign},
+		{name: "garbage", token: "not-a-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tokens.ParseAccessToken(tt.token); err == nil {
+				t.Errorf("ParseAccessToken() accepted %s token", tt.name)
+			}
+		})
+	}
+}
+
+func TestPasswordHashing(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if ok, err := auth.CheckPassword(hash, "correct horse"); !ok || err != nil {
+		t.Errorf("CheckPassword() = %v, %v; want true, nil", ok, err)
+	}
+	if ok, err := auth.CheckPassword(hash, "battery staple"); ok || err != nil {
+		t.Errorf("CheckPassword() = %v, %v; want false, nil", ok, err)
+	}
+}
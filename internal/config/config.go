@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -18,6 +20,23 @@ type Config struct {
 	Database struct {
 		URL string `env:"DATABASE_URL"`
 	}
+	Auth struct {
+		JWTSecret       string        `env:"JWT_SECRET"`
+		AccessTokenTTL  time.Duration `env:"ACCESS_TOKEN_TTL" default:"15m"`
+		RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL" default:"720h"`
+	}
+	// Levels configures the experience level curve: reaching level 2 takes
+	// BasePoints and each further level takes Growth times more.
+	Levels struct {
+		BasePoints int     `env:"LEVEL_BASE_POINTS" default:"100"`
+		Growth     float64 `env:"LEVEL_GROWTH" default:"1.5"`
+		MaxLevel   int     `env:"LEVEL_MAX" default:"100"`
+	}
+	// Leaderboard configures how often the precomputed leaderboard points
+	// are refreshed.
+	Leaderboard struct {
+		RefreshInterval time.Duration `env:"LEADERBOARD_REFRESH_INTERVAL" default:"5m"`
+	}
 }
 
 // Load loads the configuration from environment variables
@@ -28,6 +47,32 @@ func Load() (*Config, error) {
 
 	cfg.Server.Port = getEnv("SERVER_PORT", "8080")
 	cfg.Database.URL = getEnv("DATABASE_URL", "")
+	cfg.Auth.JWTSecret = getEnv("JWT_SECRET", "")
+
+	var err error
+	if cfg.Auth.AccessTokenTTL, err = getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.Auth.RefreshTokenTTL, err = getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+
+	if cfg.Levels.BasePoints, err = getEnvInt("LEVEL_BASE_POINTS", 100); err != nil {
+		return nil, err
+	}
+	if cfg.Levels.Growth, err = getEnvFloat("LEVEL_GROWTH", 1.5); err != nil {
+		return nil, err
+	}
+	if cfg.Levels.MaxLevel, err = getEnvInt("LEVEL_MAX", 100); err != nil {
+		return nil, err
+	}
+
+	if cfg.Leaderboard.RefreshInterval, err = getEnvDuration("LEADERBOARD_REFRESH_INTERVAL", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.Leaderboard.RefreshInterval <= 0 {
+		return nil, fmt.Errorf("LEADERBOARD_REFRESH_INTERVAL must be positive")
+	}
 
 	if cfg.Server.Port == "" {
 		return nil, fmt.Errorf("server port cannot be empty")
@@ -43,3 +88,42 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDuration reads an environment variable as a time.Duration or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration for %s: %w", key, err)
+	}
+	return d, nil
+}
+
+// getEnvInt reads an environment variable as an int or returns a default value
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer for %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// getEnvFloat reads an environment variable as a float64 or returns a default value
+func getEnvFloat(key string, defaultValue float64) (float64, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number for %s: %w", key, err)
+	}
+	return f, nil
+}
@@ -0,0 +1,271 @@
+// Package content reads and writes lesson content items together with the
+// details of their type, and validates them before they are stored.
+//
+// A content item is a base row plus exactly one type-specific row, and the
+// JSONB fields of the details are checked against the JSON Schemas in the
+// schemas directory.
+package content
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"mathtermind-go/internal/assessment"
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/mathexpr"
+	"mathtermind-go/internal/models"
+)
+
+// Defaults mirroring the assessment_content column defaults, for requests
+// that omit them.
+const (
+	DefaultPassingScore    = 70.0
+	DefaultAttemptsAllowed = 3
+)
+
+// Errors maps the JSON path of each invalid field to what is wrong with it.
+type Errors map[string]string
+
+func (e Errors) Error() string {
+	keys := make([]string, 0, len(e))
+	for k := range e {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + ": " + e[k]
+	}
+	return "invalid content: " + strings.Join(parts, ", ")
+}
+
+// err returns e as an error, or nil when it is empty.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// details are the type-specific fields shared by models.Content and
+// db.ContentPatch.
+type details struct {
+	theory      *models.TheoryContent
+	exercise    *models.ExerciseContent
+	assessment  *models.AssessmentContent
+	interactive *models.InteractiveContent
+	resource    *models.ResourceContent
+}
+
+// set returns the types whose details are set.
+func (d details) set() []models.ContentType {
+	var types []models.ContentType
+	if d.theory != nil {
+		types = append(types, models.ContentTypeTheory)
+	}
+	if d.exercise != nil {
+		types = append(types, models.ContentTypeExercise)
+	}
+	if d.assessment != nil {
+		types = append(types, models.ContentTypeAssessment)
+	}
+	if d.interactive != nil {
+		types = append(types, models.ContentTypeInteractive)
+	}
+	if d.resource != nil {
+		types = append(types, models.ContentTypeResource)
+	}
+	return types
+}
+
+// Validate checks a content item before it is created: its type must be
+// known, only the details of that type may be set and they must be valid.
+func Validate(c *models.Content) error {
+	errs := Errors{}
+	validateTitle(errs, c.Title)
+	validateDetails(errs, c.ContentType, details{c.Theory, c.Exercise, c.Assessment, c.Interactive, c.Resource}, true)
+	return errs.err()
+}
+
+// ValidatePatch checks a patch of a content item of type t. Details are
+// optional in a patch, but when set they must be of type t and valid.
+func ValidatePatch(t models.ContentType, p db.ContentPatch) error {
+	errs := Errors{}
+	if p.Title != nil {
+		validateTitle(errs, *p.Title)
+	}
+	if p.Order != nil && *p.Order < 1 {
+		errs["order"] = "must be at least 1"
+	}
+	validateDetails(errs, t, details{p.Theory, p.Exercise, p.Assessment, p.Interactive, p.Resource}, false)
+	return errs.err()
+}
+
+func validateTitle(errs Errors, title string) {
+	switch {
+	case strings.TrimSpace(title) == "":
+		errs["title"] = "is required"
+	case utf8.RuneCountInString(title) > 255:
+		errs["title"] = "must be at most 255 characters"
+	}
+}
+
+// validateDetails checks that only the details of type t are set, and that
+// they are set when required. The details are keyed by their type, which is
+// also their JSON field name.
+func validateDetails(errs Errors, t models.ContentType, d details, required bool) {
+	switch t {
+	case models.ContentTypeTheory, models.ContentTypeExercise, models.ContentTypeAssessment,
+		models.ContentTypeInteractive, models.ContentTypeResource:
+	default:
+		errs["content_type"] = fmt.Sprintf("unknown content type %q", t)
+		return
+	}
+
+	found := false
+	for _, set := range d.set() {
+		if set != t {
+			errs[string(set)] = fmt.Sprintf("must not be set for %s content", t)
+			continue
+		}
+		found = true
+	}
+	if !found {
+		if required {
+			errs[string(t)] = fmt.Sprintf("is required for %s content", t)
+		}
+		return
+	}
+
+	switch t {
+	case models.ContentTypeTheory:
+		validateTheory(errs, d.theory)
+	case models.ContentTypeExercise:
+		validateExercise(errs, d.exercise)
+	case models.ContentTypeAssessment:
+		validateAssessment(errs, d.assessment)
+	case models.ContentTypeInteractive:
+		validateInteractive(errs, d.interactive)
+	case models.ContentTypeResource:
+		validateResource(errs, d.resource)
+	}
+}
+
+func validateTheory(errs Errors, t *models.TheoryContent) {
+	if strings.TrimSpace(t.TextContent) == "" {
+		errs["theory.text_content"] = "is required"
+	}
+}
+
+func validateExercise(errs Errors, e *models.ExerciseContent) {
+	if e.EstimatedTime != nil && *e.EstimatedTime < 0 {
+		errs["exercise.estimated_time"] = "must not be negative"
+	}
+	n := len(errs)
+	validateJSON(errs, "exercise.problems", problemsSchema, e.Problems)
+	if len(errs) == n {
+		// The schema cannot express that item IDs are unique.
+		problems, err := assessment.ParseProblems(e.Problems)
+		if err != nil {
+			errs["exercise.problems"] = err.Error()
+			return
+		}
+		validateAnswerKeys(errs, "exercise.problems.problems", problems)
+	}
+}
+
+func validateAssessment(errs Errors, a *models.AssessmentContent) {
+	if a.TimeLimit != nil && *a.TimeLimit < 1 {
+		errs["assessment.time_limit"] = "must be at least 1"
+	}
+	if a.PassingScore < 0 || a.PassingScore > 100 {
+		errs["assessment.passing_score"] = "must be between 0 and 100"
+	}
+	if a.AttemptsAllowed < 1 {
+		errs["assessment.attempts_allowed"] = "must be at least 1"
+	}
+	n := len(errs)
+	validateJSON(errs, "assessment.questions", questionsSchema, a.Questions)
+	if len(errs) == n {
+		questions, err := assessment.ParseQuestions(a.Questions)
+		if err != nil {
+			errs["assessment.questions"] = err.Error()
+			return
+		}
+		validateAnswerKeys(errs, "assessment.questions.questions", questions)
+	}
+}
+
+// validateAnswerKeys checks what the schema cannot express about the answer
+// keys of schema-valid items: the options of choice questions have unique
+// IDs and the correct answers are among them, and expressions parse and use
+// only the declared variables. Errors are recorded under field followed by
+// the index of the item, like those of the schema.
+func validateAnswerKeys(errs Errors, field string, items []assessment.Question) {
+	for i, q := range items {
+		at := fmt.Sprintf("%s.%d.", field, i)
+		switch q.Type {
+		case assessment.QuestionSingleChoice, assessment.QuestionMultipleChoice:
+			options := make(map[string]bool, len(q.Options))
+			for _, o := range q.Options {
+				if options[o.ID] {
+					errs[at+"options"] = fmt.Sprintf("duplicate option id %q", o.ID)
+				}
+				options[o.ID] = true
+			}
+			for _, id := range q.Correct {
+				if !options[id] {
+					errs[at+"correct"] = fmt.Sprintf("%q is not an option", id)
+					break
+				}
+			}
+		case assessment.QuestionExpression:
+			expr, err := mathexpr.Parse(q.Expression)
+			if err != nil {
+				errs[at+"expression"] = err.Error()
+				continue
+			}
+			declared := make(map[string]bool, len(q.Variables))
+			for _, v := range q.Variables {
+				declared[v] = true
+			}
+			for _, v := range mathexpr.Vars(expr) {
+				if !declared[v] {
+					errs[at+"expression"] = fmt.Sprintf("uses variable %q, which is not declared in variables", v)
+					break
+				}
+			}
+		}
+	}
+}
+
+func validateInteractive(errs Errors, i *models.InteractiveContent) {
+	validateType(errs, "interactive.interactive_type", i.InteractiveType)
+	validateJSON(errs, "interactive.content_data", contentDataSchema, i.ContentData)
+}
+
+func validateResource(errs Errors, r *models.ResourceContent) {
+	validateType(errs, "resource.resource_type", r.ResourceType)
+	u, err := url.Parse(r.URL)
+	switch {
+	case r.URL == "":
+		errs["resource.url"] = "is required"
+	case len(r.URL) > 1024:
+		errs["resource.url"] = "must be at most 1024 characters"
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+		errs["resource.url"] = "must be an absolute http or https URL"
+	}
+}
+
+// validateType checks a free-form type name stored in a VARCHAR(50) column.
+func validateType(errs Errors, field, name string) {
+	switch {
+	case strings.TrimSpace(name) == "":
+		errs[field] = "is required"
+	case utf8.RuneCountInString(name) > 50:
+		errs[field] = "must be at most 50 characters"
+	}
+}
@@ -0,0 +1,272 @@
+package content_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"mathtermind-go/internal/content"
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+)
+
+func choiceQuestion(id string) map[string]any {
+	return map[string]any{
+		"id":      id,
+		"type":    "single_choice",
+		"prompt":  "2 + 2 = ?",
+		"options": []any{map[string]any{"id": "a", "text": "3"}, map[string]any{"id": "b", "text": "4"}},
+		"correct": []any{"b"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	theory := &models.TheoryContent{TextContent: "Fractions"}
+	tests := []struct {
+		name    string
+		content models.Content
+		// wantErrors are the invalid fields, or none for a valid content.
+		wantErrors []string
+	}{
+		{
+			name:    "theory",
+			content: models.Content{Title: "Intro", ContentType: models.ContentTypeTheory, Theory: theory},
+		},
+		{
+			name: "exercise with untyped problem",
+			content: models.Content{Title: "Practice", ContentType: models.ContentTypeExercise, Exercise: &models.ExerciseContent{
+				Problems: models.JSONB{"problems": []any{map[string]any{"id": "p1", "prompt": "Simplify", "expression": "2*x", "variables": []any{"x"}}}},
+			}},
+		},
+		{
+			name: "assessment",
+			content: models.Content{Title: "Quiz", ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{
+				Questions:       models.JSONB{"questions": []any{choiceQuestion("q1")}},
+				PassingScore:    70,
+				AttemptsAllowed: 3,
+			}},
+		},
+		{
+			name: "interactive",
+			content: models.Content{Title: "Plot", ContentType: models.ContentTypeInteractive, Interactive: &models.InteractiveContent{
+				InteractiveType: "graph",
+				ContentData:     models.JSONB{"function": "x^2"},
+			}},
+		},
+		{
+			name: "resource",
+			content: models.Content{Title: "Video", ContentType: models.ContentTypeResource, Resource: &models.ResourceContent{
+				ResourceType: "video",
+				URL:          "https://example.com/fractions",
+			}},
+		},
+		{
+			name:       "unknown type",
+			content:    models.Content{Title: "Intro", ContentType: "quiz", Theory: theory},
+			wantErrors: []string{"content_type"},
+		},
+		{
+			name:       "missing details",
+			content:    models.Content{Title: "Intro", ContentType: models.ContentTypeTheory},
+			wantErrors: []string{"theory"},
+		},
+		{
+			name:       "details of another type",
+			content:    models.Content{Title: "Intro", ContentType: models.ContentTypeExercise, Theory: theory},
+			wantErrors: []string{"exercise", "theory"},
+		},
+		{
+			name: "details of two types",
+			content: models.Content{Title: "Intro", ContentType: models.ContentTypeTheory, Theory: theory,
+				Resource: &models.ResourceContent{ResourceType: "video", URL: "https://example.com"}},
+			wantErrors: []string{"resource"},
+		},
+		{
+			name:       "blank title and text",
+			content:    models.Content{Title: " ", ContentType: models.ContentTypeTheory, Theory: &models.TheoryContent{}},
+			wantErrors: []string{"theory.text_content", "title"},
+		},
+		{
+			name: "question without answer key",
+			content: models.Content{Title: "Quiz", ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{
+				Questions:       models.JSONB{"questions": []any{map[string]any{"id": "q1", "type": "numeric", "prompt": "1/2 = ?"}}},
+				PassingScore:    70,
+				AttemptsAllowed: 3,
+			}},
+			wantErrors: []string{"assessment.questions.questions.0"},
+		},
+		{
+			name: "question of unknown type",
+			content: models.Content{Title: "Quiz", ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{
+				Questions:       models.JSONB{"questions": []any{map[string]any{"id": "q1", "type": "essay", "prompt": "Why?"}}},
+				PassingScore:    70,
+				AttemptsAllowed: 3,
+			}},
+			wantErrors: []string{"assessment.questions.questions.0.type"},
+		},
+		{
+			name: "duplicate question ids",
+			content: models.Content{Title: "Quiz", ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{
+				Questions:       models.JSONB{"questions": []any{choiceQuestion("q1"), choiceQuestion("q1")}},
+				PassingScore:    70,
+				AttemptsAllowed: 3,
+			}},
+			wantErrors: []string{"assessment.questions"},
+		},
+		{
+			name: "assessment settings",
+			content: models.Content{Title: "Quiz", ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{
+				Questions:    models.JSONB{"questions": []any{choiceQuestion("q1")}},
+				PassingScore: 120,
+			}},
+			wantErrors: []string{"assessment.attempts_allowed", "assessment.passing_score"},
+		},
+		{
+			name: "choice answer key",
+			content: models.Content{Title: "Quiz", ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{
+				Questions: models.JSONB{"questions": []any{
+					choiceQuestion("q1"),
+					map[string]any{
+						"id": "q2", "type": "multiple_choice", "prompt": "Even numbers?",
+						"options": []any{map[string]any{"id": "a", "text": "2"}, map[string]any{"id": "a", "text": "4"}},
+						"correct": []any{"a", "c"},
+					},
+				}},
+				PassingScore:    70,
+				AttemptsAllowed: 3,
+			}},
+			wantErrors: []string{"assessment.questions.questions.1.correct", "assessment.questions.questions.1.options"},
+		},
+		{
+			name: "numeric answer key",
+			content: models.Content{Title: "Quiz", ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{
+				Questions: models.JSONB{"questions": []any{
+					map[string]any{"id": "q1", "type": "numeric", "prompt": "1/2 = ?", "value": 0.5, "tolerance": -0.1},
+				}},
+				PassingScore:    70,
+				AttemptsAllowed: 3,
+			}},
+			wantErrors: []string{"assessment.questions.questions.0.tolerance"},
+		},
+		{
+			name: "invalid expressions",
+			content: models.Content{Title: "Quiz", ContentType: models.ContentTypeAssessment, Assessment: &models.AssessmentContent{
+				Questions: models.JSONB{"questions": []any{
+					map[string]any{"id": "q1", "type": "expression", "prompt": "Simplify", "expression": "2*(x"},
+					map[string]any{"id": "q2", "type": "expression", "prompt": "Simplify", "expression": "x*y", "variables": []any{"x"}},
+					map[string]any{"id": "q3", "type": "expression", "prompt": "Simplify", "expression": "sin(pi*x)", "variables": []any{"x"}},
+				}},
+				PassingScore:    70,
+				AttemptsAllowed: 3,
+			}},
+			wantErrors: []string{"assessment.questions.questions.0.expression", "assessment.questions.questions.1.expression"},
+		},
+		{
+			name: "problem with undeclared variable",
+			content: models.Content{Title: "Practice", ContentType: models.ContentTypeExercise, Exercise: &models.ExerciseContent{
+				Problems: models.JSONB{"problems": []any{map[string]any{"id": "p1", "prompt": "Simplify", "expression": "2*x"}}},
+			}},
+			wantErrors: []string{"exercise.problems.problems.0.expression"},
+		},
+		{
+			name: "problem with unknown field",
+			content: models.Content{Title: "Practice", ContentType: models.ContentTypeExercise, Exercise: &models.ExerciseContent{
+				Problems: models.JSONB{"problems": []any{map[string]any{"id": "p1", "prompt": "Simplify", "expression": "x", "answer": "x"}}},
+			}},
+			wantErrors: []string{"exercise.problems.problems.0"},
+		},
+		{
+			name: "exercise without problems",
+			content: models.Content{Title: "Practice", ContentType: models.ContentTypeExercise,
+				Exercise: &models.ExerciseContent{}},
+			wantErrors: []string{"exercise.problems"},
+		},
+		{
+			name: "empty interactive data",
+			content: models.Content{Title: "Plot", ContentType: models.ContentTypeInteractive, Interactive: &models.InteractiveContent{
+				InteractiveType: "graph",
+				ContentData:     models.JSONB{},
+			}},
+			wantErrors: []string{"interactive.content_data"},
+		},
+		{
+			name: "relative resource url",
+			content: models.Content{Title: "Video", ContentType: models.ContentTypeResource, Resource: &models.ResourceContent{
+				URL: "/videos/1",
+			}},
+			wantErrors: []string{"resource.resource_type", "resource.url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := content.Validate(&tt.content)
+			assertErrors(t, err, tt.wantErrors)
+		})
+	}
+}
+
+func TestValidatePatch(t *testing.T) {
+	title, blank, zero := "Quiz", "", 0
+	tests := []struct {
+		name       string
+		patch      db.ContentPatch
+		wantErrors []string
+	}{
+		{name: "base fields only", patch: db.ContentPatch{Title: &title}},
+		{
+			name: "details of the content type",
+			patch: db.ContentPatch{Assessment: &models.AssessmentContent{
+				Questions:       models.JSONB{"questions": []any{choiceQuestion("q1")}},
+				PassingScore:    50,
+				AttemptsAllowed: 1,
+			}},
+		},
+		{
+			name:       "details of another type",
+			patch:      db.ContentPatch{Theory: &models.TheoryContent{TextContent: "Fractions"}},
+			wantErrors: []string{"theory"},
+		},
+		{
+			name:       "invalid base fields",
+			patch:      db.ContentPatch{Title: &blank, Order: &zero},
+			wantErrors: []string{"order", "title"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := content.ValidatePatch(models.ContentTypeAssessment, tt.patch)
+			assertErrors(t, err, tt.wantErrors)
+		})
+	}
+}
+
+func assertErrors(t *testing.T, err error, want []string) {
+	t.Helper()
+	if len(want) == 0 {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	var errs content.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want content.Errors", err)
+	}
+	got := make([]string, 0, len(errs))
+	for k := range errs {
+		got = append(got, k)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("errors = %v, want fields %v", errs, want)
+	}
+	for _, field := range want {
+		if _, ok := errs[field]; !ok {
+			t.Errorf("errors = %v, missing field %s", errs, field)
+		}
+	}
+	if !strings.HasPrefix(err.Error(), "invalid content: ") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
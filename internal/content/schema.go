@@ -0,0 +1,100 @@
+package content
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// schemaBase is the base URL the embedded schemas are registered under, so
+// that they can reference each other by file name.
+const schemaBase = "https://mathtermind.app/schemas/content/"
+
+// The schemas of the JSONB fields of the type-specific details.
+var (
+	problemsSchema    = mustCompile("problems.json")
+	questionsSchema   = mustCompile("questions.json")
+	contentDataSchema = mustCompile("content_data.json")
+)
+
+var compiler = newCompiler()
+
+func newCompiler() *jsonschema.Compiler {
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft2020
+	names, err := fs.Glob(schemaFiles, "schemas/*.json")
+	if err != nil {
+		panic(err)
+	}
+	for _, name := range names {
+		data, err := schemaFiles.ReadFile(name)
+		if err != nil {
+			panic(err)
+		}
+		if err := c.AddResource(schemaBase+strings.TrimPrefix(name, "schemas/"), bytes.NewReader(data)); err != nil {
+			panic(fmt.Sprintf("content: schema %s: %v", name, err))
+		}
+	}
+	return c
+}
+
+func mustCompile(name string) *jsonschema.Schema {
+	return compiler.MustCompile(schemaBase + name)
+}
+
+// validateJSON validates data against schema and records every violation in
+// errs under field followed by the location of the offending value.
+func validateJSON(errs Errors, field string, schema *jsonschema.Schema, data models.JSONB) {
+	// The validator expects values as decoded by encoding/json.
+	raw, err := json.Marshal(data)
+	if err != nil {
+		errs[field] = err.Error()
+		return
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		errs[field] = err.Error()
+		return
+	}
+
+	err = schema.Validate(doc)
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		if err != nil {
+			errs[field] = err.Error()
+		}
+		return
+	}
+	for _, leaf := range leaves(ve) {
+		key := field + strings.ReplaceAll(leaf.InstanceLocation, "/", ".")
+		if msg, ok := errs[key]; ok {
+			errs[key] = msg + "; " + leaf.Message
+		} else {
+			errs[key] = leaf.Message
+		}
+	}
+}
+
+// leaves returns the innermost causes of a validation error, which name the
+// actual violations rather than the schemas they failed.
+func leaves(ve *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(ve.Causes) == 0 {
+		return []*jsonschema.ValidationError{ve}
+	}
+	var out []*jsonschema.ValidationError
+	for _, cause := range ve.Causes {
+		out = append(out, leaves(cause)...)
+	}
+	return out
+}
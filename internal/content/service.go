@@ -0,0 +1,84 @@
+package content
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/repository"
+)
+
+// Service lists, creates and updates the contents of lessons.
+type Service struct {
+	repos *repository.Repositories
+}
+
+// NewService creates a content service.
+func NewService(repos *repository.Repositories) *Service {
+	return &Service{repos: repos}
+}
+
+// validationError converts the errors of Validate and ValidatePatch into an
+// API error.
+func validationError(err error) error {
+	var errs Errors
+	if errors.As(err, &errs) {
+		return apperrors.Validation("Validation failed", map[string]any{"errors": map[string]string(errs)})
+	}
+	return err
+}
+
+// List returns the contents of a lesson in order. It returns
+// db.ErrNotFound when the lesson does not exist.
+func (s *Service) List(ctx context.Context, lessonID uuid.UUID) ([]models.Content, error) {
+	var contents []models.Content
+	err := s.repos.Tx.WithReadOnlyTx(ctx, func(ctx context.Context) error {
+		if _, err := s.repos.Lessons.Get(ctx, lessonID); err != nil {
+			return err
+		}
+		var err error
+		contents, err = s.repos.Contents.ListByLesson(ctx, lessonID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Create validates a content item and stores it with its details.
+func (s *Service) Create(ctx context.Context, c models.Content) (*models.Content, error) {
+	if err := Validate(&c); err != nil {
+		return nil, validationError(err)
+	}
+	return s.repos.Contents.Create(ctx, c)
+}
+
+// Update validates a patch against the type of a content item of the lesson
+// and applies it. It returns db.ErrNotFound when the item does not belong
+// to the lesson.
+func (s *Service) Update(ctx context.Context, lessonID, id uuid.UUID, p db.ContentPatch) (*models.Content, error) {
+	var updated *models.Content
+	err := s.repos.Tx.WithTx(ctx, func(ctx context.Context) error {
+		current, err := s.repos.Contents.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if current.LessonID != lessonID {
+			return db.ErrNotFound
+		}
+		if err := ValidatePatch(current.ContentType, p); err != nil {
+			return validationError(err)
+		}
+		updated, err = s.repos.Contents.Update(ctx, id, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,181 @@
+package coursepack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Limits of the archives Read accepts.
+const (
+	// MaxPackageSize is the largest archive accepted, in bytes.
+	MaxPackageSize = 32 << 20
+	maxFiles       = 2000
+	maxFileSize    = 8 << 20
+	// maxUnpackedSize bounds the files of an archive together, so that a
+	// small archive of highly compressed files cannot exhaust memory.
+	maxUnpackedSize = 64 << 20
+)
+
+// file is a file of a package.
+type file struct {
+	name string
+	data []byte
+}
+
+// writeArchive writes files as an archive in the given format, with every
+// file modified at modTime.
+func writeArchive(w io.Writer, f Format, files []file, modTime time.Time) error {
+	switch f {
+	case FormatZip:
+		zw := zip.NewWriter(w)
+		for _, file := range files {
+			fw, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: modTime})
+			if err != nil {
+				return err
+			}
+			if _, err := fw.Write(file.data); err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	case FormatTarGz:
+		gw := gzip.NewWriter(w)
+		tw := tar.NewWriter(gw)
+		for _, file := range files {
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     file.name,
+				Mode:     0o644,
+				Size:     int64(len(file.data)),
+				ModTime:  modTime,
+			}); err != nil {
+				return err
+			}
+			if _, err := tw.Write(file.data); err != nil {
+				return err
+			}
+		}
+		if err := tw.Close(); err != nil {
+			return err
+		}
+		return gw.Close()
+	default:
+		return fmt.Errorf("unknown package format %q", f)
+	}
+}
+
+// readArchive returns the regular files of a zip, tar or gzip-compressed tar
+// archive keyed by their cleaned names.
+func readArchive(data []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	var total int64
+	tooLarge := fmt.Errorf("archive unpacks to more than %d MiB", maxUnpackedSize>>20)
+	add := func(name string, size int64, r io.Reader) error {
+		if len(files) == maxFiles {
+			return fmt.Errorf("archive has more than %d files", maxFiles)
+		}
+		clean := path.Clean(strings.TrimPrefix(name, "./"))
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("%s: invalid file name", name)
+		}
+		if size > maxFileSize {
+			return fmt.Errorf("%s: file is larger than %d MiB", clean, maxFileSize>>20)
+		}
+		if total+size > maxUnpackedSize {
+			return tooLarge
+		}
+		b, err := io.ReadAll(io.LimitReader(r, maxFileSize+1))
+		if err != nil {
+			return fmt.Errorf("%s: %w", clean, err)
+		}
+		if len(b) > maxFileSize {
+			return fmt.Errorf("%s: file is larger than %d MiB", clean, maxFileSize>>20)
+		}
+		// The sizes in the headers may understate what the files hold.
+		if total += int64(len(b)); total > maxUnpackedSize {
+			return tooLarge
+		}
+		files[clean] = b
+		return nil
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid zip archive: %w", err)
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			err = add(f.Name, int64(f.UncompressedSize64), rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return files, nil
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip archive: %w", err)
+		}
+		defer gr.Close()
+		return files, readTar(gr, add)
+	default:
+		return files, readTar(bytes.NewReader(data), add)
+	}
+}
+
+func readTar(r io.Reader, add func(name string, size int64, r io.Reader) error) error {
+	tr := tar.NewReader(r)
+	for first := true; ; first = false {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if first {
+				return errors.New("not a zip or tar archive")
+			}
+			return fmt.Errorf("invalid tar archive: %w", err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := add(h.Name, h.Size, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// packageRoot returns the directory holding the manifest: the root of the
+// archive, or its only top-level directory when the package was archived
+// as a folder.
+func packageRoot(files map[string][]byte) (string, bool) {
+	if _, ok := files[ManifestFile]; ok {
+		return "", true
+	}
+	root, found := "", 0
+	for name := range files {
+		dir, base := path.Split(name)
+		if base == ManifestFile && strings.Count(dir, "/") == 1 {
+			root = dir
+			found++
+		}
+	}
+	return root, found == 1
+}
@@ -0,0 +1,235 @@
+// Package coursepack reads and writes course packages: portable archives
+// holding a course with its lessons, contents and tags, so that courses can
+// be written offline and moved between installations.
+//
+// A package is a zip or gzip-compressed tar archive with a manifest,
+// course.yaml, at its root or in its single top-level directory:
+//
+//	format: mathtermind-course
+//	version: 1
+//	course:
+//	  topic: MATH
+//	  name: Fractions
+//	  description: Adding and comparing fractions.
+//	  duration_min: 90
+//	tags:
+//	  - name: arithmetic
+//	    category: TOPIC
+//	lessons:
+//	  - title: What is a fraction
+//	    estimated_time_min: 20
+//	    points_reward: 10
+//	    contents:
+//	      - title: Parts of a whole
+//	        type: theory
+//	        file: lessons/01/01-parts-of-a-whole.md
+//
+// Lessons and contents are stored in the order they are listed. The details
+// of each content item are kept in the file it references: theory as
+// Markdown, with examples and references in optional YAML front matter, and
+// the other types as YAML holding the fields of their details, such as the
+// problems of an exercise or the questions and passing score of an
+// assessment. Packages carry no IDs; importing one creates a new course.
+package coursepack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"mathtermind-go/internal/models"
+)
+
+const (
+	// FormatName identifies course packages in the manifest.
+	FormatName = "mathtermind-course"
+	// Version is the package format version written by Write and the
+	// newest one Read accepts.
+	Version = 1
+	// ManifestFile is the name of the manifest.
+	ManifestFile = "course.yaml"
+)
+
+// Defaults mirroring the column defaults, for fields a package may omit.
+const (
+	defaultTagCategory  = "TOPIC"
+	defaultLessonPoints = 10
+)
+
+// Format is an archive format of a package.
+type Format string
+
+const (
+	FormatZip   Format = "zip"
+	FormatTarGz Format = "tar.gz"
+)
+
+// ParseFormat parses an archive format; an empty value is FormatZip.
+func ParseFormat(v string) (Format, error) {
+	switch f := Format(v); f {
+	case "":
+		return FormatZip, nil
+	case FormatZip, FormatTarGz:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown package format %q", v)
+	}
+}
+
+// ContentType returns the MIME type of archives in the format.
+func (f Format) ContentType() string {
+	if f == FormatTarGz {
+		return "application/gzip"
+	}
+	return "application/zip"
+}
+
+// FileName returns the file name a course is exported under.
+func FileName(course *models.Course, f Format) string {
+	return slug(course.Name, "course") + "." + string(f)
+}
+
+// Problem is a problem found in a package. Line is 0 when the problem is
+// not tied to a line, and File is empty when it concerns the archive itself.
+type Problem struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.File == "":
+		return p.Message
+	case p.Line == 0:
+		return p.File + ": " + p.Message
+	default:
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	}
+}
+
+// Problems are the problems found in a package, ordered by file and line.
+type Problems []Problem
+
+func (ps Problems) Error() string {
+	lines := make([]string, len(ps))
+	for i, p := range ps {
+		lines[i] = p.String()
+	}
+	return "invalid course package:\n" + strings.Join(lines, "\n")
+}
+
+func (ps Problems) sort() {
+	sort.SliceStable(ps, func(i, j int) bool {
+		if ps[i].File != ps[j].File {
+			// The manifest comes first, problems with the archive before both.
+			return fileRank(ps[i].File) < fileRank(ps[j].File) ||
+				fileRank(ps[i].File) == fileRank(ps[j].File) && ps[i].File < ps[j].File
+		}
+		return ps[i].Line < ps[j].Line
+	})
+}
+
+func fileRank(name string) int {
+	switch name {
+	case "":
+		return 0
+	case ManifestFile:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// manifest is the content of course.yaml.
+type manifest struct {
+	Format  string        `yaml:"format"`
+	Version int           `yaml:"version"`
+	Course  courseEntry   `yaml:"course"`
+	Tags    []tagEntry    `yaml:"tags,omitempty"`
+	Lessons []lessonEntry `yaml:"lessons"`
+}
+
+type courseEntry struct {
+	Topic       string `yaml:"topic"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	DurationMin int    `yaml:"duration_min"`
+}
+
+type tagEntry struct {
+	Name     string `yaml:"name"`
+	Category string `yaml:"category,omitempty"`
+}
+
+type lessonEntry struct {
+	Title            string         `yaml:"title"`
+	EstimatedTimeMin int            `yaml:"estimated_time_min"`
+	PointsReward     *int           `yaml:"points_reward,omitempty"`
+	Contents         []contentEntry `yaml:"contents"`
+}
+
+type contentEntry struct {
+	Title       string             `yaml:"title"`
+	Description *string            `yaml:"description,omitempty"`
+	Type        models.ContentType `yaml:"type"`
+	File        string             `yaml:"file"`
+}
+
+// The content files of each type. Their fields carry the JSON names of the
+// details, so that validation errors can be traced back to them.
+
+type theoryFrontMatter struct {
+	Examples   models.JSONB `yaml:"examples,omitempty"`
+	References models.JSONB `yaml:"references,omitempty"`
+}
+
+type exerciseFile struct {
+	EstimatedTime *int `yaml:"estimated_time,omitempty"`
+	Problems      any  `yaml:"problems"`
+}
+
+type assessmentFile struct {
+	TimeLimit       *int     `yaml:"time_limit,omitempty"`
+	PassingScore    *float64 `yaml:"passing_score,omitempty"`
+	AttemptsAllowed *int     `yaml:"attempts_allowed,omitempty"`
+	Questions       any      `yaml:"questions"`
+}
+
+type interactiveFile struct {
+	InteractiveType string       `yaml:"interactive_type"`
+	ContentData     models.JSONB `yaml:"content_data"`
+	Config          models.JSONB `yaml:"config,omitempty"`
+}
+
+type resourceFile struct {
+	ResourceType     string       `yaml:"resource_type"`
+	URL              string       `yaml:"url"`
+	ResourceMetadata models.JSONB `yaml:"resource_metadata,omitempty"`
+}
+
+// slug turns a title into a file name, falling back to def for titles
+// without ASCII letters or digits.
+func slug(title, def string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+		if b.Len() >= 40 {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return def
+	}
+	return b.String()
+}
@@ -0,0 +1,327 @@
+package coursepack_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/coursepack"
+	"mathtermind-go/internal/models"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// fixture is a course with a content item of every type, with JSONB values
+// as decoded from the database.
+func fixture() *models.Course {
+	id := func() models.Base { return models.Base{ID: uuid.New()} }
+	return &models.Course{
+		Base:        models.Base{ID: uuid.New(), UpdatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		Topic:       "MATH",
+		Name:        "Дроби: fractions",
+		Description: "Adding and comparing fractions.",
+		DurationMin: 90,
+		Tags:        []models.Tag{{Name: "arithmetic", Category: "TOPIC"}, {Name: "grade-5", Category: "LEVEL"}},
+		Lessons: []models.Lesson{
+			{
+				Base:             id(),
+				Title:            "What is a fraction",
+				LessonOrder:      1,
+				EstimatedTimeMin: 20,
+				PointsReward:     15,
+				Contents: []models.Content{
+					{
+						Base: id(), Title: "Parts of a whole", Order: 1, ContentType: models.ContentTypeTheory,
+						Description: ptr("The idea of a fraction."),
+						Theory: &models.TheoryContent{
+							TextContent: "# Fractions\n\nA fraction $\\frac{a}{b}$ is...\n",
+							Examples:    models.JSONB{"items": []any{"1/2", "3/4"}},
+						},
+					},
+					{
+						Base: id(), Title: "Starts like front matter", Order: 2, ContentType: models.ContentTypeTheory,
+						Theory: &models.TheoryContent{TextContent: "---\nA horizontal rule first.\n"},
+					},
+					{
+						Base: id(), Title: "Practice", Order: 3, ContentType: models.ContentTypeExercise,
+						Exercise: &models.ExerciseContent{
+							EstimatedTime: ptr(10),
+							Problems: models.JSONB{"problems": []any{
+								map[string]any{"id": "p1", "prompt": "Simplify 2/4", "expression": "1/2"},
+							}},
+						},
+					},
+				},
+			},
+			{
+				Base:             id(),
+				Title:            "Comparing fractions",
+				LessonOrder:      2,
+				EstimatedTimeMin: 30,
+				PointsReward:     10,
+				Contents: []models.Content{
+					{
+						Base: id(), Title: "Quiz", Order: 1, ContentType: models.ContentTypeAssessment,
+						Assessment: &models.AssessmentContent{
+							Questions: models.JSONB{"questions": []any{
+								map[string]any{"id": "q1", "type": "numeric", "prompt": "1/2 = ?", "value": 0.5, "points": float64(2)},
+								map[string]any{
+									"id": "q2", "type": "single_choice", "prompt": "Larger?",
+									"options": []any{map[string]any{"id": "a", "text": "1/3"}, map[string]any{"id": "b", "text": "1/2"}},
+									"correct": []any{"b"},
+								},
+							}},
+							TimeLimit:       ptr(15),
+							PassingScore:    80,
+							AttemptsAllowed: 2,
+						},
+					},
+					{
+						Base: id(), Title: "Number line", Order: 2, ContentType: models.ContentTypeInteractive,
+						Interactive: &models.InteractiveContent{
+							InteractiveType: "number_line",
+							ContentData:     models.JSONB{"min": float64(0), "max": float64(1), "marks": []any{0.25, 0.5}},
+							Config:          models.JSONB{"snap": true},
+						},
+					},
+					{
+						Base: id(), Title: "Video", Order: 3, ContentType: models.ContentTypeResource,
+						Resource: &models.ResourceContent{
+							ResourceType:     "video",
+							URL:              "https://example.com/fractions",
+							ResourceMetadata: models.JSONB{"duration": "5:00"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// strip removes what a package does not carry: IDs, timestamps and owners.
+func strip(c *models.Course) *models.Course {
+	out := *c
+	out.Base = models.Base{}
+	out.Lessons = nil
+	for _, l := range c.Lessons {
+		l.Base = models.Base{}
+		contents := l.Contents
+		l.Contents = nil
+		for _, item := range contents {
+			item.Base = models.Base{}
+			if item.Resource != nil {
+				r := *item.Resource
+				r.CreatedBy = nil
+				item.Resource = &r
+			}
+			l.Contents = append(l.Contents, item)
+		}
+		out.Lessons = append(out.Lessons, l)
+	}
+	return &out
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []coursepack.Format{coursepack.FormatZip, coursepack.FormatTarGz} {
+		t.Run(string(format), func(t *testing.T) {
+			course := fixture()
+			var buf bytes.Buffer
+			if err := coursepack.Write(&buf, course, format); err != nil {
+				t.Fatal(err)
+			}
+
+			pkg, err := coursepack.Read(buf.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// JSONB numbers come back as ints from YAML; compare as JSON.
+			want, _ := json.Marshal(strip(course))
+			got, _ := json.Marshal(pkg.Course)
+			if !bytes.Equal(got, want) {
+				t.Errorf("read course\n%s\nwant\n%s", got, want)
+			}
+
+			var again bytes.Buffer
+			if err := coursepack.Write(&again, course, format); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+				t.Error("exporting the same course twice produced different archives")
+			}
+		})
+	}
+}
+
+// zipped archives files, in the order given, under dir.
+func zipped(t *testing.T, dir string, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		w, err := zw.Create(dir + files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(files[i+1]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const validManifest = `format: mathtermind-course
+version: 1
+course:
+  topic: MATH
+  name: Fractions
+  description: Adding fractions.
+  duration_min: 30
+lessons:
+  - title: Basics
+    estimated_time_min: 10
+    contents:
+      - title: Intro
+        type: theory
+        file: intro.md
+`
+
+func TestReadFolder(t *testing.T) {
+	pkg, err := coursepack.Read(zipped(t, "fractions/", "course.yaml", validManifest, "intro.md", "Fractions."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lesson := pkg.Course.Lessons[0]
+	if lesson.PointsReward != 10 || lesson.Contents[0].Theory.TextContent != "Fractions." {
+		t.Errorf("lesson = %+v, want default points and the theory text", lesson)
+	}
+}
+
+func TestReadUnpackedSize(t *testing.T) {
+	// Nine files of 8 MiB of zeros compress to a small archive but unpack
+	// to 72 MiB.
+	zeros := string(make([]byte, 8<<20))
+	var files []string
+	for i := 0; i < 9; i++ {
+		files = append(files, fmt.Sprintf("zeros%d.bin", i), zeros)
+	}
+	data := zipped(t, "", files...)
+	if len(data) > 1<<20 {
+		t.Fatalf("archive is %d bytes, want a small one", len(data))
+	}
+
+	_, err := coursepack.Read(data)
+	want := coursepack.Problems{{Message: "archive unpacks to more than 64 MiB"}}
+	var got coursepack.Problems
+	if !errors.As(err, &got) || !reflect.DeepEqual(got, want) {
+		t.Errorf("error = %v, want %v", err, want)
+	}
+}
+
+func TestReadProblems(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  coursepack.Problems
+	}{
+		{
+			name:  "not an archive",
+			files: nil,
+			want:  coursepack.Problems{{Message: "archive has no course.yaml"}},
+		},
+		{
+			name:  "unknown field",
+			files: []string{"course.yaml", validManifest + "    level: easy\n", "intro.md", "Fractions."},
+			want: coursepack.Problems{
+				{File: "course.yaml", Line: 15, Message: "unknown field level"},
+			},
+		},
+		{
+			name: "manifest values",
+			files: []string{"course.yaml", `format: mathtermind-course
+version: 2
+course:
+  topic: MATH
+  name: ""
+  description: Adding fractions.
+  duration_min: -5
+tags:
+  - name: arithmetic
+  - name: arithmetic
+lessons:
+  - title: Basics
+    estimated_time_min: 10
+    contents:
+      - title: Intro
+        type: video
+        file: intro.md
+      - title: Missing
+        type: theory
+        file: missing.md
+`, "intro.md", "Fractions."},
+			want: coursepack.Problems{
+				{File: "course.yaml", Line: 2, Message: "version: unsupported version 2, the newest supported is 1"},
+				{File: "course.yaml", Line: 5, Message: "course.name: is required"},
+				{File: "course.yaml", Line: 7, Message: "course.duration_min: must not be negative"},
+				{File: "course.yaml", Line: 10, Message: `tags.1.name: duplicate tag "arithmetic"`},
+				{File: "course.yaml", Line: 16, Message: `lessons.0.contents.0.type: unknown content type "video"`},
+				{File: "course.yaml", Line: 20, Message: "lessons.0.contents.1.file: file missing.md is not in the package"},
+			},
+		},
+		{
+			name: "content files",
+			files: []string{"course.yaml", validManifest + `      - title: Quiz
+        type: assessment
+        file: quiz.yaml
+      - title: ""
+        type: exercise
+        file: practice.yaml
+      - title: Plot
+        type: interactive
+        file: plot.yaml
+`,
+				"intro.md", "---\nexamples: [1, 2\n---\nText",
+				"quiz.yaml", `passing_score: 120
+questions:
+  - id: q1
+    type: numeric
+    prompt: 1/2 = ?
+`,
+				"practice.yaml", `problems:
+  - id: p1
+    prompt: Simplify
+    expression: x
+    variables: [x]
+`,
+				"plot.yaml", "interactive_type: graph\ncontent_data: {}\n",
+			},
+			want: coursepack.Problems{
+				{File: "course.yaml", Line: 18, Message: "lessons.0.contents.2.title: is required"},
+				{File: "intro.md", Line: 2, Message: "did not find expected ',' or ']'"},
+				{File: "plot.yaml", Line: 2, Message: "content_data: minimum 1 properties allowed, but found 0 properties"},
+				{File: "quiz.yaml", Line: 1, Message: "passing_score: must be between 0 and 100"},
+				{File: "quiz.yaml", Line: 3, Message: "questions.0: missing properties: 'value'"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := coursepack.Read(zipped(t, "", tt.files...))
+			var got coursepack.Problems
+			if !errors.As(err, &got) {
+				t.Fatalf("error = %v, want problems", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("problems:\n%v\nwant:\n%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package coursepack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"mathtermind-go/internal/content"
+	"mathtermind-go/internal/models"
+)
+
+// frontMatterDelimiter opens and closes the front matter of Markdown files.
+const frontMatterDelimiter = "---\n"
+
+// Package is a course package read from an archive.
+type Package struct {
+	// Course is the course with its tags and its lessons and their contents
+	// in order. None of them have IDs.
+	Course *models.Course
+
+	manifest *yamlFile
+}
+
+// TagProblem returns a problem with the i-th tag of the package.
+func (p *Package) TagProblem(i int, message string) Problem {
+	return p.manifest.problem(fmt.Sprintf("tags.%d.name", i), message)
+}
+
+// Read parses and validates a course package. When the package is invalid
+// the error is Problems listing every problem found.
+func Read(data []byte) (*Package, error) {
+	files, err := readArchive(data)
+	if err != nil {
+		return nil, Problems{{Message: err.Error()}}
+	}
+	root, ok := packageRoot(files)
+	if !ok {
+		return nil, Problems{{Message: "archive has no " + ManifestFile}}
+	}
+
+	r := &reader{files: files, root: root}
+	course := r.read()
+	if len(r.problems) > 0 {
+		r.problems.sort()
+		return nil, r.problems
+	}
+	return &Package{Course: course, manifest: r.manifest}, nil
+}
+
+// reader collects the problems of a package while reading it.
+type reader struct {
+	files    map[string][]byte
+	root     string
+	manifest *yamlFile
+	problems Problems
+}
+
+func (r *reader) add(p Problem) {
+	r.problems = append(r.problems, p)
+}
+
+func (r *reader) read() *models.Course {
+	var m manifest
+	mf, ok := r.decode(ManifestFile, r.files[r.root+ManifestFile], 0, &m)
+	if !ok {
+		return nil
+	}
+	r.manifest = mf
+
+	if m.Format != FormatName {
+		r.add(mf.problem("format", "must be "+FormatName))
+	}
+	switch {
+	case m.Version == 0:
+		r.add(mf.problem("version", "is required"))
+	case m.Version < 0 || m.Version > Version:
+		r.add(mf.problem("version", fmt.Sprintf("unsupported version %d, the newest supported is %d", m.Version, Version)))
+	}
+
+	course := &models.Course{
+		Topic:       m.Course.Topic,
+		Name:        m.Course.Name,
+		Description: m.Course.Description,
+		DurationMin: m.Course.DurationMin,
+	}
+	r.checkText(mf, "course.topic", course.Topic, 50)
+	r.checkText(mf, "course.name", course.Name, 255)
+	r.checkText(mf, "course.description", course.Description, 0)
+	if course.DurationMin < 0 {
+		r.add(mf.problem("course.duration_min", "must not be negative"))
+	}
+
+	seen := make(map[string]bool, len(m.Tags))
+	for i, t := range m.Tags {
+		field := fmt.Sprintf("tags.%d", i)
+		if t.Category == "" {
+			t.Category = defaultTagCategory
+		}
+		r.checkText(mf, field+".name", t.Name, 100)
+		r.checkText(mf, field+".category", t.Category, 50)
+		if seen[t.Name] {
+			r.add(mf.problem(field+".name", fmt.Sprintf("duplicate tag %q", t.Name)))
+		}
+		seen[t.Name] = true
+		course.Tags = append(course.Tags, models.Tag{Name: t.Name, Category: t.Category})
+	}
+
+	for i, l := range m.Lessons {
+		field := fmt.Sprintf("lessons.%d", i)
+		lesson := models.Lesson{
+			Title:            l.Title,
+			LessonOrder:      i + 1,
+			EstimatedTimeMin: l.EstimatedTimeMin,
+			PointsReward:     defaultLessonPoints,
+		}
+		if l.PointsReward != nil {
+			lesson.PointsReward = *l.PointsReward
+		}
+		r.checkText(mf, field+".title", lesson.Title, 255)
+		if lesson.EstimatedTimeMin < 0 {
+			r.add(mf.problem(field+".estimated_time_min", "must not be negative"))
+		}
+		if lesson.PointsReward < 0 {
+			r.add(mf.problem(field+".points_reward", "must not be negative"))
+		}
+
+		for j, entry := range l.Contents {
+			if c, ok := r.readContent(fmt.Sprintf("%s.contents.%d", field, j), entry); ok {
+				c.Order = j + 1
+				lesson.Contents = append(lesson.Contents, c)
+			}
+		}
+		course.Lessons = append(course.Lessons, lesson)
+	}
+	return course
+}
+
+// checkText checks a required text field of the manifest that is at most
+// max characters long, or unbounded when max is 0.
+func (r *reader) checkText(f *yamlFile, field, value string, max int) {
+	switch {
+	case strings.TrimSpace(value) == "":
+		r.add(f.problem(field, "is required"))
+	case max > 0 && utf8.RuneCountInString(value) > max:
+		r.add(f.problem(field, fmt.Sprintf("must be at most %d characters", max)))
+	}
+}
+
+// readContent reads the content item of a manifest entry from its file and
+// validates it.
+func (r *reader) readContent(field string, entry contentEntry) (models.Content, bool) {
+	c := models.Content{Title: entry.Title, Description: entry.Description, ContentType: entry.Type}
+
+	name := path.Clean(entry.File)
+	switch {
+	case entry.File == "":
+		r.add(r.manifest.problem(field+".file", "is required"))
+		return c, false
+	case path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../"):
+		r.add(r.manifest.problem(field+".file", "must be a path inside the package"))
+		return c, false
+	}
+	data, ok := r.files[r.root+name]
+	if !ok {
+		r.add(r.manifest.problem(field+".file", fmt.Sprintf("file %s is not in the package", name)))
+		return c, false
+	}
+
+	var f *yamlFile
+	switch c.ContentType {
+	case models.ContentTypeTheory:
+		f, ok = r.readTheory(name, data, &c)
+	case models.ContentTypeExercise:
+		var v exerciseFile
+		if f, ok = r.decode(name, data, 0, &v); ok {
+			c.Exercise = &models.ExerciseContent{EstimatedTime: v.EstimatedTime, Problems: models.JSONB{"problems": v.Problems}}
+		}
+	case models.ContentTypeAssessment:
+		var v assessmentFile
+		if f, ok = r.decode(name, data, 0, &v); ok {
+			a := &models.AssessmentContent{
+				TimeLimit:       v.TimeLimit,
+				Questions:       models.JSONB{"questions": v.Questions},
+				PassingScore:    content.DefaultPassingScore,
+				AttemptsAllowed: content.DefaultAttemptsAllowed,
+			}
+			if v.PassingScore != nil {
+				a.PassingScore = *v.PassingScore
+			}
+			if v.AttemptsAllowed != nil {
+				a.AttemptsAllowed = *v.AttemptsAllowed
+			}
+			c.Assessment = a
+		}
+	case models.ContentTypeInteractive:
+		var v interactiveFile
+		if f, ok = r.decode(name, data, 0, &v); ok {
+			c.Interactive = &models.InteractiveContent{InteractiveType: v.InteractiveType, ContentData: v.ContentData, Config: v.Config}
+		}
+	case models.ContentTypeResource:
+		var v resourceFile
+		if f, ok = r.decode(name, data, 0, &v); ok {
+			c.Resource = &models.ResourceContent{ResourceType: v.ResourceType, URL: v.URL, ResourceMetadata: v.ResourceMetadata}
+		}
+	default:
+		r.add(r.manifest.problem(field+".type", fmt.Sprintf("unknown content type %q", c.ContentType)))
+		return c, false
+	}
+	if !ok {
+		return c, false
+	}
+
+	var errs content.Errors
+	if err := content.Validate(&c); errors.As(err, &errs) {
+		for key, msg := range errs {
+			r.add(r.contentProblem(field, f, c.ContentType, key, msg))
+		}
+		return c, false
+	}
+	return c, true
+}
+
+// contentProblem locates a validation error of a content item: errors of
+// its title and type in its manifest entry, errors of its details in its
+// file.
+func (r *reader) contentProblem(field string, f *yamlFile, t models.ContentType, key, msg string) Problem {
+	rest, ok := strings.CutPrefix(key, string(t)+".")
+	switch {
+	case key == "title":
+		return r.manifest.problem(field+".title", msg)
+	case !ok:
+		return r.manifest.problem(field+".type", msg)
+	}
+	// The problems and questions documents are spread over the file.
+	for _, doc := range []string{"problems", "questions"} {
+		if inner, ok := strings.CutPrefix(rest, doc+"."); ok && strings.HasPrefix(inner, doc) {
+			rest = inner
+		}
+	}
+	return f.problem(rest, msg)
+}
+
+// readTheory reads a theory item from Markdown with optional front matter.
+func (r *reader) readTheory(name string, data []byte, c *models.Content) (*yamlFile, bool) {
+	theory := &models.TheoryContent{}
+	f := &yamlFile{name: name}
+	body, bodyLine := data, 1
+
+	if bytes.HasPrefix(data, []byte(frontMatterDelimiter)) {
+		rest := data[len(frontMatterDelimiter):]
+		var front []byte
+		if bytes.HasPrefix(rest, []byte(frontMatterDelimiter)) {
+			body = rest[len(frontMatterDelimiter):]
+		} else {
+			end := bytes.Index(rest, []byte("\n"+frontMatterDelimiter))
+			if end < 0 {
+				r.add(Problem{File: name, Line: 1, Message: "front matter is not closed by a --- line"})
+				return nil, false
+			}
+			front = rest[:end+1]
+			body = rest[end+1+len(frontMatterDelimiter):]
+		}
+		bodyLine = bytes.Count(data[:len(data)-len(body)], []byte("\n")) + 1
+
+		if len(bytes.TrimSpace(front)) > 0 {
+			var v theoryFrontMatter
+			var ok bool
+			if f, ok = r.decode(name, front, 1, &v); !ok {
+				return nil, false
+			}
+			theory.Examples, theory.References = v.Examples, v.References
+		}
+	}
+
+	theory.TextContent = string(body)
+	f.bodyLine = bodyLine
+	c.Theory = theory
+	return f, true
+}
+
+// yamlLine matches the line reported in YAML errors.
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// unknownField matches the error of a field a file type does not have.
+var unknownField = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+
+// decode decodes a YAML file into v, reporting unknown fields. offset is
+// the number of lines preceding the YAML document in the file.
+func (r *reader) decode(name string, data []byte, offset int, v any) (*yamlFile, bool) {
+	f := &yamlFile{name: name, offset: offset}
+	if err := yaml.Unmarshal(data, &f.doc); err != nil {
+		r.add(f.errorProblem(err.Error()))
+		return nil, false
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err := dec.Decode(v)
+	if errors.Is(err, io.EOF) {
+		r.add(Problem{File: name, Message: "file is empty"})
+		return nil, false
+	}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		for _, msg := range typeErr.Errors {
+			r.add(f.errorProblem(msg))
+		}
+		return nil, false
+	}
+	if err != nil {
+		r.add(f.errorProblem(err.Error()))
+		return nil, false
+	}
+	return f, true
+}
+
+// yamlFile locates values of a YAML file by their path.
+type yamlFile struct {
+	name   string
+	doc    yaml.Node
+	offset int
+	// bodyLine is the first line of the Markdown body of theory files.
+	bodyLine int
+}
+
+// errorProblem converts a YAML error message into a problem at the line it
+// names.
+func (f *yamlFile) errorProblem(msg string) Problem {
+	p := Problem{File: f.name, Message: msg}
+	if m := yamlLine.FindStringSubmatch(msg); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		p.Line, p.Message = n+f.offset, m[2]
+	}
+	if m := unknownField.FindStringSubmatch(p.Message); m != nil {
+		p.Message = "unknown field " + m[1]
+	}
+	return p
+}
+
+// problem returns a problem with the value at a dotted path, such as
+// "lessons.0.title", reported at the line of the value or of its closest
+// enclosing value in the file.
+func (f *yamlFile) problem(field, msg string) Problem {
+	p := Problem{File: f.name, Message: field + ": " + msg}
+	if field == "text_content" && f.bodyLine > 0 {
+		p.Line = f.bodyLine
+		return p
+	}
+	if len(f.doc.Content) == 0 {
+		return p
+	}
+
+	node := f.doc.Content[0]
+	line := node.Line
+	for _, seg := range strings.Split(field, ".") {
+		node = child(node, seg)
+		if node == nil {
+			break
+		}
+		line = node.Line
+	}
+	p.Line = line + f.offset
+	return p
+}
+
+// child returns the value under a key of a mapping or an index of a
+// sequence, or nil.
+func child(n *yaml.Node, seg string) *yaml.Node {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == seg {
+				return n.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(seg); err == nil && i >= 0 && i < len(n.Content) {
+			return n.Content[i]
+		}
+	}
+	return nil
+}
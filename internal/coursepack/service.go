@@ -0,0 +1,153 @@
+package coursepack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/repository"
+)
+
+// ImportOptions control how a package is imported.
+type ImportOptions struct {
+	// Owner becomes the creator of the course and of its resources.
+	Owner uuid.UUID
+	// CreateTags allows the package to create the tags that do not exist
+	// yet; otherwise they are reported as problems.
+	CreateTags bool
+	// DryRun validates the package without importing it.
+	DryRun bool
+}
+
+// ImportResult is the outcome of an import. Course is only set when the
+// package was imported.
+type ImportResult struct {
+	DryRun   bool           `json:"dry_run"`
+	Valid    bool           `json:"valid"`
+	Course   *models.Course `json:"course,omitempty"`
+	Problems Problems       `json:"problems,omitempty"`
+}
+
+// Service imports course packages.
+type Service struct {
+	repos *repository.Repositories
+}
+
+// NewService creates a course package service.
+func NewService(repos *repository.Repositories) *Service {
+	return &Service{repos: repos}
+}
+
+// Import reads a package and, unless it is invalid or opts.DryRun is set,
+// creates its course with its lessons, contents and tags in one
+// transaction. Problems with the package are reported in the result; the
+// error is only set when the import failed.
+func (s *Service) Import(ctx context.Context, data []byte, opts ImportOptions) (*ImportResult, error) {
+	res := &ImportResult{DryRun: opts.DryRun}
+	pkg, err := Read(data)
+	if err != nil {
+		if !errors.As(err, &res.Problems) {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	run := s.repos.Tx.WithTx
+	if opts.DryRun {
+		run = s.repos.Tx.WithReadOnlyTx
+	}
+	err = run(ctx, func(ctx context.Context) error {
+		tagIDs, err := s.resolveTags(ctx, pkg, opts)
+		if err != nil || opts.DryRun {
+			return err
+		}
+		res.Course, err = s.create(ctx, pkg.Course, tagIDs, opts.Owner)
+		return err
+	})
+	if errors.As(err, &res.Problems) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.Valid = true
+	return res, nil
+}
+
+// resolveTags returns the IDs of the tags of a package, creating the
+// missing ones when allowed. Missing tags that may not be created are
+// returned as Problems.
+func (s *Service) resolveTags(ctx context.Context, pkg *Package, opts ImportOptions) ([]uuid.UUID, error) {
+	var (
+		ids      []uuid.UUID
+		problems Problems
+	)
+	for i, t := range pkg.Course.Tags {
+		tag, err := s.repos.Tags.GetByName(ctx, t.Name)
+		switch {
+		case err == nil:
+			ids = append(ids, tag.ID)
+		case !errors.Is(err, db.ErrNotFound):
+			return nil, err
+		case !opts.CreateTags:
+			problems = append(problems, pkg.TagProblem(i, fmt.Sprintf("tag %q does not exist", t.Name)))
+		case !opts.DryRun:
+			tag, err := s.repos.Tags.Create(ctx, t.Name, t.Category)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, tag.ID)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return ids, nil
+}
+
+// create stores the course of a package and returns it as stored.
+func (s *Service) create(ctx context.Context, c *models.Course, tagIDs []uuid.UUID, owner uuid.UUID) (*models.Course, error) {
+	course, err := s.repos.Courses.Create(ctx, models.Course{
+		Topic:       c.Topic,
+		Name:        c.Name,
+		Description: c.Description,
+		DurationMin: c.DurationMin,
+		CreatedBy:   &owner,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range tagIDs {
+		if err := s.repos.Tags.Attach(ctx, course.ID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, l := range c.Lessons {
+		lesson, err := s.repos.Lessons.Create(ctx, models.Lesson{
+			CourseID:         course.ID,
+			Title:            l.Title,
+			LessonOrder:      l.LessonOrder,
+			EstimatedTimeMin: l.EstimatedTimeMin,
+			PointsReward:     l.PointsReward,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range l.Contents {
+			item.LessonID = lesson.ID
+			if item.Resource != nil {
+				item.Resource.CreatedBy = &owner
+			}
+			if _, err := s.repos.Contents.Create(ctx, item); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.repos.Courses.Get(ctx, course.ID)
+}
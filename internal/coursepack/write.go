@@ -0,0 +1,138 @@
+package coursepack
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"mathtermind-go/internal/models"
+)
+
+// Write writes a course with its lessons, contents and tags as a package in
+// the given format. Files are dated at the course's last update, so that
+// exporting an unchanged course produces the same archive.
+func Write(w io.Writer, course *models.Course, f Format) error {
+	m := manifest{
+		Format:  FormatName,
+		Version: Version,
+		Course: courseEntry{
+			Topic:       course.Topic,
+			Name:        course.Name,
+			Description: course.Description,
+			DurationMin: course.DurationMin,
+		},
+		Lessons: []lessonEntry{},
+	}
+	for _, t := range course.Tags {
+		m.Tags = append(m.Tags, tagEntry{Name: t.Name, Category: t.Category})
+	}
+
+	var files []file
+	for i, l := range course.Lessons {
+		points := l.PointsReward
+		entry := lessonEntry{
+			Title:            l.Title,
+			EstimatedTimeMin: l.EstimatedTimeMin,
+			PointsReward:     &points,
+			Contents:         []contentEntry{},
+		}
+		for j, c := range l.Contents {
+			name, data, err := contentFile(&c)
+			if err != nil {
+				return fmt.Errorf("content %s: %w", c.ID, err)
+			}
+			name = fmt.Sprintf("lessons/%02d/%02d-%s", i+1, j+1, name)
+			entry.Contents = append(entry.Contents, contentEntry{
+				Title:       c.Title,
+				Description: c.Description,
+				Type:        c.ContentType,
+				File:        name,
+			})
+			files = append(files, file{name: name, data: data})
+		}
+		m.Lessons = append(m.Lessons, entry)
+	}
+
+	data, err := marshalYAML(m)
+	if err != nil {
+		return err
+	}
+	files = append([]file{{name: ManifestFile, data: data}}, files...)
+	return writeArchive(w, f, files, course.UpdatedAt)
+}
+
+// contentFile returns the base name and data of the file holding the
+// details of a content item.
+func contentFile(c *models.Content) (string, []byte, error) {
+	name := slug(c.Title, string(c.ContentType))
+	var v any
+	switch {
+	case c.Theory != nil:
+		data, err := theoryFile(c.Theory)
+		return name + ".md", data, err
+	case c.Exercise != nil:
+		v = exerciseFile{EstimatedTime: c.Exercise.EstimatedTime, Problems: c.Exercise.Problems["problems"]}
+	case c.Assessment != nil:
+		a := c.Assessment
+		v = assessmentFile{
+			TimeLimit:       a.TimeLimit,
+			PassingScore:    &a.PassingScore,
+			AttemptsAllowed: &a.AttemptsAllowed,
+			Questions:       a.Questions["questions"],
+		}
+	case c.Interactive != nil:
+		v = interactiveFile{
+			InteractiveType: c.Interactive.InteractiveType,
+			ContentData:     c.Interactive.ContentData,
+			Config:          c.Interactive.Config,
+		}
+	case c.Resource != nil:
+		v = resourceFile{
+			ResourceType:     c.Resource.ResourceType,
+			URL:              c.Resource.URL,
+			ResourceMetadata: c.Resource.ResourceMetadata,
+		}
+	default:
+		return "", nil, fmt.Errorf("no details for %s content", c.ContentType)
+	}
+	data, err := marshalYAML(v)
+	return name + ".yaml", data, err
+}
+
+// theoryFile returns the Markdown text of a theory item, preceded by front
+// matter when it has examples or references. Front matter is also written
+// when the text itself starts like front matter, so that it reads back
+// unchanged.
+func theoryFile(t *models.TheoryContent) ([]byte, error) {
+	var b bytes.Buffer
+	switch {
+	case t.Examples != nil || t.References != nil:
+		front, err := marshalYAML(theoryFrontMatter{Examples: t.Examples, References: t.References})
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(frontMatterDelimiter)
+		b.Write(front)
+		b.WriteString(frontMatterDelimiter)
+	case strings.HasPrefix(t.TextContent, frontMatterDelimiter):
+		b.WriteString(frontMatterDelimiter + frontMatterDelimiter)
+	}
+	b.WriteString(t.TextContent)
+	return b.Bytes(), nil
+}
+
+func marshalYAML(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+const achievementColumns = `id, key, name, description, icon, rule, points, is_active, created_at, updated_at`
+
+func scanAchievement(row pgx.Row) (*models.Achievement, error) {
+	var a models.Achievement
+	err := row.Scan(
+		&a.ID,
+		&a.Key,
+		&a.Name,
+		&a.Description,
+		&a.Icon,
+		&a.Rule,
+		&a.Points,
+		&a.IsActive,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListAchievements returns the active achievements, ordered by name.
+func ListAchievements(ctx context.Context, q Querier) ([]models.Achievement, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+achievementColumns+` FROM achievements WHERE is_active ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	achievements := []models.Achievement{}
+	for rows.Next() {
+		a, err := scanAchievement(rows)
+		if err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, *a)
+	}
+	return achievements, rows.Err()
+}
+
+// ListUnearnedAchievements returns the active achievements the user has not earned yet.
+func ListUnearnedAchievements(ctx context.Context, q Querier, userID uuid.UUID) ([]models.Achievement, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+achievementColumns+`
+		FROM achievements a
+		WHERE is_active AND NOT EXISTS (
+			SELECT 1 FROM user_achievements ua WHERE ua.achievement_id = a.id AND ua.user_id = $1
+		)
+		ORDER BY key
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	achievements := []models.Achievement{}
+	for rows.Next() {
+		a, err := scanAchievement(rows)
+		if err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, *a)
+	}
+	return achievements, rows.Err()
+}
+
+// InsertUserAchievement records that the user earned an achievement. It
+// returns ErrNotFound when the user already has it.
+func InsertUserAchievement(ctx context.Context, q Querier, userID, achievementID uuid.UUID, courseID *uuid.UUID) (*models.UserAchievement, error) {
+	var ua models.UserAchievement
+	err := q.QueryRow(ctx, `
+		INSERT INTO user_achievements (user_id, achievement_id, course_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, achievement_id) DO NOTHING
+		RETURNING id, user_id, achievement_id, course_id, awarded_at
+	`, userID, achievementID, courseID).Scan(&ua.ID, &ua.UserID, &ua.AchievementID, &ua.CourseID, &ua.AwardedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ua, nil
+}
+
+// ListUserAchievements returns the achievements the user earned, newest first.
+func ListUserAchievements(ctx context.Context, q Querier, userID uuid.UUID) ([]models.UserAchievement, error) {
+	rows, err := q.Query(ctx, `
+		SELECT ua.id, ua.user_id, ua.achievement_id, ua.course_id, ua.awarded_at,
+			a.id, a.key, a.name, a.description, a.icon, a.rule, a.points, a.is_active, a.created_at, a.updated_at
+		FROM user_achievements ua
+		JOIN achievements a ON a.id = ua.achievement_id
+		WHERE ua.user_id = $1
+		ORDER BY ua.awarded_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	earned := []models.UserAchievement{}
+	for rows.Next() {
+		var ua models.UserAchievement
+		var a models.Achievement
+		if err := rows.Scan(
+			&ua.ID, &ua.UserID, &ua.AchievementID, &ua.CourseID, &ua.AwardedAt,
+			&a.ID, &a.Key, &a.Name, &a.Description, &a.Icon, &a.Rule, &a.Points, &a.IsActive, &a.CreatedAt, &a.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		ua.Achievement = &a
+		earned = append(earned, ua)
+	}
+	return earned, rows.Err()
+}
+
+// SetCourseAchievements stores the achievements the user earned in a course
+// on the course completion record.
+func SetCourseAchievements(ctx context.Context, q Querier, userID, courseID uuid.UUID) error {
+	_, err := q.Exec(ctx, `
+		UPDATE completed_courses SET
+			achievements_earned = ARRAY(
+				SELECT achievement_id FROM user_achievements
+				WHERE user_id = $1 AND course_id = $2
+				ORDER BY awarded_at
+			),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND course_id = $2
+	`, userID, courseID)
+	return err
+}
+
+// CountCompletedLessons returns how many lessons the user completed since
+// the given time. A zero time counts all completed lessons.
+func CountCompletedLessons(ctx context.Context, q Querier, userID uuid.UUID, since time.Time) (int, error) {
+	var n int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*) FROM completed_lessons WHERE user_id = $1 AND completed_at >= $2
+	`, userID, since).Scan(&n)
+	return n, err
+}
+
+// CountCompletedCourses returns how many courses the user completed.
+func CountCompletedCourses(ctx context.Context, q Querier, userID uuid.UUID) (int, error) {
+	var n int
+	err := q.QueryRow(ctx, `SELECT COUNT(*) FROM completed_courses WHERE user_id = $1`, userID).Scan(&n)
+	return n, err
+}
+
+// ListActivityDays returns the days since from, oldest first and taken in
+// the given timezone, on which the user studied, completed a lesson or
+// answered a question.
+func ListActivityDays(ctx context.Context, q Querier, userID uuid.UUID, timezone string, from time.Time) ([]time.Time, error) {
+	rows, err := q.Query(ctx, `
+		SELECT day FROM (
+			SELECT (started_at AT TIME ZONE $2)::date AS day FROM study_sessions
+			WHERE user_id = $1 AND duration_sec > 0
+			UNION
+			SELECT (completed_at AT TIME ZONE $2)::date FROM completed_lessons WHERE user_id = $1
+			UNION
+			SELECT (created_at AT TIME ZONE $2)::date FROM user_answers WHERE user_id = $1
+		) days
+		WHERE day >= $3::date
+		ORDER BY day
+	`, userID, timezone, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
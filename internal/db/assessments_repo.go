@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+const attemptColumns = `id, user_id, content_id, attempt_number, started_at, deadline_at,
+	submitted_at, score, passed, created_at, updated_at`
+
+func scanAttempt(row pgx.Row) (*models.AssessmentAttempt, error) {
+	var a models.AssessmentAttempt
+	err := row.Scan(
+		&a.ID,
+		&a.UserID,
+		&a.ContentID,
+		&a.AttemptNumber,
+		&a.StartedAt,
+		&a.DeadlineAt,
+		&a.SubmittedAt,
+		&a.Score,
+		&a.Passed,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// CountAttempts returns how many attempts the user has started on an assessment.
+func CountAttempts(ctx context.Context, q Querier, userID, contentID uuid.UUID) (int, error) {
+	var n int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*) FROM assessment_attempts WHERE user_id = $1 AND content_id = $2
+	`, userID, contentID).Scan(&n)
+	return n, err
+}
+
+// GetOpenAttempt returns the user's unsubmitted attempt whose deadline has
+// not passed, or ErrNotFound.
+func GetOpenAttempt(ctx context.Context, q Querier, userID, contentID uuid.UUID) (*models.AssessmentAttempt, error) {
+	return scanAttempt(q.QueryRow(ctx, `
+		SELECT `+attemptColumns+`
+		FROM assessment_attempts
+		WHERE user_id = $1 AND content_id = $2 AND submitted_at IS NULL
+			AND (deadline_at IS NULL OR deadline_at > CURRENT_TIMESTAMP)
+		ORDER BY attempt_number DESC
+		LIMIT 1
+	`, userID, contentID))
+}
+
+// CreateAttempt starts a new attempt. A nil deadline means no time limit.
+func CreateAttempt(ctx context.Context, q Querier, userID, contentID uuid.UUID, number int, deadline *time.Time) (*models.AssessmentAttempt, error) {
+	return scanAttempt(q.QueryRow(ctx, `
+		INSERT INTO assessment_attempts (user_id, content_id, attempt_number, deadline_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+attemptColumns,
+		userID, contentID, number, deadline))
+}
+
+// LockAttempt returns an attempt and locks it for the rest of the transaction.
+func LockAttempt(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*models.AssessmentAttempt, error) {
+	return scanAttempt(tx.QueryRow(ctx, `
+		SELECT `+attemptColumns+` FROM assessment_attempts WHERE id = $1 FOR UPDATE
+	`, id))
+}
+
+// FinishAttempt records the result of a submitted attempt.
+func FinishAttempt(ctx context.Context, q Querier, id uuid.UUID, score float64, passed bool) (*models.AssessmentAttempt, error) {
+	return scanAttempt(q.QueryRow(ctx, `
+		UPDATE assessment_attempts SET
+			submitted_at = CURRENT_TIMESTAMP,
+			score = $2,
+			passed = $3,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+attemptColumns,
+		id, score, passed))
+}
+
+// InsertUserAnswer stores a graded answer and fills in its ID and timestamps.
+func InsertUserAnswer(ctx context.Context, q Querier, a *models.UserAnswer) error {
+	return q.QueryRow(ctx, `
+		INSERT INTO user_answers (user_id, content_id, question_id, answer_data, is_correct, points_earned, attempt_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`, a.UserID, a.ContentID, a.QuestionID, a.AnswerData, a.IsCorrect, a.PointsEarned, a.AttemptID).Scan(
+		&a.ID, &a.CreatedAt, &a.UpdatedAt,
+	)
+}
+
+// HasCorrectAnswer reports whether the user has already answered a question correctly.
+func HasCorrectAnswer(ctx context.Context, q Querier, userID, contentID uuid.UUID, questionID string) (bool, error) {
+	var exists bool
+	err := q.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM user_answers
+			WHERE user_id = $1 AND content_id = $2 AND question_id = $3 AND is_correct
+		)
+	`, userID, contentID, questionID).Scan(&exists)
+	return exists, err
+}
+
+// CountCorrectlyAnswered returns how many of the given questions the user
+// has answered correctly at least once.
+func CountCorrectlyAnswered(ctx context.Context, q Querier, userID, contentID uuid.UUID, questionIDs []string) (int, error) {
+	var n int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT question_id) FROM user_answers
+		WHERE user_id = $1 AND content_id = $2 AND is_correct AND question_id = ANY($3)
+	`, userID, contentID, questionIDs).Scan(&n)
+	return n, err
+}
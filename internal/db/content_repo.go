@@ -0,0 +1,354 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"mathtermind-go/internal/models"
+)
+
+// ErrContentTypeMismatch is returned when a write carries details of a
+// type other than the content type.
+var ErrContentTypeMismatch = errors.New("content details do not match the content type")
+
+// contentSelect selects a content row together with every type-specific
+// table. Only the table matching content_type is expected to produce values.
+const contentSelect = `
+	SELECT c.id, c.lesson_id, c.title, c.description, c."order", c.content_type, c.created_at, c.updated_at,
+		t.id, t.text_content, t.examples, t."references",
+		e.id, e.problems, e.estimated_time_min,
+		a.id, a.questions, a.time_limit_min, a.passing_score, a.attempts_allowed,
+		i.id, i.interactive_type, i.content_data, i.config,
+		r.id, r.resource_type, r.url, r.created_by, r.resource_metadata
+	FROM content c
+	LEFT JOIN theory_content t ON t.id = c.id
+	LEFT JOIN exercise_content e ON e.id = c.id
+	LEFT JOIN assessment_content a ON a.id = c.id
+	LEFT JOIN interactive_content i ON i.id = c.id
+	LEFT JOIN resource_content r ON r.id = c.id
+`
+
+// scanContent scans a row produced by contentSelect into a Content with the
+// matching type-specific field populated.
+func scanContent(row pgx.Row) (models.Content, error) {
+	var (
+		c models.Content
+
+		theoryID   *uuid.UUID
+		theoryText *string
+		theory     models.TheoryContent
+		exerciseID *uuid.UUID
+		exercise   models.ExerciseContent
+		assessID   *uuid.UUID
+		assess     models.AssessmentContent
+		passing    *float64
+		attempts   *int
+		interID    *uuid.UUID
+		interType  *string
+		inter      models.InteractiveContent
+		resourceID *uuid.UUID
+		resType    *string
+		resURL     *string
+		resource   models.ResourceContent
+	)
+
+	err := row.Scan(
+		&c.ID, &c.LessonID, &c.Title, &c.Description, &c.Order, &c.ContentType, &c.CreatedAt, &c.UpdatedAt,
+		&theoryID, &theoryText, &theory.Examples, &theory.References,
+		&exerciseID, &exercise.Problems, &exercise.EstimatedTime,
+		&assessID, &assess.Questions, &assess.TimeLimit, &passing, &attempts,
+		&interID, &interType, &inter.ContentData, &inter.Config,
+		&resourceID, &resType, &resURL, &resource.CreatedBy, &resource.ResourceMetadata,
+	)
+	if err != nil {
+		return c, err
+	}
+
+	switch {
+	case c.ContentType == models.ContentTypeTheory && theoryID != nil:
+		theory.ID = *theoryID
+		theory.TextContent = *theoryText
+		c.Theory = &theory
+	case c.ContentType == models.ContentTypeExercise && exerciseID != nil:
+		exercise.ID = *exerciseID
+		c.Exercise = &exercise
+	case c.ContentType == models.ContentTypeAssessment && assessID != nil:
+		assess.ID = *assessID
+		assess.PassingScore = *passing
+		assess.AttemptsAllowed = *attempts
+		c.Assessment = &assess
+	case c.ContentType == models.ContentTypeInteractive && interID != nil:
+		inter.ID = *interID
+		inter.InteractiveType = *interType
+		c.Interactive = &inter
+	case c.ContentType == models.ContentTypeResource && resourceID != nil:
+		resource.ID = *resourceID
+		resource.ResourceType = *resType
+		resource.URL = *resURL
+		c.Resource = &resource
+	}
+	return c, nil
+}
+
+// GetContent returns a content item with its type-specific details.
+func GetContent(ctx context.Context, q Querier, id uuid.UUID) (*models.Content, error) {
+	c, err := scanContent(q.QueryRow(ctx, contentSelect+` WHERE c.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Content orders within a lesson are kept contiguous, starting at 1, the
+// same way lesson orders are within a course. Writes lock the parent lesson
+// row to serialize concurrent edits of the same lesson.
+
+// ContentPatch holds the content fields to change; nil fields are left
+// untouched. A non-nil type-specific field replaces the details of that
+// type, which must be the type of the content.
+type ContentPatch struct {
+	Title       *string
+	Description *string
+	Order       *int
+
+	Theory      *models.TheoryContent
+	Exercise    *models.ExerciseContent
+	Assessment  *models.AssessmentContent
+	Interactive *models.InteractiveContent
+	Resource    *models.ResourceContent
+}
+
+// lockLesson locks the lesson row for the rest of the transaction.
+func lockLesson(ctx context.Context, tx pgx.Tx, lessonID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM lessons WHERE id = $1 FOR UPDATE`, lessonID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func countContents(ctx context.Context, tx pgx.Tx, lessonID uuid.UUID) (int, error) {
+	var n int
+	err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM content WHERE lesson_id = $1`, lessonID).Scan(&n)
+	return n, err
+}
+
+// ListLessonContents returns the contents of a lesson in order, each with
+// its type-specific details.
+func ListLessonContents(ctx context.Context, q Querier, lessonID uuid.UUID) ([]models.Content, error) {
+	rows, err := q.Query(ctx, contentSelect+`
+		WHERE c.lesson_id = $1
+		ORDER BY c."order", c.created_at
+	`, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contents := []models.Content{}
+	for rows.Next() {
+		c, err := scanContent(rows)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, c)
+	}
+	return contents, rows.Err()
+}
+
+// CreateContent inserts a content item and the details of its type. An
+// Order of zero appends the item to the lesson; otherwise it is inserted at
+// that position and later items are shifted down.
+func CreateContent(ctx context.Context, c Conn, content models.Content) (*models.Content, error) {
+	var created *models.Content
+	err := pgx.BeginFunc(ctx, c, func(tx pgx.Tx) error {
+		if err := lockLesson(ctx, tx, content.LessonID); err != nil {
+			return err
+		}
+		n, err := countContents(ctx, tx, content.LessonID)
+		if err != nil {
+			return err
+		}
+		if content.Order == 0 {
+			content.Order = n + 1
+		}
+		content.Order = clampOrder(content.Order, n+1)
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE content SET "order" = "order" + 1
+			WHERE lesson_id = $1 AND "order" >= $2
+		`, content.LessonID, content.Order); err != nil {
+			return err
+		}
+
+		var id uuid.UUID
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO content (lesson_id, title, description, "order", content_type)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, content.LessonID, content.Title, content.Description, content.Order, content.ContentType).Scan(&id); err != nil {
+			return err
+		}
+		if err := insertContentDetails(ctx, tx, id, &content); err != nil {
+			return err
+		}
+
+		created, err = GetContent(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// insertContentDetails inserts the type-specific row of a content item.
+func insertContentDetails(ctx context.Context, tx pgx.Tx, id uuid.UUID, c *models.Content) error {
+	var err error
+	switch {
+	case c.Theory != nil:
+		_, err = tx.Exec(ctx, `
+			INSERT INTO theory_content (id, text_content, examples, "references")
+			VALUES ($1, $2, $3, $4)
+		`, id, c.Theory.TextContent, c.Theory.Examples, c.Theory.References)
+	case c.Exercise != nil:
+		_, err = tx.Exec(ctx, `
+			INSERT INTO exercise_content (id, problems, estimated_time_min)
+			VALUES ($1, $2, $3)
+		`, id, c.Exercise.Problems, c.Exercise.EstimatedTime)
+	case c.Assessment != nil:
+		_, err = tx.Exec(ctx, `
+			INSERT INTO assessment_content (id, questions, time_limit_min, passing_score, attempts_allowed)
+			VALUES ($1, $2, $3, $4, $5)
+		`, id, c.Assessment.Questions, c.Assessment.TimeLimit, c.Assessment.PassingScore, c.Assessment.AttemptsAllowed)
+	case c.Interactive != nil:
+		_, err = tx.Exec(ctx, `
+			INSERT INTO interactive_content (id, interactive_type, content_data, config)
+			VALUES ($1, $2, $3, $4)
+		`, id, c.Interactive.InteractiveType, c.Interactive.ContentData, c.Interactive.Config)
+	case c.Resource != nil:
+		_, err = tx.Exec(ctx, `
+			INSERT INTO resource_content (id, resource_type, url, created_by, resource_metadata)
+			VALUES ($1, $2, $3, $4, $5)
+		`, id, c.Resource.ResourceType, c.Resource.URL, c.Resource.CreatedBy, c.Resource.ResourceMetadata)
+	}
+	return err
+}
+
+// UpdateContent applies a patch to a content item. Changing Order moves the
+// item to that position and shifts the items in between; type-specific
+// details in the patch replace the stored ones.
+func UpdateContent(ctx context.Context, c Conn, id uuid.UUID, p ContentPatch) (*models.Content, error) {
+	var updated *models.Content
+	err := pgx.BeginFunc(ctx, c, func(tx pgx.Tx) error {
+		var lessonID uuid.UUID
+		err := tx.QueryRow(ctx, `SELECT lesson_id FROM content WHERE id = $1`, id).Scan(&lessonID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := lockLesson(ctx, tx, lessonID); err != nil {
+			return err
+		}
+
+		if p.Order != nil {
+			// Read under the lesson lock, another request may have moved it.
+			var current int
+			if err := tx.QueryRow(ctx, `SELECT "order" FROM content WHERE id = $1`, id).Scan(&current); err != nil {
+				return err
+			}
+			n, err := countContents(ctx, tx, lessonID)
+			if err != nil {
+				return err
+			}
+			target := clampOrder(*p.Order, n)
+			if target != current {
+				if _, err := tx.Exec(ctx, `
+					UPDATE content SET "order" = CASE
+						WHEN id = $2 THEN $4
+						WHEN $4 < $3 THEN "order" + 1
+						ELSE "order" - 1
+					END
+					WHERE lesson_id = $1 AND "order" BETWEEN LEAST($3, $4) AND GREATEST($3, $4)
+				`, lessonID, id, current, target); err != nil {
+					return err
+				}
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE content SET
+				title = COALESCE($2, title),
+				description = COALESCE($3, description),
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`, id, p.Title, p.Description); err != nil {
+			return err
+		}
+		if err := updateContentDetails(ctx, tx, id, p); err != nil {
+			return err
+		}
+
+		updated, err = GetContent(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// updateContentDetails replaces the type-specific row of a content item with
+// the details set in the patch, if any.
+func updateContentDetails(ctx context.Context, tx pgx.Tx, id uuid.UUID, p ContentPatch) error {
+	var (
+		tag pgconn.CommandTag
+		err error
+	)
+	switch {
+	case p.Theory != nil:
+		tag, err = tx.Exec(ctx, `
+			UPDATE theory_content SET text_content = $2, examples = $3, "references" = $4
+			WHERE id = $1
+		`, id, p.Theory.TextContent, p.Theory.Examples, p.Theory.References)
+	case p.Exercise != nil:
+		tag, err = tx.Exec(ctx, `
+			UPDATE exercise_content SET problems = $2, estimated_time_min = $3
+			WHERE id = $1
+		`, id, p.Exercise.Problems, p.Exercise.EstimatedTime)
+	case p.Assessment != nil:
+		tag, err = tx.Exec(ctx, `
+			UPDATE assessment_content SET questions = $2, time_limit_min = $3, passing_score = $4, attempts_allowed = $5
+			WHERE id = $1
+		`, id, p.Assessment.Questions, p.Assessment.TimeLimit, p.Assessment.PassingScore, p.Assessment.AttemptsAllowed)
+	case p.Interactive != nil:
+		tag, err = tx.Exec(ctx, `
+			UPDATE interactive_content SET interactive_type = $2, content_data = $3, config = $4
+			WHERE id = $1
+		`, id, p.Interactive.InteractiveType, p.Interactive.ContentData, p.Interactive.Config)
+	case p.Resource != nil:
+		tag, err = tx.Exec(ctx, `
+			UPDATE resource_content SET resource_type = $2, url = $3, resource_metadata = $4
+			WHERE id = $1
+		`, id, p.Resource.ResourceType, p.Resource.URL, p.Resource.ResourceMetadata)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrContentTypeMismatch
+	}
+	return nil
+}
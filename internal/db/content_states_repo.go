@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+const contentStateColumns = `id, user_id, progress_id, content_id, state_type, numeric_value,
+	json_value, text_value, created_at, updated_at`
+
+func scanContentState(row pgx.Row) (*models.ContentState, error) {
+	var s models.ContentState
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.ProgressID,
+		&s.ContentID,
+		&s.StateType,
+		&s.NumericValue,
+		&s.JSONValue,
+		&s.TextValue,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpsertContentState saves a piece of resumable state, replacing any value
+// previously saved under the same state type.
+func UpsertContentState(ctx context.Context, q Querier, s models.ContentState) (*models.ContentState, error) {
+	return scanContentState(q.QueryRow(ctx, `
+		INSERT INTO content_states (user_id, progress_id, content_id, state_type, numeric_value, json_value, text_value)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, content_id, state_type) DO UPDATE SET
+			progress_id = EXCLUDED.progress_id,
+			numeric_value = EXCLUDED.numeric_value,
+			json_value = EXCLUDED.json_value,
+			text_value = EXCLUDED.text_value,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING `+contentStateColumns,
+		s.UserID, s.ProgressID, s.ContentID, s.StateType, s.NumericValue, s.JSONValue, s.TextValue))
+}
+
+// ListContentStates returns all state the user saved for a content item.
+func ListContentStates(ctx context.Context, q Querier, userID, contentID uuid.UUID) ([]models.ContentState, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+contentStateColumns+`
+		FROM content_states
+		WHERE user_id = $1 AND content_id = $2
+		ORDER BY state_type
+	`, userID, contentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := []models.ContentState{}
+	for rows.Next() {
+		s, err := scanContentState(rows)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, *s)
+	}
+	return states, rows.Err()
+}
+
+// DeleteContentState removes one piece of saved state.
+func DeleteContentState(ctx context.Context, q Querier, userID, contentID uuid.UUID, stateType string) error {
+	tag, err := q.Exec(ctx, `
+		DELETE FROM content_states WHERE user_id = $1 AND content_id = $2 AND state_type = $3
+	`, userID, contentID, stateType)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetLastContentProgress returns the content progress the user interacted
+// with most recently, or ErrNotFound if there is none.
+func GetLastContentProgress(ctx context.Context, q Querier, userID uuid.UUID) (*models.UserContentProgress, error) {
+	return scanContentProgress(q.QueryRow(ctx, `
+		SELECT `+contentProgressColumns+`
+		FROM user_content_progress
+		WHERE user_id = $1
+		ORDER BY last_interaction DESC
+		LIMIT 1
+	`, userID))
+}
@@ -2,25 +2,113 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/pagination"
 )
 
-// ListCourses returns a paginated list of courses.
-func ListCourses(ctx context.Context, pool *pgxpool.Pool, limit, offset int) ([]models.Course, error) {
-	rows, err := pool.Query(ctx, `
-		SELECT id, topic, name, description, duration_min, created_at, updated_at
-		FROM courses
-		ORDER BY created_at DESC
+// CourseFilter narrows the courses returned by ListCourses; zero fields
+// do not filter.
+type CourseFilter struct {
+	Topic string
+	// Tags are tag names. A course matches when it has any of them, or all
+	// of them when MatchAllTags is set.
+	Tags         []string
+	MatchAllTags bool
+}
+
+// CourseSorts are the fields courses can be sorted by.
+var CourseSorts = []string{"name", "duration_min", "created_at"}
+
+// DefaultCourseSort lists the newest courses first.
+var DefaultCourseSort = pagination.Sort{Field: "created_at", Desc: true}
+
+// courseSortColumns maps the course sort fields to their columns and the
+// SQL types cursor keys are compared as.
+var courseSortColumns = map[string]struct{ column, typ string }{
+	"name":         {"c.name", "text"},
+	"duration_min": {"c.duration_min", "integer"},
+	"created_at":   {"c.created_at", "timestamptz"},
+}
+
+// courseSortKey returns the value of a sort field of a course.
+func courseSortKey(c models.Course, field string) any {
+	switch field {
+	case "name":
+		return c.Name
+	case "duration_min":
+		return c.DurationMin
+	default:
+		return c.CreatedAt
+	}
+}
+
+// CoursePage selects a page of courses.
+type CoursePage struct {
+	Sort pagination.Sort
+	// After continues from the cursor of a previous page. When it is nil
+	// the page starts Offset courses into the list.
+	After  *pagination.Cursor
+	Offset int
+	Limit  int
+}
+
+// ListCourses returns a page of the courses matching the filter.
+func ListCourses(ctx context.Context, q Querier, f CourseFilter, p CoursePage) (*pagination.Page[models.Course], error) {
+	sort, ok := courseSortColumns[p.Sort.Field]
+	if !ok {
+		return nil, fmt.Errorf("unknown course sort field %q", p.Sort.Field)
+	}
+	dir, cmp := "ASC", ">"
+	if p.Sort.Desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	tags := uniqueStrings(f.Tags)
+	minTags := 1
+	if f.MatchAllTags {
+		minTags = len(tags)
+	}
+
+	// One extra row tells whether there is a next page.
+	args := []any{p.Limit + 1, p.Offset, f.Topic, tags, minTags}
+	keyset := ""
+	if p.After != nil {
+		key, err := decodeCourseSortKey(p.After)
+		if err != nil {
+			return nil, err
+		}
+		args[1] = 0
+		args = append(args, key, p.After.ID)
+		keyset = fmt.Sprintf("AND (%s, c.id) %s ($6::%s, $7::uuid)", sort.column, cmp, sort.typ)
+	}
+
+	rows, err := q.Query(ctx, fmt.Sprintf(`
+		SELECT c.id, c.topic, c.name, c.description, c.duration_min, c.created_by, c.created_at, c.updated_at
+		FROM courses c
+		WHERE ($3 = '' OR c.topic = $3)
+		  AND (cardinality($4::text[]) = 0 OR (
+			SELECT COUNT(*)
+			FROM course_tags ct
+			JOIN tags t ON t.id = ct.tag_id
+			WHERE ct.course_id = c.id AND t.name = ANY($4)
+		  ) >= $5)
+		  %[1]s
+		ORDER BY %[2]s %[3]s, c.id %[3]s
 		LIMIT $1 OFFSET $2
-	`, limit, offset)
+	`, keyset, sort.column, dir), args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	courses := make([]models.Course, 0, limit)
+	courses := make([]models.Course, 0, p.Limit+1)
 	for rows.Next() {
 		var c models.Course
 		if err := rows.Scan(
@@ -29,6 +117,7 @@ func ListCourses(ctx context.Context, pool *pgxpool.Pool, limit, offset int) ([]
 			&c.Name,
 			&c.Description,
 			&c.DurationMin,
+			&c.CreatedBy,
 			&c.CreatedAt,
 			&c.UpdatedAt,
 		); err != nil {
@@ -39,5 +128,242 @@ func ListCourses(ctx context.Context, pool *pgxpool.Pool, limit, offset int) ([]
 	if rows.Err() != nil {
 		return nil, rows.Err()
 	}
-	return courses, nil
+
+	return pagination.NewPage(courses, p.Limit, func(c models.Course) (string, error) {
+		return pagination.Encode(p.Sort, courseSortKey(c, p.Sort.Field), c.ID)
+	})
+}
+
+// decodeCourseSortKey returns the sort key of a course cursor as the type
+// of its sort field.
+func decodeCourseSortKey(c *pagination.Cursor) (any, error) {
+	var key any
+	switch c.Sort.Field {
+	case "name":
+		key = new(string)
+	case "duration_min":
+		key = new(int)
+	default:
+		key = new(time.Time)
+	}
+	if err := c.DecodeKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetCourse returns a course with its ordered lessons, each lesson's ordered
+// contents and the course tags. It returns ErrNotFound if the course does
+// not exist. Run it in a repeatable read transaction so that lessons and
+// contents are read from one snapshot.
+func GetCourse(ctx context.Context, q Querier, id uuid.UUID) (*models.Course, error) {
+	var c models.Course
+	err := q.QueryRow(ctx, `
+		SELECT id, topic, name, description, duration_min, created_by, created_at, updated_at
+		FROM courses
+		WHERE id = $1
+	`, id).Scan(
+		&c.ID,
+		&c.Topic,
+		&c.Name,
+		&c.Description,
+		&c.DurationMin,
+		&c.CreatedBy,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Lessons, err = ListLessons(ctx, q, id); err != nil {
+		return nil, err
+	}
+
+	contents, err := listCourseContents(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range c.Lessons {
+		c.Lessons[i].Contents = contents[c.Lessons[i].ID]
+	}
+
+	if c.Tags, err = ListCourseTags(ctx, q, id); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListLessons returns the lessons of a course in order, without their
+// contents.
+func ListLessons(ctx context.Context, q Querier, courseID uuid.UUID) ([]models.Lesson, error) {
+	rows, err := q.Query(ctx, `
+		SELECT id, course_id, title, lesson_order, estimated_time_min, points_reward, created_at, updated_at
+		FROM lessons
+		WHERE course_id = $1
+		ORDER BY lesson_order
+	`, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lessons := []models.Lesson{}
+	for rows.Next() {
+		var l models.Lesson
+		if err := rows.Scan(
+			&l.ID,
+			&l.CourseID,
+			&l.Title,
+			&l.LessonOrder,
+			&l.EstimatedTimeMin,
+			&l.PointsReward,
+			&l.CreatedAt,
+			&l.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		lessons = append(lessons, l)
+	}
+	return lessons, rows.Err()
+}
+
+// listCourseContents returns the contents of every lesson in a course keyed by lesson ID.
+func listCourseContents(ctx context.Context, q Querier, courseID uuid.UUID) (map[uuid.UUID][]models.Content, error) {
+	rows, err := q.Query(ctx, contentSelect+`
+		JOIN lessons l ON l.id = c.lesson_id
+		WHERE l.course_id = $1
+		ORDER BY l.lesson_order, c."order"
+	`, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contents := make(map[uuid.UUID][]models.Content)
+	for rows.Next() {
+		c, err := scanContent(rows)
+		if err != nil {
+			return nil, err
+		}
+		contents[c.LessonID] = append(contents[c.LessonID], c)
+	}
+	return contents, rows.Err()
+}
+
+// CoursePatch holds the course fields to change; nil fields are left untouched.
+type CoursePatch struct {
+	Topic       *string
+	Name        *string
+	Description *string
+	DurationMin *int
+}
+
+// CreateCourse inserts a new course and returns it.
+func CreateCourse(ctx context.Context, q Querier, c models.Course) (*models.Course, error) {
+	err := q.QueryRow(ctx, `
+		INSERT INTO courses (topic, name, description, duration_min, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, c.Topic, c.Name, c.Description, c.DurationMin, c.CreatedBy).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// UpdateCourse applies a patch to a course and returns the updated course.
+func UpdateCourse(ctx context.Context, q Querier, id uuid.UUID, p CoursePatch) (*models.Course, error) {
+	var c models.Course
+	err := q.QueryRow(ctx, `
+		UPDATE courses SET
+			topic = COALESCE($2, topic),
+			name = COALESCE($3, name),
+			description = COALESCE($4, description),
+			duration_min = COALESCE($5, duration_min),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING id, topic, name, description, duration_min, created_by, created_at, updated_at
+	`, id, p.Topic, p.Name, p.Description, p.DurationMin).Scan(
+		&c.ID,
+		&c.Topic,
+		&c.Name,
+		&c.Description,
+		&c.DurationMin,
+		&c.CreatedBy,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// DeleteCourse deletes a course; lessons and contents are removed by cascade.
+func DeleteCourse(ctx context.Context, q Querier, id uuid.UUID) error {
+	tag, err := q.Exec(ctx, `DELETE FROM courses WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetCourseOwner returns the ID of the user who created the course, or nil
+// for courses without an owner.
+func GetCourseOwner(ctx context.Context, q Querier, id uuid.UUID) (*uuid.UUID, error) {
+	var owner *uuid.UUID
+	err := q.QueryRow(ctx, `SELECT created_by FROM courses WHERE id = $1`, id).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return owner, err
+}
+
+// GetCourseSummary returns a course without its lessons and tags.
+func GetCourseSummary(ctx context.Context, q Querier, id uuid.UUID) (*models.Course, error) {
+	var c models.Course
+	err := q.QueryRow(ctx, `
+		SELECT id, topic, name, description, duration_min, created_by, created_at, updated_at
+		FROM courses
+		WHERE id = $1
+	`, id).Scan(
+		&c.ID,
+		&c.Topic,
+		&c.Name,
+		&c.Description,
+		&c.DurationMin,
+		&c.CreatedBy,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// uniqueStrings returns the distinct values of s in their original order.
+func uniqueStrings(s []string) []string {
+	seen := make(map[string]bool, len(s))
+	out := make([]string, 0, len(s))
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
 }
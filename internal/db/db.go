@@ -2,10 +2,32 @@ package db
 
 import (
 	"context"
+	"errors"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrNotFound is returned by lookups when the requested row does not exist.
+var ErrNotFound = errors.New("record not found")
+
+// Querier is implemented by *pgxpool.Pool and pgx.Tx, so functions that
+// accept it can run either on their own or as part of a transaction.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Conn is a Querier that can also begin a transaction. Functions that make
+// several writes accept it and run them in a transaction of their own, or
+// in a savepoint when given a pgx.Tx.
+type Conn interface {
+	Querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 // Connect creates a new pgx connection pool using the provided DSN.
 func Connect(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	cfg, err := pgxpool.ParseConfig(dsn)
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// leaderboardLockID is the key of the PostgreSQL advisory lock held while
+// the leaderboard is refreshed, so replicas do not refresh it at once.
+const leaderboardLockID int64 = 0x6c6561646572 // "leader"
+
+// LeaderboardEntry is a user's standing on a leaderboard.
+type LeaderboardEntry struct {
+	Rank      int       `json:"rank"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	FirstName *string   `json:"first_name,omitempty"`
+	AvatarURL *string   `json:"avatar_url,omitempty"`
+	AgeGroup  string    `json:"age_group"`
+	Points    int       `json:"points"`
+}
+
+// LeaderboardFilter selects the points a leaderboard ranks users by.
+type LeaderboardFilter struct {
+	// Since limits points to those earned on or after this day (UTC); the
+	// zero time ranks all-time points.
+	Since time.Time
+	// Topic limits points to courses of this topic.
+	Topic string
+	// AgeGroup limits the leaderboard to users of this age group.
+	AgeGroup string
+}
+
+// ListLeaderboard ranks users with points matching f and returns the first
+// limit entries followed by the entry of userID when it is ranked below
+// them. Users with equal points share a rank. It also returns the number of
+// ranked users.
+//
+// All-time points across all topics are taken from users.points and are
+// always current; other leaderboards are computed from
+// leaderboard_daily_points and are as fresh as its last refresh.
+func ListLeaderboard(ctx context.Context, q Querier, f LeaderboardFilter, userID uuid.UUID, limit int) ([]LeaderboardEntry, int, error) {
+	totals := `SELECT id AS user_id, points FROM users`
+	args := []any{f.AgeGroup, limit, userID}
+	if !f.Since.IsZero() || f.Topic != "" {
+		totals = `
+			SELECT user_id, SUM(points)::integer AS points
+			FROM leaderboard_daily_points
+			WHERE ($4::date IS NULL OR day >= $4::date) AND ($5::text = '' OR topic = $5::text)
+			GROUP BY user_id`
+		var since *time.Time
+		if !f.Since.IsZero() {
+			since = &f.Since
+		}
+		args = append(args, since, f.Topic)
+	}
+
+	rows, err := q.Query(ctx, fmt.Sprintf(`
+		WITH totals AS (%s),
+		ranked AS (
+			SELECT u.id, u.username, u.first_name, u.avatar_url, u.age_group, t.points,
+				RANK() OVER (ORDER BY t.points DESC) AS rank,
+				ROW_NUMBER() OVER (ORDER BY t.points DESC, u.username) AS position,
+				COUNT(*) OVER () AS total
+			FROM totals t
+			JOIN users u ON u.id = t.user_id
+			WHERE t.points > 0 AND ($1::text = '' OR u.age_group = $1::text)
+		)
+		SELECT rank, id, username, first_name, avatar_url, age_group, points, total
+		FROM ranked
+		WHERE position <= $2 OR id = $3
+		ORDER BY position
+	`, totals), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		entries []LeaderboardEntry
+		total   int
+	)
+	for rows.Next() {
+		var e LeaderboardEntry
+		if err := rows.Scan(&e.Rank, &e.UserID, &e.Username, &e.FirstName, &e.AvatarURL, &e.AgeGroup, &e.Points, &total); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
+
+// RefreshLeaderboard recomputes leaderboard_daily_points inside tx without
+// blocking readers. It reports false without refreshing when another
+// transaction is already refreshing it.
+func RefreshLeaderboard(ctx context.Context, tx pgx.Tx) (bool, error) {
+	var locked bool
+	if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", leaderboardLockID).Scan(&locked); err != nil || !locked {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY leaderboard_daily_points"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AcquireLease takes or renews the named lease for holder until ttl from
+// now. It reports false when another holder has an unexpired lease.
+func AcquireLease(ctx context.Context, q Querier, name, holder string, ttl time.Duration) (bool, error) {
+	var got string
+	err := q.QueryRow(ctx, `
+		INSERT INTO job_leases (name, holder, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP + $3::interval)
+		ON CONFLICT (name) DO UPDATE SET
+			holder = EXCLUDED.holder,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE job_leases.holder = EXCLUDED.holder OR job_leases.expires_at < CURRENT_TIMESTAMP
+		RETURNING holder
+	`, name, holder, ttl).Scan(&got)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ReleaseLease gives up the named lease if holder has it.
+func ReleaseLease(ctx context.Context, q Querier, name, holder string) error {
+	_, err := q.Exec(ctx, `DELETE FROM job_leases WHERE name = $1 AND holder = $2`, name, holder)
+	return err
+}
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+// Lesson orders within a course are always kept contiguous, starting at 1.
+// Every write that touches them locks the parent course row first so that
+// concurrent authoring requests on the same course are serialized, and the
+// deferrable (course_id, lesson_order) unique constraint guarantees that a
+// reorder can never commit with duplicate orders.
+
+// ErrInvalidLessonOrder is returned when a reorder request does not list
+// exactly the lessons of the course.
+var ErrInvalidLessonOrder = errors.New("lesson order must list every lesson of the course exactly once")
+
+// LessonPatch holds the lesson fields to change; nil fields are left untouched.
+type LessonPatch struct {
+	Title            *string
+	LessonOrder      *int
+	EstimatedTimeMin *int
+	PointsReward     *int
+}
+
+// lockCourse locks the course row for the rest of the transaction.
+func lockCourse(ctx context.Context, tx pgx.Tx, courseID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM courses WHERE id = $1 FOR UPDATE`, courseID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func countLessons(ctx context.Context, tx pgx.Tx, courseID uuid.UUID) (int, error) {
+	var n int
+	err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM lessons WHERE course_id = $1`, courseID).Scan(&n)
+	return n, err
+}
+
+// clampOrder keeps a requested position within 1..max.
+func clampOrder(order, max int) int {
+	if order < 1 {
+		return 1
+	}
+	if order > max {
+		return max
+	}
+	return order
+}
+
+// GetLesson returns a single lesson without its contents.
+func GetLesson(ctx context.Context, q Querier, id uuid.UUID) (*models.Lesson, error) {
+	var l models.Lesson
+	err := q.QueryRow(ctx, `
+		SELECT id, course_id, title, lesson_order, estimated_time_min, points_reward, created_at, updated_at
+		FROM lessons
+		WHERE id = $1
+	`, id).Scan(
+		&l.ID,
+		&l.CourseID,
+		&l.Title,
+		&l.LessonOrder,
+		&l.EstimatedTimeMin,
+		&l.PointsReward,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// CreateLesson inserts a lesson into a course. A LessonOrder of zero appends
+// the lesson; otherwise it is inserted at that position and later lessons
+// are shifted down.
+func CreateLesson(ctx context.Context, c Conn, l models.Lesson) (*models.Lesson, error) {
+	err := pgx.BeginFunc(ctx, c, func(tx pgx.Tx) error {
+		if err := lockCourse(ctx, tx, l.CourseID); err != nil {
+			return err
+		}
+		n, err := countLessons(ctx, tx, l.CourseID)
+		if err != nil {
+			return err
+		}
+		if l.LessonOrder == 0 {
+			l.LessonOrder = n + 1
+		}
+		l.LessonOrder = clampOrder(l.LessonOrder, n+1)
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE lessons SET lesson_order = lesson_order + 1
+			WHERE course_id = $1 AND lesson_order >= $2
+		`, l.CourseID, l.LessonOrder); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(ctx, `
+			INSERT INTO lessons (course_id, title, lesson_order, estimated_time_min, points_reward)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at, updated_at
+		`, l.CourseID, l.Title, l.LessonOrder, l.EstimatedTimeMin, l.PointsReward).Scan(&l.ID, &l.CreatedAt, &l.UpdatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// UpdateLesson applies a patch to a lesson. Changing LessonOrder moves the
+// lesson to that position and shifts the lessons in between.
+func UpdateLesson(ctx context.Context, c Conn, id uuid.UUID, p LessonPatch) (*models.Lesson, error) {
+	var l models.Lesson
+	err := pgx.BeginFunc(ctx, c, func(tx pgx.Tx) error {
+		var courseID uuid.UUID
+		var current int
+		err := tx.QueryRow(ctx, `SELECT course_id, lesson_order FROM lessons WHERE id = $1`, id).Scan(&courseID, &current)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := lockCourse(ctx, tx, courseID); err != nil {
+			return err
+		}
+
+		if p.LessonOrder != nil {
+			// Re-read under the course lock, another request may have moved it.
+			if err := tx.QueryRow(ctx, `SELECT lesson_order FROM lessons WHERE id = $1`, id).Scan(&current); err != nil {
+				return err
+			}
+			n, err := countLessons(ctx, tx, courseID)
+			if err != nil {
+				return err
+			}
+			target := clampOrder(*p.LessonOrder, n)
+			if target != current {
+				if _, err := tx.Exec(ctx, `
+					UPDATE lessons SET lesson_order = CASE
+						WHEN id = $2 THEN $4
+						WHEN $4 < $3 THEN lesson_order + 1
+						ELSE lesson_order - 1
+					END
+					WHERE course_id = $1 AND lesson_order BETWEEN LEAST($3, $4) AND GREATEST($3, $4)
+				`, courseID, id, current, target); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.QueryRow(ctx, `
+			UPDATE lessons SET
+				title = COALESCE($2, title),
+				estimated_time_min = COALESCE($3, estimated_time_min),
+				points_reward = COALESCE($4, points_reward),
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING id, course_id, title, lesson_order, estimated_time_min, points_reward, created_at, updated_at
+		`, id, p.Title, p.EstimatedTimeMin, p.PointsReward).Scan(
+			&l.ID,
+			&l.CourseID,
+			&l.Title,
+			&l.LessonOrder,
+			&l.EstimatedTimeMin,
+			&l.PointsReward,
+			&l.CreatedAt,
+			&l.UpdatedAt,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// DeleteLesson deletes a lesson and closes the gap it leaves in the order.
+func DeleteLesson(ctx context.Context, c Conn, id uuid.UUID) error {
+	return pgx.BeginFunc(ctx, c, func(tx pgx.Tx) error {
+		var courseID uuid.UUID
+		err := tx.QueryRow(ctx, `SELECT course_id FROM lessons WHERE id = $1`, id).Scan(&courseID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := lockCourse(ctx, tx, courseID); err != nil {
+			return err
+		}
+
+		var order int
+		err = tx.QueryRow(ctx, `DELETE FROM lessons WHERE id = $1 RETURNING lesson_order`, id).Scan(&order)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE lessons SET lesson_order = lesson_order - 1
+			WHERE course_id = $1 AND lesson_order > $2
+		`, courseID, order)
+		return err
+	})
+}
+
+// ReorderLessons sets the order of a course's lessons to the order of
+// lessonIDs, which must contain every lesson of the course exactly once.
+func ReorderLessons(ctx context.Context, c Conn, courseID uuid.UUID, lessonIDs []uuid.UUID) ([]models.Lesson, error) {
+	var lessons []models.Lesson
+	err := pgx.BeginFunc(ctx, c, func(tx pgx.Tx) error {
+		if err := lockCourse(ctx, tx, courseID); err != nil {
+			return err
+		}
+		n, err := countLessons(ctx, tx, courseID)
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[uuid.UUID]bool, len(lessonIDs))
+		for _, id := range lessonIDs {
+			if seen[id] {
+				return ErrInvalidLessonOrder
+			}
+			seen[id] = true
+		}
+
+		tag, err := tx.Exec(ctx, `
+			UPDATE lessons l
+			SET lesson_order = o.pos, updated_at = CURRENT_TIMESTAMP
+			FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, pos)
+			WHERE l.id = o.id AND l.course_id = $1
+		`, courseID, lessonIDs)
+		if err != nil {
+			return err
+		}
+		if len(lessonIDs) != n || int(tag.RowsAffected()) != n {
+			return ErrInvalidLessonOrder
+		}
+
+		lessons, err = ListLessons(ctx, tx, courseID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lessons, nil
+}
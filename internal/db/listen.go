@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Listener handles the notifications of a PostgreSQL channel.
+type Listener struct {
+	Channel string
+	// OnListen, if set, is called each time listening starts, including
+	// after reconnecting, when notifications may have been missed.
+	OnListen func(ctx context.Context)
+	// OnNotify is called with the payload of each notification.
+	OnNotify func(ctx context.Context, payload string)
+}
+
+// Listen holds a pool connection listening on l.Channel and hands the
+// notifications to l until ctx is done, reconnecting after failures.
+func Listen(ctx context.Context, pool *pgxpool.Pool, l Listener) {
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := listen(ctx, pool, l)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > time.Minute {
+			backoff = time.Second
+		}
+		slog.Error("Listener failed", "channel", l.Channel, "error", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, 30*time.Second)
+	}
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool, l Listener) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Stop listening before the connection goes back to the pool; a
+		// broken connection fails here and is discarded by Release.
+		conn.Exec(context.Background(), "UNLISTEN *")
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.Channel}.Sanitize()); err != nil {
+		return err
+	}
+	if l.OnListen != nil {
+		l.OnListen(ctx)
+	}
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		l.OnNotify(ctx, n.Payload)
+	}
+}
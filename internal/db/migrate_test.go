@@ -0,0 +1,63 @@
+package db
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		t.Fatalf("embedded migrations failed to load: %v", err)
+	}
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %s has version %d, want %d", m.Name, m.Version, i+1)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr bool
+	}{
+		{
+			name: "valid pair",
+			files: fstest.MapFS{
+				"m/0001_init.up.sql":   {Data: []byte("CREATE TABLE t (id INT);")},
+				"m/0001_init.down.sql": {Data: []byte("DROP TABLE t;")},
+			},
+		},
+		{
+			name: "missing down",
+			files: fstest.MapFS{
+				"m/0001_init.up.sql": {Data: []byte("CREATE TABLE t (id INT);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad file name",
+			files: fstest.MapFS{
+				"m/init.sql": {Data: []byte("SELECT 1;")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "conflicting names",
+			files: fstest.MapFS{
+				"m/0001_init.up.sql":    {Data: []byte("SELECT 1;")},
+				"m/0001_other.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.files, "m")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
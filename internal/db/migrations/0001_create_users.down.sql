@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_notifications;
+DROP TABLE IF EXISTS user_settings;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS resource_content;
+DROP TABLE IF EXISTS interactive_content;
+DROP TABLE IF EXISTS assessment_content;
+DROP TABLE IF EXISTS exercise_content;
+DROP TABLE IF EXISTS theory_content;
+DROP TABLE IF EXISTS content;
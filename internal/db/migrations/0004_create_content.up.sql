@@ -0,0 +1,51 @@
+-- Polymorphic lesson content: a base row plus one type-specific row
+
+CREATE TABLE content (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    "order" INTEGER NOT NULL,
+    content_type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE theory_content (
+    id UUID PRIMARY KEY REFERENCES content(id) ON DELETE CASCADE,
+    text_content TEXT NOT NULL,
+    examples JSONB,
+    "references" JSONB
+);
+
+CREATE TABLE exercise_content (
+    id UUID PRIMARY KEY REFERENCES content(id) ON DELETE CASCADE,
+    problems JSONB NOT NULL,
+    estimated_time_min INTEGER
+);
+
+CREATE TABLE assessment_content (
+    id UUID PRIMARY KEY REFERENCES content(id) ON DELETE CASCADE,
+    questions JSONB NOT NULL,
+    time_limit_min INTEGER,
+    passing_score FLOAT NOT NULL DEFAULT 70.0,
+    attempts_allowed INTEGER NOT NULL DEFAULT 3
+);
+
+CREATE TABLE interactive_content (
+    id UUID PRIMARY KEY REFERENCES content(id) ON DELETE CASCADE,
+    interactive_type VARCHAR(50) NOT NULL,
+    content_data JSONB NOT NULL,
+    config JSONB
+);
+
+CREATE TABLE resource_content (
+    id UUID PRIMARY KEY REFERENCES content(id) ON DELETE CASCADE,
+    resource_type VARCHAR(50) NOT NULL,
+    url VARCHAR(1024) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resource_metadata JSONB
+);
+
+CREATE INDEX idx_content_lesson_id ON content(lesson_id);
+CREATE INDEX idx_content_content_type ON content(content_type);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS user_answers;
+DROP TABLE IF EXISTS completed_courses;
+DROP TABLE IF EXISTS completed_lessons;
+DROP TABLE IF EXISTS content_states;
+DROP TABLE IF EXISTS user_content_progress;
+DROP TABLE IF EXISTS progress;
@@ -0,0 +1,90 @@
+-- Learner progress, resumable state, completions and answers
+
+CREATE TABLE progress (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    current_lesson_id UUID REFERENCES lessons(id) ON DELETE CASCADE,
+    total_points_earned INTEGER NOT NULL DEFAULT 0,
+    time_spent_min INTEGER NOT NULL DEFAULT 0,
+    progress_percentage FLOAT NOT NULL DEFAULT 0.0,
+    progress_data JSONB NOT NULL DEFAULT '{}'::jsonb,
+    last_accessed TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    is_completed BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_content_progress (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    is_completed BOOLEAN NOT NULL DEFAULT false,
+    score FLOAT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    time_spent INTEGER NOT NULL DEFAULT 0,
+    last_interaction TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, content_id)
+);
+
+CREATE TABLE content_states (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    progress_id UUID NOT NULL REFERENCES progress(id) ON DELETE CASCADE,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    state_type VARCHAR(50) NOT NULL,
+    numeric_value FLOAT,
+    json_value JSONB,
+    text_value TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, content_id, state_type)
+);
+
+CREATE TABLE completed_lessons (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    completed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    score FLOAT,
+    time_spent INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE completed_courses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    completed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    final_score FLOAT,
+    total_time_spent INTEGER NOT NULL,
+    completed_lessons_count INTEGER NOT NULL,
+    achievements_earned UUID[],
+    certificate_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, course_id)
+);
+
+CREATE TABLE user_answers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    question_id VARCHAR(100) NOT NULL,
+    answer_data JSONB NOT NULL,
+    is_correct BOOLEAN NOT NULL,
+    points_earned INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_progress_user_course ON progress(user_id, course_id);
+CREATE INDEX idx_user_content_progress_user_content ON user_content_progress(user_id, content_id);
+CREATE INDEX idx_content_states_user_content ON content_states(user_id, content_id);
+CREATE INDEX idx_completed_lessons_user_course ON completed_lessons(user_id, course_id);
+CREATE INDEX idx_completed_courses_user ON completed_courses(user_id);
+CREATE INDEX idx_user_answers_user_content ON user_answers(user_id, content_id);
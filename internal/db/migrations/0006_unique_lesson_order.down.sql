@@ -0,0 +1 @@
+ALTER TABLE lessons DROP CONSTRAINT IF EXISTS lessons_course_id_lesson_order_key;
@@ -0,0 +1,14 @@
+-- Lesson order must be unique within a course. The constraint is deferrable
+-- so that reordering statements are only checked once they complete.
+
+UPDATE lessons l
+SET lesson_order = o.pos
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY lesson_order, created_at, id) AS pos
+    FROM lessons
+) o
+WHERE l.id = o.id;
+
+ALTER TABLE lessons
+    ADD CONSTRAINT lessons_course_id_lesson_order_key
+    UNIQUE (course_id, lesson_order) DEFERRABLE INITIALLY IMMEDIATE;
@@ -0,0 +1,2 @@
+ALTER TABLE courses DROP COLUMN IF EXISTS created_by;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
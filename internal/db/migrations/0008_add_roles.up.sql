@@ -0,0 +1,11 @@
+-- User roles and course ownership
+
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'learner'
+    CONSTRAINT users_role_check CHECK (role IN ('learner', 'author', 'teacher', 'admin'));
+
+ALTER TABLE courses
+    ADD COLUMN created_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_users_role ON users(role);
+CREATE INDEX idx_courses_created_by ON courses(created_by);
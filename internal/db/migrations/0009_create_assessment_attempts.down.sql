@@ -0,0 +1,2 @@
+ALTER TABLE user_answers DROP COLUMN IF EXISTS attempt_id;
+DROP TABLE IF EXISTS assessment_attempts;
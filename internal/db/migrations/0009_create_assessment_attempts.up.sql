@@ -0,0 +1,22 @@
+-- Timed assessment attempts; answers given in an attempt reference it
+
+CREATE TABLE assessment_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    attempt_number INTEGER NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deadline_at TIMESTAMP WITH TIME ZONE,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    score FLOAT,
+    passed BOOLEAN,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, content_id, attempt_number)
+);
+
+ALTER TABLE user_answers
+    ADD COLUMN attempt_id UUID REFERENCES assessment_attempts(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_assessment_attempts_user_content ON assessment_attempts(user_id, content_id);
+CREATE INDEX idx_user_answers_attempt_id ON user_answers(attempt_id);
@@ -0,0 +1,4 @@
+ALTER TABLE completed_lessons DROP CONSTRAINT IF EXISTS completed_lessons_user_id_lesson_id_key;
+ALTER TABLE progress DROP CONSTRAINT IF EXISTS progress_user_id_course_id_key;
+
+CREATE INDEX IF NOT EXISTS idx_progress_user_course ON progress(user_id, course_id);
@@ -0,0 +1,19 @@
+-- One progress row per user and course, one completion per user and lesson
+
+DELETE FROM progress p
+USING progress dup
+WHERE p.user_id = dup.user_id AND p.course_id = dup.course_id
+    AND (p.created_at, p.id) < (dup.created_at, dup.id);
+
+DELETE FROM completed_lessons c
+USING completed_lessons dup
+WHERE c.user_id = dup.user_id AND c.lesson_id = dup.lesson_id
+    AND (c.completed_at, c.id) > (dup.completed_at, dup.id);
+
+DROP INDEX IF EXISTS idx_progress_user_course;
+
+ALTER TABLE progress
+    ADD CONSTRAINT progress_user_id_course_id_key UNIQUE (user_id, course_id);
+
+ALTER TABLE completed_lessons
+    ADD CONSTRAINT completed_lessons_user_id_lesson_id_key UNIQUE (user_id, lesson_id);
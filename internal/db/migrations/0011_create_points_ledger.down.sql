@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS points_ledger;
+DROP FUNCTION IF EXISTS points_ledger_append_only();
@@ -0,0 +1,31 @@
+-- Append-only ledger of awarded points; event_key makes each award idempotent
+
+CREATE TABLE points_ledger (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID REFERENCES courses(id) ON DELETE SET NULL,
+    points INTEGER NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    event_key VARCHAR(200) NOT NULL,
+    source_id UUID,
+    balance INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, event_key)
+);
+
+CREATE INDEX idx_points_ledger_user_created ON points_ledger(user_id, created_at DESC);
+
+-- Updates made by foreign key actions, such as the ON DELETE SET NULL of
+-- course_id, run inside a trigger and are allowed.
+CREATE FUNCTION points_ledger_append_only() RETURNS trigger AS $$
+BEGIN
+    IF pg_trigger_depth() > 1 THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'points_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER points_ledger_no_update
+    BEFORE UPDATE ON points_ledger
+    FOR EACH ROW EXECUTE FUNCTION points_ledger_append_only();
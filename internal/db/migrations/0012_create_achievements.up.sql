@@ -0,0 +1,38 @@
+-- Achievements with declarative award rules, and the achievements users earned
+
+CREATE TABLE achievements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    key VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    icon VARCHAR(255),
+    rule JSONB NOT NULL,
+    points INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_achievements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_id UUID NOT NULL REFERENCES achievements(id) ON DELETE CASCADE,
+    course_id UUID REFERENCES courses(id) ON DELETE SET NULL,
+    awarded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, achievement_id)
+);
+
+CREATE INDEX idx_user_achievements_user ON user_achievements(user_id, awarded_at DESC);
+CREATE INDEX idx_user_achievements_course ON user_achievements(user_id, course_id);
+
+INSERT INTO achievements (key, name, description, rule, points) VALUES
+    ('first_lesson', 'First Steps', 'Complete your first lesson.',
+        '{"type": "lessons_completed", "count": 1}', 10),
+    ('five_lessons_a_day', 'On a Roll', 'Complete 5 lessons in a single day.',
+        '{"type": "lessons_per_day", "count": 5}', 50),
+    ('perfect_assessment', 'Perfectionist', 'Score 100% on an assessment.',
+        '{"type": "assessment_score", "min_score": 100}', 50),
+    ('week_streak', 'Week Streak', 'Study 7 days in a row.',
+        '{"type": "streak_days", "count": 7}', 70),
+    ('first_course', 'Graduate', 'Complete your first course.',
+        '{"type": "courses_completed", "count": 1}', 100);
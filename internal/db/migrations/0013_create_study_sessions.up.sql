@@ -0,0 +1,19 @@
+-- Study sessions reported by clients, and the timezone study days are counted in
+
+CREATE TABLE study_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content_id UUID REFERENCES content(id) ON DELETE SET NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_heartbeat_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    duration_sec INTEGER NOT NULL DEFAULT 0 CHECK (duration_sec >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- At most one open session per user
+CREATE UNIQUE INDEX study_sessions_open_user_key ON study_sessions(user_id) WHERE ended_at IS NULL;
+CREATE INDEX idx_study_sessions_user_started ON study_sessions(user_id, started_at);
+
+ALTER TABLE user_settings ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_age_group;
+DROP INDEX IF EXISTS idx_users_points;
+DROP MATERIALIZED VIEW IF EXISTS leaderboard_daily_points;
@@ -0,0 +1,49 @@
+-- Daily points per user and course topic, precomputed for leaderboards.
+-- Points come from the points ledger; lessons completed and questions
+-- answered before the ledger existed are included from their own records.
+-- Points not tied to a course (achievements) have an empty topic.
+
+CREATE MATERIALIZED VIEW leaderboard_daily_points AS
+WITH earned AS (
+    SELECT user_id, course_id, points, created_at AS earned_at
+    FROM points_ledger
+    UNION ALL
+    SELECT cl.user_id, cl.course_id, l.points_reward, COALESCE(cl.completed_at, cl.created_at)
+    FROM completed_lessons cl
+    JOIN lessons l ON l.id = cl.lesson_id
+    WHERE l.points_reward > 0
+      AND NOT EXISTS (
+          SELECT 1 FROM points_ledger pl
+          WHERE pl.user_id = cl.user_id AND pl.event_key = 'lesson:' || cl.lesson_id::text)
+    UNION ALL
+    SELECT a.user_id, l.course_id, a.points_earned, a.created_at
+    FROM (
+        SELECT DISTINCT ON (user_id, content_id, question_id)
+            user_id, content_id, question_id, points_earned, created_at
+        FROM user_answers
+        WHERE is_correct
+        ORDER BY user_id, content_id, question_id, created_at
+    ) a
+    JOIN content c ON c.id = a.content_id
+    JOIN lessons l ON l.id = c.lesson_id
+    WHERE a.points_earned > 0
+      AND NOT EXISTS (
+          SELECT 1 FROM points_ledger pl
+          WHERE pl.user_id = a.user_id
+            AND pl.event_key = 'answer:' || a.content_id::text || ':' || a.question_id)
+)
+SELECT e.user_id,
+       COALESCE(co.topic, '') AS topic,
+       (e.earned_at AT TIME ZONE 'UTC')::date AS day,
+       SUM(e.points)::integer AS points
+FROM earned e
+LEFT JOIN courses co ON co.id = e.course_id
+WHERE e.earned_at IS NOT NULL
+GROUP BY e.user_id, COALESCE(co.topic, ''), (e.earned_at AT TIME ZONE 'UTC')::date;
+
+-- The unique index allows REFRESH MATERIALIZED VIEW CONCURRENTLY.
+CREATE UNIQUE INDEX idx_leaderboard_daily_points_key ON leaderboard_daily_points(user_id, topic, day);
+CREATE INDEX idx_leaderboard_daily_points_day ON leaderboard_daily_points(day, topic);
+
+CREATE INDEX idx_users_points ON users(points DESC);
+CREATE INDEX idx_users_age_group ON users(age_group);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_notifications_user_unread;
+DROP INDEX IF EXISTS idx_user_notifications_user_created;
+CREATE INDEX IF NOT EXISTS idx_user_notifications_user_id ON user_notifications(user_id);
+ALTER TABLE user_notifications ALTER COLUMN created_at DROP NOT NULL;
@@ -0,0 +1,8 @@
+-- Indexes for the notification inbox: newest first per user, and unread counts
+
+UPDATE user_notifications SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE user_notifications ALTER COLUMN created_at SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_user_notifications_user_id;
+CREATE INDEX idx_user_notifications_user_created ON user_notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_user_notifications_user_unread ON user_notifications(user_id) WHERE NOT is_read;
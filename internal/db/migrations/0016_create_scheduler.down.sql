@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS study_reminders;
+DROP TABLE IF EXISTS job_leases;
@@ -0,0 +1,18 @@
+-- Leases that let one replica at a time run a scheduled job, and the daily
+-- study reminders already sent
+
+CREATE TABLE job_leases (
+    name VARCHAR(100) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- local_date is the day in the user's timezone the reminder was sent for
+CREATE TABLE study_reminders (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    local_date DATE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, local_date)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE user_settings DROP CONSTRAINT IF EXISTS user_settings_user_id_key;
+CREATE INDEX IF NOT EXISTS idx_user_settings_user_id ON user_settings(user_id);
@@ -0,0 +1,9 @@
+-- One settings row per user; settings rows are created on first use
+
+DELETE FROM user_settings s
+USING user_settings o
+WHERE s.user_id = o.user_id
+  AND (COALESCE(s.created_at, 'infinity'), s.id) > (COALESCE(o.created_at, 'infinity'), o.id);
+
+DROP INDEX IF EXISTS idx_user_settings_user_id;
+ALTER TABLE user_settings ADD CONSTRAINT user_settings_user_id_key UNIQUE (user_id);
@@ -0,0 +1,3 @@
+DELETE FROM settings WHERE key IN ('leaderboard.size', 'points.lesson_multiplier');
+DROP TRIGGER IF EXISTS settings_changed ON settings;
+DROP FUNCTION IF EXISTS settings_notify_change();
@@ -0,0 +1,18 @@
+-- Announce changes to application settings so every replica reloads them,
+-- and seed the runtime settings the application reads
+
+CREATE FUNCTION settings_notify_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('settings_changed', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER settings_changed
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON settings
+    FOR EACH STATEMENT EXECUTE FUNCTION settings_notify_change();
+
+INSERT INTO settings (key, value, description) VALUES
+    ('leaderboard.size', '10', 'Number of top entries a leaderboard shows by default'),
+    ('points.lesson_multiplier', '1', 'Factor applied to the points reward of every completed lesson')
+ON CONFLICT (key) DO NOTHING;
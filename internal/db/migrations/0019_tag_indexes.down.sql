@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_course_tags_tag_id;
+DROP INDEX IF EXISTS idx_tags_category;
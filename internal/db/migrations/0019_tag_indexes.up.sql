@@ -0,0 +1,4 @@
+-- Tag lookups: listing by category and finding the courses of a tag
+
+CREATE INDEX idx_tags_category ON tags(category, name);
+CREATE INDEX idx_course_tags_tag_id ON course_tags(tag_id);
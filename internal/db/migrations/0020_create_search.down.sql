@@ -0,0 +1,17 @@
+DROP TRIGGER IF EXISTS theory_content_search_vector ON theory_content;
+DROP TRIGGER IF EXISTS lessons_search_vector ON lessons;
+DROP TRIGGER IF EXISTS courses_search_vector ON courses;
+
+DROP FUNCTION IF EXISTS theory_content_search_vector();
+DROP FUNCTION IF EXISTS lessons_search_vector();
+DROP FUNCTION IF EXISTS courses_search_vector();
+
+ALTER TABLE theory_content DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE lessons DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE courses DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS search_query(TEXT);
+DROP FUNCTION IF EXISTS search_document(TEXT, "char");
+
+-- The ukrainian text search configuration is left in place: it may have
+-- existed before the migration.
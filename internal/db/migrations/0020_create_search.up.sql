@@ -0,0 +1,71 @@
+-- Full-text search over courses, lessons and theory content.
+--
+-- Text is indexed twice, with the english configuration and with the
+-- ukrainian one, so queries in either language match. PostgreSQL ships no
+-- Ukrainian stemmer; when the ukrainian configuration does not exist it is
+-- created as a copy of simple, and servers with a Ukrainian dictionary
+-- installed (for example hunspell uk_UA) can map it with ALTER TEXT SEARCH
+-- CONFIGURATION ukrainian ALTER MAPPING ... and reindex with
+-- UPDATE courses SET name = name (and likewise for the other tables).
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'ukrainian') THEN
+        CREATE TEXT SEARCH CONFIGURATION ukrainian (COPY = simple);
+    END IF;
+END
+$$;
+
+CREATE FUNCTION search_document(body TEXT, weight "char") RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(body, '')), weight)
+        || setweight(to_tsvector('ukrainian', COALESCE(body, '')), weight)
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION search_query(query TEXT) RETURNS tsquery AS $$
+    SELECT websearch_to_tsquery('english', query) || websearch_to_tsquery('ukrainian', query)
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE courses ADD COLUMN search_vector tsvector;
+ALTER TABLE lessons ADD COLUMN search_vector tsvector;
+ALTER TABLE theory_content ADD COLUMN search_vector tsvector;
+
+CREATE FUNCTION courses_search_vector() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := search_document(NEW.name, 'A') || search_document(NEW.description, 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION lessons_search_vector() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := search_document(NEW.title, 'A');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION theory_content_search_vector() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := search_document(NEW.text_content, 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER courses_search_vector
+    BEFORE INSERT OR UPDATE OF name, description ON courses
+    FOR EACH ROW EXECUTE FUNCTION courses_search_vector();
+
+CREATE TRIGGER lessons_search_vector
+    BEFORE INSERT OR UPDATE OF title ON lessons
+    FOR EACH ROW EXECUTE FUNCTION lessons_search_vector();
+
+CREATE TRIGGER theory_content_search_vector
+    BEFORE INSERT OR UPDATE OF text_content ON theory_content
+    FOR EACH ROW EXECUTE FUNCTION theory_content_search_vector();
+
+UPDATE courses SET search_vector = search_document(name, 'A') || search_document(description, 'B');
+UPDATE lessons SET search_vector = search_document(title, 'A');
+UPDATE theory_content SET search_vector = search_document(text_content, 'B');
+
+CREATE INDEX idx_courses_search ON courses USING GIN (search_vector);
+CREATE INDEX idx_lessons_search ON lessons USING GIN (search_vector);
+CREATE INDEX idx_theory_content_search ON theory_content USING GIN (search_vector);
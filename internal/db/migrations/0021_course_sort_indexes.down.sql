@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_courses_created_at_id;
+DROP INDEX IF EXISTS idx_courses_duration_min_id;
+DROP INDEX IF EXISTS idx_courses_name_id;
+
+ALTER TABLE courses ALTER COLUMN created_at DROP NOT NULL;
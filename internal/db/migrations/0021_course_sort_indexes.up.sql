@@ -0,0 +1,9 @@
+-- Keyset pagination of courses by name, duration and creation time, with
+-- the ID breaking ties. Row comparisons skip NULLs, so created_at must be set.
+
+UPDATE courses SET created_at = COALESCE(updated_at, CURRENT_TIMESTAMP) WHERE created_at IS NULL;
+ALTER TABLE courses ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX idx_courses_name_id ON courses(name, id);
+CREATE INDEX idx_courses_duration_min_id ON courses(duration_min, id);
+CREATE INDEX idx_courses_created_at_id ON courses(created_at, id);
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS points_ledger_append_only ON points_ledger;
+
+CREATE OR REPLACE FUNCTION points_ledger_append_only() RETURNS trigger AS $$
+BEGIN
+    IF pg_trigger_depth() > 1 THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'points_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER points_ledger_no_update
+    BEFORE UPDATE ON points_ledger
+    FOR EACH ROW EXECUTE FUNCTION points_ledger_append_only();
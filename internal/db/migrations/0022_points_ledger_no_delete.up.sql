@@ -0,0 +1,21 @@
+-- The points ledger is append-only for deletes too. Deletes made by the ON
+-- DELETE CASCADE from users run inside a trigger and are still allowed, like
+-- the updates of the ON DELETE SET NULL from courses.
+
+CREATE OR REPLACE FUNCTION points_ledger_append_only() RETURNS trigger AS $$
+BEGIN
+    IF pg_trigger_depth() > 1 THEN
+        IF TG_OP = 'DELETE' THEN
+            RETURN OLD;
+        END IF;
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'points_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER points_ledger_no_update ON points_ledger;
+
+CREATE TRIGGER points_ledger_append_only
+    BEFORE UPDATE OR DELETE ON points_ledger
+    FOR EACH ROW EXECUTE FUNCTION points_ledger_append_only();
@@ -0,0 +1,16 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_ts_dict WHERE dictname = 'ukrainian_hunspell') THEN
+        ALTER TEXT SEARCH CONFIGURATION ukrainian
+            ALTER MAPPING FOR word, hword, hword_part WITH simple;
+        DROP TEXT SEARCH DICTIONARY ukrainian_hunspell;
+
+        UPDATE courses SET search_vector = search_document(name, 'A') || search_document(description, 'B');
+        UPDATE lessons SET search_vector = search_document(title, 'A');
+        UPDATE theory_content SET search_vector = search_document(text_content, 'B');
+    END IF;
+END
+$$;
+
+DROP FUNCTION IF EXISTS search_enable_ukrainian_stemming();
+DROP FUNCTION IF EXISTS search_ukrainian_stemmed();
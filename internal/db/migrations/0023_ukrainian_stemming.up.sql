@@ -0,0 +1,49 @@
+-- Ukrainian stemming for full-text search with the hunspell uk_UA
+-- dictionary. PostgreSQL ships no Ukrainian stemmer, so the dictionary must
+-- be installed on the database server as uk_ua.dict and uk_ua.affix in
+-- $SHAREDIR/tsearch_data. Without it the ukrainian configuration stays a
+-- copy of simple: Ukrainian words only match in the form they are written.
+-- The server warns about this at startup; once the files are installed,
+-- SELECT search_enable_ukrainian_stemming(); maps the dictionary and
+-- reindexes the searchable text.
+
+CREATE FUNCTION search_ukrainian_stemmed() RETURNS boolean AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM pg_ts_config_map m
+        JOIN pg_ts_config c ON c.oid = m.mapcfg
+        JOIN pg_ts_dict d ON d.oid = m.mapdict
+        WHERE c.cfgname = 'ukrainian' AND d.dictname <> 'simple'
+    )
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION search_enable_ukrainian_stemming() RETURNS boolean AS $$
+BEGIN
+    IF search_ukrainian_stemmed() THEN
+        RETURN true;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_dict WHERE dictname = 'ukrainian_hunspell') THEN
+        BEGIN
+            CREATE TEXT SEARCH DICTIONARY ukrainian_hunspell (
+                TEMPLATE = ispell,
+                DictFile = uk_ua,
+                AffFile = uk_ua
+            );
+        EXCEPTION WHEN OTHERS THEN
+            RAISE NOTICE 'Ukrainian search text is not stemmed: %', SQLERRM;
+            RETURN false;
+        END;
+    END IF;
+
+    ALTER TEXT SEARCH CONFIGURATION ukrainian
+        ALTER MAPPING FOR word, hword, hword_part WITH ukrainian_hunspell, simple;
+
+    UPDATE courses SET search_vector = search_document(name, 'A') || search_document(description, 'B');
+    UPDATE lessons SET search_vector = search_document(title, 'A');
+    UPDATE theory_content SET search_vector = search_document(text_content, 'B');
+    RETURN true;
+END
+$$ LANGUAGE plpgsql;
+
+SELECT search_enable_ukrainian_stemming();
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+const notificationColumns = `id, user_id, type, title, message, is_read, related_id, created_at, updated_at`
+
+func scanNotification(row pgx.Row) (*models.UserNotification, error) {
+	var n models.UserNotification
+	err := row.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.Type,
+		&n.Title,
+		&n.Message,
+		&n.IsRead,
+		&n.RelatedID,
+		&n.CreatedAt,
+		&n.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// NotificationPreferences are the user's notification settings.
+type NotificationPreferences struct {
+	DailyReminder     bool
+	AchievementAlerts bool
+}
+
+// NotificationFilter selects notifications in a user's inbox.
+type NotificationFilter struct {
+	IsRead *bool
+	Type   string
+	// Notifications older than (BeforeCreatedAt, BeforeID) are returned
+	// when BeforeCreatedAt is set.
+	BeforeCreatedAt *time.Time
+	BeforeID        uuid.UUID
+}
+
+// InsertNotification stores a notification and fills in its ID and timestamps.
+func InsertNotification(ctx context.Context, q Querier, n *models.UserNotification) error {
+	return q.QueryRow(ctx, `
+		INSERT INTO user_notifications (user_id, type, title, message, related_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, is_read, created_at, updated_at
+	`, n.UserID, n.Type, n.Title, n.Message, n.RelatedID).Scan(&n.ID, &n.IsRead, &n.CreatedAt, &n.UpdatedAt)
+}
+
+// GetNotificationPreferences returns the user's notification settings.
+// Users without settings get the defaults, which are on.
+func GetNotificationPreferences(ctx context.Context, q Querier, userID uuid.UUID) (NotificationPreferences, error) {
+	var p NotificationPreferences
+	err := q.QueryRow(ctx, `
+		SELECT COALESCE(s.notification_daily_reminder, true), COALESCE(s.notification_achievement_alerts, true)
+		FROM users u
+		LEFT JOIN user_settings s ON s.user_id = u.id
+		WHERE u.id = $1
+	`, userID).Scan(&p.DailyReminder, &p.AchievementAlerts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return p, ErrNotFound
+	}
+	return p, err
+}
+
+// ListNotifications returns up to limit of the user's notifications
+// matching f, newest first.
+func ListNotifications(ctx context.Context, q Querier, userID uuid.UUID, f NotificationFilter, limit int) ([]models.UserNotification, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM user_notifications
+		WHERE user_id = $1
+		  AND ($2::boolean IS NULL OR is_read = $2::boolean)
+		  AND ($3::text = '' OR type = $3::text)
+		  AND ($4::timestamptz IS NULL OR (created_at, id) < ($4::timestamptz, $5::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $6
+	`, userID, f.IsRead, f.Type, f.BeforeCreatedAt, f.BeforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []models.UserNotification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *n)
+	}
+	return notifications, rows.Err()
+}
+
+// CountUnreadNotifications returns the number of unread notifications.
+func CountUnreadNotifications(ctx context.Context, q Querier, userID uuid.UUID) (int, error) {
+	var n int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*) FROM user_notifications WHERE user_id = $1 AND NOT is_read
+	`, userID).Scan(&n)
+	return n, err
+}
+
+// MarkNotificationRead marks one of the user's notifications read.
+func MarkNotificationRead(ctx context.Context, q Querier, userID, id uuid.UUID) (*models.UserNotification, error) {
+	return scanNotification(q.QueryRow(ctx, `
+		UPDATE user_notifications
+		SET is_read = true, updated_at = CASE WHEN is_read THEN updated_at ELSE CURRENT_TIMESTAMP END
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+notificationColumns,
+		id, userID))
+}
+
+// MarkAllNotificationsRead marks the user's unread notifications read,
+// optionally only those of one type, and returns how many were marked.
+func MarkAllNotificationsRead(ctx context.Context, q Querier, userID uuid.UUID, notificationType string) (int64, error) {
+	tag, err := q.Exec(ctx, `
+		UPDATE user_notifications
+		SET is_read = true, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND NOT is_read AND ($2::text = '' OR type = $2::text)
+	`, userID, notificationType)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteNotification deletes one of the user's notifications.
+func DeleteNotification(ctx context.Context, q Querier, userID, id uuid.UUID) error {
+	tag, err := q.Exec(ctx, `DELETE FROM user_notifications WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
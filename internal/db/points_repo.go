@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+const pointsEntryColumns = `id, user_id, course_id, points, reason, event_key, source_id, balance, created_at`
+
+func scanPointsEntry(row pgx.Row) (*models.PointsEntry, error) {
+	var e models.PointsEntry
+	err := row.Scan(
+		&e.ID,
+		&e.UserID,
+		&e.CourseID,
+		&e.Points,
+		&e.Reason,
+		&e.EventKey,
+		&e.SourceID,
+		&e.Balance,
+		&e.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// LockUserPoints returns the user's point balance and locks the user row
+// for the rest of the transaction.
+func LockUserPoints(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (int, error) {
+	var points int
+	err := tx.QueryRow(ctx, `SELECT points FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&points)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	return points, err
+}
+
+// InsertPointsEntry appends an entry to the points ledger. It returns
+// ErrNotFound when an entry with the same event key was already recorded
+// for the user.
+func InsertPointsEntry(ctx context.Context, q Querier, e models.PointsEntry) (*models.PointsEntry, error) {
+	return scanPointsEntry(q.QueryRow(ctx, `
+		INSERT INTO points_ledger (user_id, course_id, points, reason, event_key, source_id, balance)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, event_key) DO NOTHING
+		RETURNING `+pointsEntryColumns,
+		e.UserID, e.CourseID, e.Points, e.Reason, e.EventKey, e.SourceID, e.Balance))
+}
+
+// SetUserPoints sets the user's point balance and experience level.
+func SetUserPoints(ctx context.Context, q Querier, userID uuid.UUID, points, level int) error {
+	_, err := q.Exec(ctx, `
+		UPDATE users SET points = $2, experience_level = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+	`, userID, points, level)
+	return err
+}
+
+// AddCoursePoints adds points to the user's progress in a course, creating
+// the progress row if needed.
+func AddCoursePoints(ctx context.Context, q Querier, userID, courseID uuid.UUID, points int) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO progress (user_id, course_id, total_points_earned)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, course_id) DO UPDATE SET
+			total_points_earned = progress.total_points_earned + EXCLUDED.total_points_earned,
+			updated_at = CURRENT_TIMESTAMP
+	`, userID, courseID, points)
+	return err
+}
+
+// ListPointsEntries returns a page of the user's ledger entries, newest first.
+func ListPointsEntries(ctx context.Context, q Querier, userID uuid.UUID, limit, offset int) ([]models.PointsEntry, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+pointsEntryColumns+`
+		FROM points_ledger
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.PointsEntry, 0, limit)
+	for rows.Next() {
+		e, err := scanPointsEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+// CountPointsEntries returns how many ledger entries the user has.
+func CountPointsEntries(ctx context.Context, q Querier, userID uuid.UUID) (int, error) {
+	var n int
+	err := q.QueryRow(ctx, `SELECT COUNT(*) FROM points_ledger WHERE user_id = $1`, userID).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,356 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+const progressColumns = `id, user_id, course_id, current_lesson_id, total_points_earned, time_spent_min,
+	progress_percentage, progress_data, last_accessed, is_completed, created_at, updated_at`
+
+func scanProgress(row pgx.Row) (*models.Progress, error) {
+	var p models.Progress
+	err := row.Scan(
+		&p.ID,
+		&p.UserID,
+		&p.CourseID,
+		&p.CurrentLessonID,
+		&p.TotalPointsEarned,
+		&p.TimeSpentMin,
+		&p.ProgressPercentage,
+		&p.ProgressData,
+		&p.LastAccessed,
+		&p.IsCompleted,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+const contentProgressColumns = `id, user_id, content_id, is_completed, score, attempts, time_spent,
+	last_interaction, created_at, updated_at`
+
+func scanContentProgress(row pgx.Row) (*models.UserContentProgress, error) {
+	var p models.UserContentProgress
+	err := row.Scan(
+		&p.ID,
+		&p.UserID,
+		&p.ContentID,
+		&p.IsCompleted,
+		&p.Score,
+		&p.Attempts,
+		&p.TimeSpentMin,
+		&p.LastInteraction,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+const completedLessonColumns = `id, user_id, lesson_id, course_id, score, time_spent, created_at, updated_at`
+
+func scanCompletedLesson(row pgx.Row) (*models.CompletedLesson, error) {
+	var l models.CompletedLesson
+	err := row.Scan(
+		&l.ID,
+		&l.UserID,
+		&l.LessonID,
+		&l.CourseID,
+		&l.Score,
+		&l.TimeSpentMin,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+const completedCourseColumns = `id, user_id, course_id, final_score, total_time_spent,
+	completed_lessons_count, COALESCE(achievements_earned::text[], '{}'), created_at, updated_at`
+
+func scanCompletedCourse(row pgx.Row) (*models.CompletedCourse, error) {
+	var c models.CompletedCourse
+	err := row.Scan(
+		&c.ID,
+		&c.UserID,
+		&c.CourseID,
+		&c.FinalScore,
+		&c.TotalTimeSpentMin,
+		&c.CompletedLessonsCount,
+		&c.AchievementsEarned,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetContentCourseID returns the ID of the course a content item belongs to.
+func GetContentCourseID(ctx context.Context, q Querier, contentID uuid.UUID) (uuid.UUID, error) {
+	var courseID uuid.UUID
+	err := q.QueryRow(ctx, `
+		SELECT l.course_id FROM content c JOIN lessons l ON l.id = c.lesson_id WHERE c.id = $1
+	`, contentID).Scan(&courseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrNotFound
+	}
+	return courseID, err
+}
+
+// LockCourseProgress returns the user's progress row for a course, creating
+// it if needed, and locks it for the rest of the transaction.
+//
+// Transactions writing a learner's progress take their row locks in one
+// order, so that concurrent requests of the same user cannot deadlock: the
+// course progress row first, then content progress rows
+// (LockContentProgress) and assessment attempts, and the user row
+// (LockUserPoints) last.
+func LockCourseProgress(ctx context.Context, tx pgx.Tx, userID, courseID uuid.UUID) (*models.Progress, error) {
+	return scanProgress(tx.QueryRow(ctx, `
+		INSERT INTO progress (user_id, course_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, course_id) DO UPDATE SET last_accessed = CURRENT_TIMESTAMP
+		RETURNING `+progressColumns,
+		userID, courseID))
+}
+
+// RefreshCourseProgress recomputes the completion percentage, current
+// lesson and completion flag of a progress row from the user's content
+// progress, and adds timeSpentMin to the time spent in the course.
+//
+// The current lesson is the first lesson, in course order, that still has
+// incomplete contents, or the last lesson once everything is done.
+func RefreshCourseProgress(ctx context.Context, q Querier, progressID uuid.UUID, timeSpentMin int) (*models.Progress, error) {
+	return scanProgress(q.QueryRow(ctx, `
+		WITH contents AS (
+			SELECT l.id AS lesson_id, l.lesson_order, COALESCE(p.is_completed, false) AS done
+			FROM progress pr
+			JOIN lessons l ON l.course_id = pr.course_id
+			JOIN content c ON c.lesson_id = l.id
+			LEFT JOIN user_content_progress p ON p.content_id = c.id AND p.user_id = pr.user_id
+			WHERE pr.id = $1
+		)
+		UPDATE progress pr SET
+			progress_percentage = COALESCE(
+				(SELECT 100.0 * COUNT(*) FILTER (WHERE done) / NULLIF(COUNT(*), 0) FROM contents), 0),
+			current_lesson_id = COALESCE(
+				(SELECT lesson_id FROM contents WHERE NOT done ORDER BY lesson_order LIMIT 1),
+				(SELECT id FROM lessons WHERE course_id = pr.course_id ORDER BY lesson_order DESC LIMIT 1)),
+			is_completed = EXISTS (
+				SELECT 1 FROM completed_courses cc WHERE cc.user_id = pr.user_id AND cc.course_id = pr.course_id),
+			time_spent_min = pr.time_spent_min + $2,
+			last_accessed = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE pr.id = $1
+		RETURNING `+progressColumns,
+		progressID, timeSpentMin))
+}
+
+// GetCourseProgress returns the user's progress in a course, or ErrNotFound
+// if the user has not started it.
+func GetCourseProgress(ctx context.Context, q Querier, userID, courseID uuid.UUID) (*models.Progress, error) {
+	return scanProgress(q.QueryRow(ctx, `
+		SELECT `+progressColumns+` FROM progress WHERE user_id = $1 AND course_id = $2
+	`, userID, courseID))
+}
+
+// ListUserProgress returns the user's progress in every course they
+// started, most recently accessed first.
+func ListUserProgress(ctx context.Context, q Querier, userID uuid.UUID) ([]models.Progress, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+progressColumns+` FROM progress WHERE user_id = $1 ORDER BY last_accessed DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := []models.Progress{}
+	for rows.Next() {
+		p, err := scanProgress(rows)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, *p)
+	}
+	return progress, rows.Err()
+}
+
+// LockContentProgress returns the user's progress row for a content item,
+// creating it if needed, and locks it for the rest of the transaction.
+func LockContentProgress(ctx context.Context, tx pgx.Tx, userID, contentID uuid.UUID) (*models.UserContentProgress, error) {
+	return scanContentProgress(tx.QueryRow(ctx, `
+		INSERT INTO user_content_progress (user_id, content_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, content_id) DO UPDATE SET last_interaction = CURRENT_TIMESTAMP
+		RETURNING `+contentProgressColumns,
+		userID, contentID))
+}
+
+// CompleteContentProgress marks a content item completed for the user and
+// adds timeSpentMin to the time spent on it.
+func CompleteContentProgress(ctx context.Context, q Querier, userID, contentID uuid.UUID, timeSpentMin int) (*models.UserContentProgress, error) {
+	return scanContentProgress(q.QueryRow(ctx, `
+		INSERT INTO user_content_progress (user_id, content_id, is_completed, time_spent)
+		VALUES ($1, $2, true, $3)
+		ON CONFLICT (user_id, content_id) DO UPDATE SET
+			is_completed = true,
+			time_spent = user_content_progress.time_spent + EXCLUDED.time_spent,
+			last_interaction = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING `+contentProgressColumns,
+		userID, contentID, timeSpentMin))
+}
+
+// ListCourseContentProgress returns the user's progress on the contents of a course.
+func ListCourseContentProgress(ctx context.Context, q Querier, userID, courseID uuid.UUID) ([]models.UserContentProgress, error) {
+	rows, err := q.Query(ctx, `
+		SELECT p.id, p.user_id, p.content_id, p.is_completed, p.score, p.attempts, p.time_spent,
+			p.last_interaction, p.created_at, p.updated_at
+		FROM user_content_progress p
+		JOIN content c ON c.id = p.content_id
+		JOIN lessons l ON l.id = c.lesson_id
+		WHERE p.user_id = $1 AND l.course_id = $2
+		ORDER BY l.lesson_order, c."order"
+	`, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := []models.UserContentProgress{}
+	for rows.Next() {
+		p, err := scanContentProgress(rows)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, *p)
+	}
+	return progress, rows.Err()
+}
+
+// IncrementContentAttempts increments the attempt counter of a user's content progress.
+func IncrementContentAttempts(ctx context.Context, q Querier, userID, contentID uuid.UUID) error {
+	_, err := q.Exec(ctx, `
+		UPDATE user_content_progress SET
+			attempts = attempts + 1,
+			last_interaction = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND content_id = $2
+	`, userID, contentID)
+	return err
+}
+
+// RecordContentScore keeps the best score the user reached on a content item.
+func RecordContentScore(ctx context.Context, q Querier, userID, contentID uuid.UUID, score float64) error {
+	_, err := q.Exec(ctx, `
+		UPDATE user_content_progress SET
+			score = GREATEST(COALESCE(score, 0), $3),
+			last_interaction = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND content_id = $2
+	`, userID, contentID, score)
+	return err
+}
+
+// CompleteLessonIfDone records the lesson as completed once the user has
+// completed all of its contents. It returns ErrNotFound when the lesson is
+// not done yet or was already recorded as completed.
+//
+// The lesson score is the average score of its scored contents and the
+// time spent is the sum over all of its contents.
+func CompleteLessonIfDone(ctx context.Context, q Querier, userID, lessonID uuid.UUID) (*models.CompletedLesson, error) {
+	return scanCompletedLesson(q.QueryRow(ctx, `
+		INSERT INTO completed_lessons (user_id, lesson_id, course_id, score, time_spent)
+		SELECT $1, l.id, l.course_id, AVG(p.score), COALESCE(SUM(p.time_spent), 0)
+		FROM lessons l
+		JOIN content c ON c.lesson_id = l.id
+		LEFT JOIN user_content_progress p ON p.content_id = c.id AND p.user_id = $1
+		WHERE l.id = $2
+		GROUP BY l.id, l.course_id
+		HAVING COUNT(*) = COUNT(*) FILTER (WHERE p.is_completed)
+		ON CONFLICT (user_id, lesson_id) DO NOTHING
+		RETURNING `+completedLessonColumns,
+		userID, lessonID))
+}
+
+// CompleteCourseIfDone records the course as completed once the user has
+// completed every lesson that has contents. It returns ErrNotFound when the
+// course is not done yet or was already recorded as completed.
+func CompleteCourseIfDone(ctx context.Context, q Querier, userID, courseID uuid.UUID) (*models.CompletedCourse, error) {
+	return scanCompletedCourse(q.QueryRow(ctx, `
+		INSERT INTO completed_courses (user_id, course_id, final_score, total_time_spent, completed_lessons_count)
+		SELECT $1, $2, AVG(cl.score), COALESCE(SUM(cl.time_spent), 0), COUNT(*)
+		FROM completed_lessons cl
+		WHERE cl.user_id = $1 AND cl.course_id = $2
+		HAVING COUNT(*) > 0 AND NOT EXISTS (
+			SELECT 1 FROM lessons l
+			WHERE l.course_id = $2
+				AND EXISTS (SELECT 1 FROM content c WHERE c.lesson_id = l.id)
+				AND NOT EXISTS (
+					SELECT 1 FROM completed_lessons x WHERE x.user_id = $1 AND x.lesson_id = l.id
+				)
+		)
+		ON CONFLICT (user_id, course_id) DO NOTHING
+		RETURNING `+completedCourseColumns,
+		userID, courseID))
+}
+
+// ListCompletedLessons returns the lessons of a course the user completed.
+func ListCompletedLessons(ctx context.Context, q Querier, userID, courseID uuid.UUID) ([]models.CompletedLesson, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+completedLessonColumns+`
+		FROM completed_lessons
+		WHERE user_id = $1 AND course_id = $2
+		ORDER BY completed_at
+	`, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lessons := []models.CompletedLesson{}
+	for rows.Next() {
+		l, err := scanCompletedLesson(rows)
+		if err != nil {
+			return nil, err
+		}
+		lessons = append(lessons, *l)
+	}
+	return lessons, rows.Err()
+}
+
+// GetCompletedCourse returns the user's completion record of a course, or
+// ErrNotFound if the course is not completed.
+func GetCompletedCourse(ctx context.Context, q Querier, userID, courseID uuid.UUID) (*models.CompletedCourse, error) {
+	return scanCompletedCourse(q.QueryRow(ctx, `
+		SELECT `+completedCourseColumns+` FROM completed_courses WHERE user_id = $1 AND course_id = $2
+	`, userID, courseID))
+}
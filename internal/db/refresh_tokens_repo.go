@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrRefreshTokenExpired is returned when rotating an expired refresh token.
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	// ErrRefreshTokenReused is returned when a revoked refresh token is
+	// presented again. Its whole token family is revoked as a precaution.
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+)
+
+// InsertRefreshToken stores the hash of a refresh token that starts a new token family.
+func InsertRefreshToken(ctx context.Context, q Querier, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, uuid_generate_v4(), $2, $3)
+	`, userID, tokenHash, expiresAt)
+	return err
+}
+
+// RotateRefreshToken revokes the token identified by oldHash and stores
+// newHash as its replacement in the same family. It returns the owner's ID.
+func RotateRefreshToken(ctx context.Context, c Conn, oldHash, newHash string, expiresAt time.Time) (uuid.UUID, error) {
+	tx, err := c.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		id, userID, familyID uuid.UUID
+		expires              time.Time
+		revokedAt            *time.Time
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT id, user_id, family_id, expires_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`, oldHash).Scan(&id, &userID, &familyID, &expires, &revokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrNotFound
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if revokedAt != nil {
+		// The token was rotated or revoked before, so whoever presents it now
+		// may have stolen it: end every session in the family.
+		if err := revokeFamily(ctx, tx, familyID); err != nil {
+			return uuid.Nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return uuid.Nil, err
+		}
+		return uuid.Nil, ErrRefreshTokenReused
+	}
+	if time.Now().After(expires) {
+		return uuid.Nil, ErrRefreshTokenExpired
+	}
+
+	var newID uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, userID, familyID, newHash, expiresAt).Scan(&newID); err != nil {
+		return uuid.Nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $2
+		WHERE id = $1
+	`, id, newID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, tx.Commit(ctx)
+}
+
+// RevokeRefreshToken revokes the family of the token identified by tokenHash.
+func RevokeRefreshToken(ctx context.Context, c Conn, tokenHash string) error {
+	return pgx.BeginFunc(ctx, c, func(tx pgx.Tx) error {
+		var familyID uuid.UUID
+		err := tx.QueryRow(ctx, `SELECT family_id FROM refresh_tokens WHERE token_hash = $1`, tokenHash).Scan(&familyID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return revokeFamily(ctx, tx, familyID)
+	})
+}
+
+func revokeFamily(ctx context.Context, tx pgx.Tx, familyID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`, familyID)
+	return err
+}
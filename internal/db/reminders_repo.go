@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// DueReminder is a user due a daily study reminder.
+type DueReminder struct {
+	UserID uuid.UUID
+	// LocalDate is today in the user's timezone.
+	LocalDate  time.Time
+	GoalMin    int
+	StudiedMin int
+}
+
+// ListDueReminders returns up to limit users with daily reminders on whose
+// local reminder time passed less than window ago, who were not reminded
+// today and who have not met their daily goal today. Users without settings
+// get the defaults; users with an unknown timezone are skipped.
+func ListDueReminders(ctx context.Context, q Querier, window time.Duration, limit int) ([]DueReminder, error) {
+	rows, err := q.Query(ctx, `
+		WITH prefs AS (
+			SELECT u.id AS user_id,
+				COALESCE(s.timezone, 'UTC') AS timezone,
+				COALESCE(s.notification_study_time, '09:00') AS study_time,
+				COALESCE(s.study_daily_goal_min, 30) AS goal_min
+			FROM users u
+			LEFT JOIN user_settings s ON s.user_id = u.id
+			WHERE COALESCE(s.notification_daily_reminder, true)
+		),
+		local AS (
+			SELECT p.user_id, p.timezone, p.goal_min, p.study_time::time AS study_time,
+				CURRENT_TIMESTAMP AT TIME ZONE p.timezone AS local_now
+			FROM prefs p
+			WHERE p.timezone IN (SELECT name FROM pg_timezone_names)
+			  AND p.study_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'
+		),
+		due AS (
+			SELECT l.user_id, l.timezone, l.goal_min, l.local_now::date AS local_date
+			FROM local l
+			WHERE l.local_now >= l.local_now::date + l.study_time
+			  AND l.local_now < l.local_now::date + l.study_time + $1::interval
+			  AND NOT EXISTS (
+				  SELECT 1 FROM study_reminders r
+				  WHERE r.user_id = l.user_id AND r.local_date = l.local_now::date)
+		)
+		SELECT d.user_id, d.local_date, d.goal_min, studied.minutes
+		FROM due d
+		CROSS JOIN LATERAL (
+			SELECT (COALESCE(SUM(ss.duration_sec), 0) / 60)::integer AS minutes
+			FROM study_sessions ss
+			WHERE ss.user_id = d.user_id
+			  AND ss.started_at >= (d.local_date::timestamp AT TIME ZONE d.timezone)
+			  AND (ss.started_at AT TIME ZONE d.timezone)::date = d.local_date
+		) studied
+		WHERE studied.minutes < d.goal_min
+		LIMIT $2
+	`, window, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []DueReminder
+	for rows.Next() {
+		var r DueReminder
+		if err := rows.Scan(&r.UserID, &r.LocalDate, &r.GoalMin, &r.StudiedMin); err != nil {
+			return nil, err
+		}
+		due = append(due, r)
+	}
+	return due, rows.Err()
+}
+
+// RecordStudyReminder records that the user was reminded for localDate. It
+// returns ErrNotFound when they already were.
+func RecordStudyReminder(ctx context.Context, q Querier, userID uuid.UUID, localDate time.Time) error {
+	var got uuid.UUID
+	err := q.QueryRow(ctx, `
+		INSERT INTO study_reminders (user_id, local_date)
+		VALUES ($1, $2::date)
+		ON CONFLICT DO NOTHING
+		RETURNING user_id
+	`, userID, localDate).Scan(&got)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Markers delimiting the matched words in SearchHit snippets.
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// headlineOptions are the ts_headline options used for snippets.
+const headlineOptions = "StartSel=" + HighlightStart + ", StopSel=" + HighlightStop +
+	", MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// SearchHit is a course, lesson or content item matching a search.
+type SearchHit struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id"`
+	// CourseID and LessonID are the parents of lessons and contents.
+	CourseID *uuid.UUID `json:"course_id,omitempty"`
+	LessonID *uuid.UUID `json:"lesson_id,omitempty"`
+	Title    string     `json:"title"`
+	// Snippet is an excerpt of the matching text with the matched words
+	// between HighlightStart and HighlightStop.
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+// Search returns the hits of the given types ("course", "lesson" and
+// "content") matching the web-search style query text, best ranked first.
+// The query is matched in English and in Ukrainian; snippets are
+// highlighted in the language that matched.
+func Search(ctx context.Context, q Querier, text string, types []string, limit, offset int) ([]SearchHit, error) {
+	rows, err := q.Query(ctx, `
+		WITH q AS (
+			SELECT search_query($1) AS query,
+				websearch_to_tsquery('english', $1) AS en,
+				websearch_to_tsquery('ukrainian', $1) AS uk
+		),
+		hits AS (
+			SELECT 'course' AS type, c.id, NULL::uuid AS course_id, NULL::uuid AS lesson_id,
+				c.name AS title, c.description AS body, ts_rank(c.search_vector, q.query) AS rank
+			FROM courses c, q
+			WHERE 'course' = ANY($2) AND c.search_vector @@ q.query
+			UNION ALL
+			SELECT 'lesson', l.id, l.course_id, NULL::uuid,
+				l.title, l.title, ts_rank(l.search_vector, q.query)
+			FROM lessons l, q
+			WHERE 'lesson' = ANY($2) AND l.search_vector @@ q.query
+			UNION ALL
+			SELECT 'content', ct.id, l.course_id, ct.lesson_id,
+				ct.title, t.text_content, ts_rank(t.search_vector, q.query)
+			FROM theory_content t
+			JOIN content ct ON ct.id = t.id
+			JOIN lessons l ON l.id = ct.lesson_id, q
+			WHERE 'content' = ANY($2) AND t.search_vector @@ q.query
+		),
+		page AS (
+			SELECT * FROM hits
+			ORDER BY rank DESC, id
+			LIMIT $3 OFFSET $4
+		)
+		-- Snippets are only built for the page.
+		SELECT p.type, p.id, p.course_id, p.lesson_id, p.title,
+			CASE WHEN to_tsvector('english', p.body) @@ q.en
+				THEN ts_headline('english', p.body, q.en, $5)
+				ELSE ts_headline('ukrainian', p.body, q.uk, $5)
+			END,
+			p.rank
+		FROM page p, q
+		ORDER BY p.rank DESC, p.id
+	`, text, types, limit, offset, headlineOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []SearchHit{}
+	for rows.Next() {
+		var h SearchHit
+		if err := rows.Scan(&h.Type, &h.ID, &h.CourseID, &h.LessonID, &h.Title, &h.Snippet, &h.Rank); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// UkrainianStemming reports whether the ukrainian text search configuration
+// uses a stemming dictionary, rather than only matching words as written.
+func UkrainianStemming(ctx context.Context, q Querier) (bool, error) {
+	var stemmed bool
+	err := q.QueryRow(ctx, `SELECT search_ukrainian_stemmed()`).Scan(&stemmed)
+	return stemmed, err
+}
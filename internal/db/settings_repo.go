@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+// SettingsChannel is the notification channel announcing changes to the
+// settings table.
+const SettingsChannel = "settings_changed"
+
+const settingColumns = `id, key, value, description, is_protected, created_at, updated_at`
+
+func scanSetting(row pgx.Row) (*models.Setting, error) {
+	var s models.Setting
+	err := row.Scan(
+		&s.ID,
+		&s.Key,
+		&s.Value,
+		&s.Description,
+		&s.IsProtected,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListSettings returns all application settings ordered by key.
+func ListSettings(ctx context.Context, q Querier) ([]models.Setting, error) {
+	rows, err := q.Query(ctx, `SELECT `+settingColumns+` FROM settings ORDER BY key`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settings []models.Setting
+	for rows.Next() {
+		s, err := scanSetting(rows)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, *s)
+	}
+	return settings, rows.Err()
+}
+
+// LockSetting returns a setting and locks it for the rest of the transaction.
+func LockSetting(ctx context.Context, tx pgx.Tx, key string) (*models.Setting, error) {
+	return scanSetting(tx.QueryRow(ctx, `
+		SELECT `+settingColumns+` FROM settings WHERE key = $1 FOR UPDATE
+	`, key))
+}
+
+// UpsertSetting sets the value of a setting, creating it if needed. A nil
+// description keeps the current one. Protected settings are left unchanged
+// and ErrNotFound is returned.
+func UpsertSetting(ctx context.Context, q Querier, key, value string, description *string) (*models.Setting, error) {
+	return scanSetting(q.QueryRow(ctx, `
+		INSERT INTO settings (key, value, description)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET
+			value = EXCLUDED.value,
+			description = COALESCE(EXCLUDED.description, settings.description),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE NOT settings.is_protected
+		RETURNING `+settingColumns,
+		key, value, description))
+}
+
+// DeleteSetting deletes an unprotected setting.
+func DeleteSetting(ctx context.Context, q Querier, key string) error {
+	tag, err := q.Exec(ctx, `DELETE FROM settings WHERE key = $1 AND NOT is_protected`, key)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+const studySessionColumns = `id, user_id, content_id, started_at, last_heartbeat_at, ended_at,
+	duration_sec, created_at, updated_at`
+
+func scanStudySession(row pgx.Row) (*models.StudySession, error) {
+	var s models.StudySession
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.ContentID,
+		&s.StartedAt,
+		&s.LastHeartbeatAt,
+		&s.EndedAt,
+		&s.DurationSec,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// StudyDay is the time a user studied on one day.
+type StudyDay struct {
+	Date    time.Time
+	Minutes int
+}
+
+// GetStudyPreferences returns the user's timezone and daily study goal in
+// minutes, falling back to the defaults for users without settings.
+func GetStudyPreferences(ctx context.Context, q Querier, userID uuid.UUID) (timezone string, goalMin int, err error) {
+	err = q.QueryRow(ctx, `
+		SELECT COALESCE(s.timezone, 'UTC'), COALESCE(s.study_daily_goal_min, 30)
+		FROM users u
+		LEFT JOIN user_settings s ON s.user_id = u.id
+		WHERE u.id = $1
+	`, userID).Scan(&timezone, &goalMin)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", 0, ErrNotFound
+	}
+	return timezone, goalMin, err
+}
+
+// CloseStudySessions ends the user's open session, if any, at its last heartbeat.
+func CloseStudySessions(ctx context.Context, q Querier, userID uuid.UUID) error {
+	_, err := q.Exec(ctx, `
+		UPDATE study_sessions SET ended_at = last_heartbeat_at, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND ended_at IS NULL
+	`, userID)
+	return err
+}
+
+// CreateStudySession opens a study session starting now.
+func CreateStudySession(ctx context.Context, q Querier, userID uuid.UUID, contentID *uuid.UUID) (*models.StudySession, error) {
+	return scanStudySession(q.QueryRow(ctx, `
+		INSERT INTO study_sessions (user_id, content_id)
+		VALUES ($1, $2)
+		RETURNING `+studySessionColumns,
+		userID, contentID))
+}
+
+// LockStudySession returns a study session and locks it for the rest of the transaction.
+func LockStudySession(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*models.StudySession, error) {
+	return scanStudySession(tx.QueryRow(ctx, `
+		SELECT `+studySessionColumns+` FROM study_sessions WHERE id = $1 FOR UPDATE
+	`, id))
+}
+
+// UpdateStudySession records a heartbeat at the given time that adds
+// creditSec seconds of study, and ends the session if end is set.
+func UpdateStudySession(ctx context.Context, q Querier, id uuid.UUID, at time.Time, creditSec int, end bool) (*models.StudySession, error) {
+	return scanStudySession(q.QueryRow(ctx, `
+		UPDATE study_sessions SET
+			last_heartbeat_at = $2,
+			duration_sec = duration_sec + $3,
+			ended_at = CASE WHEN $4 THEN $2 ELSE NULL END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+studySessionColumns,
+		id, at, creditSec, end))
+}
+
+// RefreshUserStudyTime recomputes the user's total study time from their sessions.
+func RefreshUserStudyTime(ctx context.Context, q Querier, userID uuid.UUID) error {
+	_, err := q.Exec(ctx, `
+		UPDATE users SET
+			total_study_time_min = (
+				SELECT COALESCE(SUM(duration_sec), 0) / 60 FROM study_sessions WHERE user_id = $1
+			),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, userID)
+	return err
+}
+
+// ListStudyDays returns the minutes studied per day since from, with days
+// taken in the given timezone. Sessions count towards the day they started
+// on; days without study are omitted.
+func ListStudyDays(ctx context.Context, q Querier, userID uuid.UUID, timezone string, from time.Time) ([]StudyDay, error) {
+	rows, err := q.Query(ctx, `
+		SELECT (started_at AT TIME ZONE $2)::date AS day, SUM(duration_sec) / 60
+		FROM study_sessions
+		WHERE user_id = $1 AND (started_at AT TIME ZONE $2)::date >= $3::date AND duration_sec > 0
+		GROUP BY day
+		ORDER BY day
+	`, userID, timezone, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []StudyDay
+	for rows.Next() {
+		var d StudyDay
+		if err := rows.Scan(&d.Date, &d.Minutes); err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+// ListStudyDates returns every day, in the given timezone and oldest
+// first, on which the user studied at all.
+func ListStudyDates(ctx context.Context, q Querier, userID uuid.UUID, timezone string) ([]time.Time, error) {
+	rows, err := q.Query(ctx, `
+		SELECT DISTINCT (started_at AT TIME ZONE $2)::date AS day
+		FROM study_sessions
+		WHERE user_id = $1 AND duration_sec > 0
+		ORDER BY day
+	`, userID, timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var d time.Time
+		if err := rows.Scan(&d); err != nil {
+			return nil, err
+		}
+		dates = append(dates, d)
+	}
+	return dates, rows.Err()
+}
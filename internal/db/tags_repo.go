@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"mathtermind-go/internal/models"
+)
+
+// ErrTagNameTaken is returned when creating or renaming a tag to an existing name.
+var ErrTagNameTaken = errors.New("tag name is already taken")
+
+const tagColumns = `t.id, t.name, t.category, t.created_at, t.updated_at`
+
+func scanTag(row pgx.Row) (*models.Tag, error) {
+	var t models.Tag
+	err := row.Scan(&t.ID, &t.Name, &t.Category, &t.CreatedAt, &t.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrTagNameTaken
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// TagSummary is a tag with the number of courses it is attached to.
+type TagSummary struct {
+	models.Tag
+	CourseCount int `json:"course_count"`
+}
+
+// ListTags returns the tags ordered by category and name with their course
+// counts. An empty category returns the tags of every category.
+func ListTags(ctx context.Context, q Querier, category string) ([]TagSummary, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+tagColumns+`, COUNT(ct.course_id)
+		FROM tags t
+		LEFT JOIN course_tags ct ON ct.tag_id = t.id
+		WHERE $1 = '' OR t.category = $1
+		GROUP BY t.id
+		ORDER BY t.category, t.name
+	`, category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []TagSummary{}
+	for rows.Next() {
+		var t TagSummary
+		if err := rows.Scan(&t.ID, &t.Name, &t.Category, &t.CreatedAt, &t.UpdatedAt, &t.CourseCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// GetTag returns the tag with the given ID.
+func GetTag(ctx context.Context, q Querier, id uuid.UUID) (*models.Tag, error) {
+	return scanTag(q.QueryRow(ctx, `SELECT `+tagColumns+` FROM tags t WHERE t.id = $1`, id))
+}
+
+// GetTagByName returns the tag with the given name.
+func GetTagByName(ctx context.Context, q Querier, name string) (*models.Tag, error) {
+	return scanTag(q.QueryRow(ctx, `SELECT `+tagColumns+` FROM tags t WHERE t.name = $1`, name))
+}
+
+// CreateTag inserts a new tag and returns it.
+func CreateTag(ctx context.Context, q Querier, name, category string) (*models.Tag, error) {
+	return scanTag(q.QueryRow(ctx, `
+		INSERT INTO tags AS t (name, category)
+		VALUES ($1, $2)
+		RETURNING `+tagColumns,
+		name, category))
+}
+
+// UpdateTag renames or recategorizes a tag; nil fields are left untouched.
+func UpdateTag(ctx context.Context, q Querier, id uuid.UUID, name, category *string) (*models.Tag, error) {
+	return scanTag(q.QueryRow(ctx, `
+		UPDATE tags AS t SET
+			name = COALESCE($2, t.name),
+			category = COALESCE($3, t.category),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE t.id = $1
+		RETURNING `+tagColumns,
+		id, name, category))
+}
+
+// DeleteTag deletes a tag and detaches it from every course.
+func DeleteTag(ctx context.Context, q Querier, id uuid.UUID) error {
+	tag, err := q.Exec(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListCourseTags returns the tags attached to a course ordered by name.
+func ListCourseTags(ctx context.Context, q Querier, courseID uuid.UUID) ([]models.Tag, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+tagColumns+`
+		FROM tags t
+		JOIN course_tags ct ON ct.tag_id = t.id
+		WHERE ct.course_id = $1
+		ORDER BY t.name
+	`, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		t, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *t)
+	}
+	return tags, rows.Err()
+}
+
+// AttachTag attaches a tag to a course; attaching it again has no effect.
+// It returns ErrNotFound if the course or the tag does not exist.
+func AttachTag(ctx context.Context, q Querier, courseID, tagID uuid.UUID) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO course_tags (course_id, tag_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, courseID, tagID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return ErrNotFound
+	}
+	return err
+}
+
+// DetachTag removes a tag from a course. It returns ErrNotFound if the tag
+// was not attached.
+func DetachTag(ctx context.Context, q Querier, courseID, tagID uuid.UUID) error {
+	tag, err := q.Exec(ctx, `DELETE FROM course_tags WHERE course_id = $1 AND tag_id = $2`, courseID, tagID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type txKey struct{}
+
+// ErrNoTx is returned by functions that must run in a transaction when
+// their context carries none.
+var ErrNoTx = errors.New("no transaction in context")
+
+// TxManager runs units of work in transactions carried by their context,
+// so that everything called with that context takes part in the same
+// transaction without passing it along explicitly.
+type TxManager struct {
+	pool *pgxpool.Pool
+}
+
+// NewTxManager creates a transaction manager on the pool.
+func NewTxManager(pool *pgxpool.Pool) *TxManager {
+	return &TxManager{pool: pool}
+}
+
+// WithTx runs fn in a transaction, committing it when fn returns nil and
+// rolling it back otherwise. Called inside a transaction, fn joins it.
+func (m *TxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.with(ctx, pgx.TxOptions{}, fn)
+}
+
+// WithReadOnlyTx runs fn in a read-only repeatable read transaction, so
+// everything fn reads comes from one snapshot. Called inside a transaction,
+// fn joins it.
+func (m *TxManager) WithReadOnlyTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.with(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, fn)
+}
+
+func (m *TxManager) with(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context) error) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+	return pgx.BeginTxFunc(ctx, m.pool, opts, func(tx pgx.Tx) error {
+		return fn(ContextWithTx(ctx, tx))
+	})
+}
+
+// Conn returns the transaction carried by ctx, or the pool outside a
+// transaction.
+func (m *TxManager) Conn(ctx context.Context) Conn {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return m.pool
+}
+
+// TxFromContext returns the transaction started by TxManager that ctx
+// carries.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// RequireTx is TxFromContext for functions that must run in a
+// transaction: it returns ErrNoTx when ctx carries none.
+func RequireTx(ctx context.Context) (pgx.Tx, error) {
+	tx, ok := TxFromContext(ctx)
+	if !ok {
+		return nil, ErrNoTx
+	}
+	return tx, nil
+}
+
+// ContextWithTx returns a context carrying tx, so that TxManager and the
+// repositories join a transaction begun elsewhere.
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
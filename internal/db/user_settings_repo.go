@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mathtermind-go/internal/models"
+)
+
+const userSettingColumns = `id, user_id, theme, notification_daily_reminder, notification_achievement_alerts,
+	notification_study_time, accessibility_font_size, accessibility_high_contrast,
+	study_daily_goal_min, study_preferred_subject, timezone, created_at, updated_at`
+
+func scanUserSetting(row pgx.Row) (*models.UserSetting, error) {
+	var s models.UserSetting
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.Theme,
+		&s.NotificationDailyReminder,
+		&s.NotificationAchievementAlerts,
+		&s.NotificationStudyTime,
+		&s.AccessibilityFontSize,
+		&s.AccessibilityHighContrast,
+		&s.StudyDailyGoalMin,
+		&s.StudyPreferredSubject,
+		&s.Timezone,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UserSettingPatch holds the user settings to change; nil fields are kept.
+type UserSettingPatch struct {
+	Theme                         *string
+	NotificationDailyReminder     *bool
+	NotificationAchievementAlerts *bool
+	NotificationStudyTime         *string
+	AccessibilityFontSize         *string
+	AccessibilityHighContrast     *bool
+	StudyDailyGoalMin             *int
+	StudyPreferredSubject         *string
+	Timezone                      *string
+}
+
+// EnsureUserSettings creates the user's settings row with the schema
+// defaults unless it exists.
+func EnsureUserSettings(ctx context.Context, q Querier, userID uuid.UUID) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO user_settings (user_id) VALUES ($1)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID)
+	return err
+}
+
+// GetUserSettings returns the user's settings row.
+func GetUserSettings(ctx context.Context, q Querier, userID uuid.UUID) (*models.UserSetting, error) {
+	return scanUserSetting(q.QueryRow(ctx, `
+		SELECT `+userSettingColumns+` FROM user_settings WHERE user_id = $1
+	`, userID))
+}
+
+// UpdateUserSettings applies a patch to the user's settings row and returns
+// the updated settings.
+func UpdateUserSettings(ctx context.Context, q Querier, userID uuid.UUID, p UserSettingPatch) (*models.UserSetting, error) {
+	return scanUserSetting(q.QueryRow(ctx, `
+		UPDATE user_settings SET
+			theme = COALESCE($2, theme),
+			notification_daily_reminder = COALESCE($3, notification_daily_reminder),
+			notification_achievement_alerts = COALESCE($4, notification_achievement_alerts),
+			notification_study_time = COALESCE($5, notification_study_time),
+			accessibility_font_size = COALESCE($6, accessibility_font_size),
+			accessibility_high_contrast = COALESCE($7, accessibility_high_contrast),
+			study_daily_goal_min = COALESCE($8, study_daily_goal_min),
+			study_preferred_subject = COALESCE($9, study_preferred_subject),
+			timezone = COALESCE($10, timezone),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+		RETURNING `+userSettingColumns,
+		userID, p.Theme, p.NotificationDailyReminder, p.NotificationAchievementAlerts, p.NotificationStudyTime,
+		p.AccessibilityFontSize, p.AccessibilityHighContrast, p.StudyDailyGoalMin, p.StudyPreferredSubject, p.Timezone))
+}
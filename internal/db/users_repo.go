@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"mathtermind-go/internal/models"
+)
+
+var (
+	// ErrUsernameTaken is returned when creating a user with an existing username.
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrEmailTaken is returned when creating a user with an existing email.
+	ErrEmailTaken = errors.New("email is already registered")
+)
+
+const userColumns = `id, username, email, password_hash, first_name, last_name, avatar_url, profile_data,
+	age_group, role, points, experience_level, total_study_time_min, created_at, updated_at`
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	var u models.User
+	err := row.Scan(
+		&u.ID,
+		&u.Username,
+		&u.Email,
+		&u.PasswordHash,
+		&u.FirstName,
+		&u.LastName,
+		&u.AvatarURL,
+		&u.ProfileData,
+		&u.AgeGroup,
+		&u.Role,
+		&u.Points,
+		&u.ExperienceLevel,
+		&u.TotalStudyTimeMin,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// CreateUser inserts a new user and returns it.
+func CreateUser(ctx context.Context, q Querier, u models.User) (*models.User, error) {
+	user, err := scanUser(q.QueryRow(ctx, `
+		INSERT INTO users (username, email, password_hash, first_name, last_name, age_group)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+userColumns,
+		u.Username, u.Email, u.PasswordHash, u.FirstName, u.LastName, u.AgeGroup))
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		switch pgErr.ConstraintName {
+		case "users_username_key":
+			return nil, ErrUsernameTaken
+		case "users_email_key":
+			return nil, ErrEmailTaken
+		}
+	}
+	return user, err
+}
+
+// GetUserByID returns the user with the given ID.
+func GetUserByID(ctx context.Context, q Querier, id uuid.UUID) (*models.User, error) {
+	return scanUser(q.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+}
+
+// GetUserByUsername returns the user with the given username.
+func GetUserByUsername(ctx context.Context, q Querier, username string) (*models.User, error) {
+	return scanUser(q.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE username = $1`, username))
+}
+
+// GetUserByEmail returns the user with the given email, compared case
+// insensitively.
+func GetUserByEmail(ctx context.Context, q Querier, email string) (*models.User, error) {
+	return scanUser(q.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE lower(email) = lower($1)`, email))
+}
+
+// UpdateUserRole changes the role of a user and returns the updated user.
+func UpdateUserRole(ctx context.Context, q Querier, id uuid.UUID, role string) (*models.User, error) {
+	return scanUser(q.QueryRow(ctx, `
+		UPDATE users SET role = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+userColumns,
+		id, role))
+}
@@ -68,12 +68,14 @@ func statusCodeFromError(err error) int {
 		switch e.Code {
 		case ErrCodeValidation:
 			return http.StatusBadRequest
-		case ErrCodeUnauthorized:
+		case ErrCodeUnauthorized, ErrCodeAuthError, ErrCodeLoginError, ErrCodeTokenError:
 			return http.StatusUnauthorized
-		case ErrCodeForbidden:
+		case ErrCodeForbidden, ErrCodePermissionDenied:
 			return http.StatusForbidden
 		case ErrCodeNotFound:
 			return http.StatusNotFound
+		case ErrCodeInvalidState:
+			return http.StatusConflict
 		case ErrCodeDBError, ErrCodeDBConnection, ErrCodeDBQuery, ErrCodeDBMigration:
 			return http.StatusInternalServerError
 		default:
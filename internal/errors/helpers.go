@@ -61,6 +61,15 @@ func WrapError(err error, code ErrorCode, message string) *Error {
 	return Wrap(err, code, message)
 }
 
+// WrapDB passes application errors through and wraps everything else, such
+// as errors from the database, as a query error
+func WrapDB(err error, message string) *Error {
+	if e, ok := As(err); ok {
+		return e
+	}
+	return Wrap(err, ErrCodeDBQuery, message)
+}
+
 // IsError checks if an error is of a specific error code
 func IsError(err error, code ErrorCode) bool {
 	return Is(err, code)
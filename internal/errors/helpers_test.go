@@ -0,0 +1,21 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"mathtermind-go/internal/errors"
+)
+
+func TestWrapDB(t *testing.T) {
+	notFound := errors.NotFound("course", 1)
+	if got := errors.WrapDB(notFound, "failed to get course"); got != notFound {
+		t.Errorf("WrapDB() = %v, want the application error passed through", got)
+	}
+
+	cause := stderrors.New("connection reset")
+	got := errors.WrapDB(cause, "failed to get course")
+	if got.Code != errors.ErrCodeDBQuery || got.Message != "failed to get course" || !stderrors.Is(got, cause) {
+		t.Errorf("WrapDB() = %+v, want a query error wrapping the cause", got)
+	}
+}
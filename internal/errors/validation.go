@@ -1,6 +1,11 @@
 package errors
 
 import (
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -27,19 +32,63 @@ func (ve ValidationErrors) ToMap() map[string]string {
 	return errs
 }
 
+// validate is shared by all validations; validator.Validate caches struct
+// metadata and is safe for concurrent use.
+var validate = newValidator()
+
+// timeOfDayPattern matches a 24-hour time of day in HH:MM format.
+var timeOfDayPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// usernamePattern matches the characters allowed in usernames. It excludes
+// @, so that a username can never be mistaken for an email address at login.
+var usernamePattern = regexp.MustCompile(`^[\p{L}\p{N}._-]+$`)
+
+// newValidator returns a validator that reports fields by their JSON names
+// and knows the custom tags:
+//
+//   - hhmm: a 24-hour time of day in HH:MM format
+//   - timezone: an IANA time zone name such as "Europe/Kyiv"
+//   - username: letters, digits, dots, hyphens and underscores
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+	v.RegisterValidation("hhmm", func(fl validator.FieldLevel) bool {
+		return timeOfDayPattern.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("timezone", func(fl validator.FieldLevel) bool {
+		name := fl.Field().String()
+		if name == "" || name == "Local" {
+			return false
+		}
+		_, err := time.LoadLocation(name)
+		return err == nil
+	})
+	v.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return usernamePattern.MatchString(fl.Field().String())
+	})
+	return v
+}
+
 // ValidateStruct validates a struct and returns validation errors if any
 func ValidateStruct(s any) error {
-	validate := validator.New()
-	
 	if err := validate.Struct(s); err != nil {
 		if ve, ok := err.(validator.ValidationErrors); ok {
 			return newValidationError(ve)
 		}
-		
+
 		// If it's not a validation error, return as internal error
 		return Wrap(err, ErrCodeInternal, "Failed to validate request")
 	}
-	
+
 	return nil
 }
 
@@ -69,6 +118,18 @@ func getValidationMessage(e validator.FieldError) string {
 		return "Value is too short"
 	case "max":
 		return "Value is too long"
+	case "gte":
+		return "Must be at least " + e.Param()
+	case "lte":
+		return "Must be at most " + e.Param()
+	case "oneof":
+		return "Must be one of: " + strings.ReplaceAll(e.Param(), " ", ", ")
+	case "hhmm":
+		return "Must be a time in HH:MM format"
+	case "timezone":
+		return "Must be a time zone name such as Europe/Kyiv"
+	case "username":
+		return "May only contain letters, digits, dots, hyphens and underscores"
 	default:
 		return e.Error()
 	}
@@ -0,0 +1,87 @@
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"mathtermind-go/internal/errors"
+)
+
+type settingsForm struct {
+	Theme     *string `json:"theme" validate:"omitempty,oneof=light dark"`
+	StudyTime *string `json:"notification_study_time" validate:"omitempty,hhmm"`
+	Timezone  *string `json:"timezone" validate:"omitempty,timezone"`
+	GoalMin   *int    `json:"study_daily_goal_min" validate:"omitempty,gte=1,lte=1440"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+
+	tests := []struct {
+		name string
+		form settingsForm
+		want map[string]string
+	}{
+		{name: "empty", form: settingsForm{}},
+		{
+			name: "valid",
+			form: settingsForm{Theme: str("dark"), StudyTime: str("07:30"), Timezone: str("Europe/Kyiv"), GoalMin: num(45)},
+		},
+		{
+			name: "invalid",
+			form: settingsForm{Theme: str("neon"), StudyTime: str("24:00"), Timezone: str("Mars/Olympus"), GoalMin: num(0)},
+			want: map[string]string{
+				"theme":                   "Must be one of: light, dark",
+				"notification_study_time": "Must be a time in HH:MM format",
+				"timezone":                "Must be a time zone name such as Europe/Kyiv",
+				"study_daily_goal_min":    "Must be at least 1",
+			},
+		},
+		{name: "single digit hour", form: settingsForm{StudyTime: str("9:00")}, want: map[string]string{"notification_study_time": "Must be a time in HH:MM format"}},
+		{name: "local timezone", form: settingsForm{Timezone: str("Local")}, want: map[string]string{"timezone": "Must be a time zone name such as Europe/Kyiv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errors.ValidateStruct(&tt.form)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateStruct() error = %v, want nil", err)
+				}
+				return
+			}
+			e, ok := errors.As(err)
+			if !ok || e.Code != errors.ErrCodeValidation {
+				t.Fatalf("ValidateStruct() error = %v, want a validation error", err)
+			}
+			if got := e.Details["errors"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateStruct() errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	type registerForm struct {
+		Username string `json:"username" validate:"required,username"`
+	}
+	tests := []struct {
+		username string
+		valid    bool
+	}{
+		{"olena_k", true},
+		{"Олена.К-1", true},
+		{"victim@example.com", false},
+		{"two words", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			err := errors.ValidateStruct(&registerForm{Username: tt.username})
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateStruct(%q) error = %v, want valid = %v", tt.username, err, tt.valid)
+			}
+		})
+	}
+}
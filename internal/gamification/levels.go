@@ -0,0 +1,63 @@
+package gamification
+
+import (
+	"fmt"
+	"math"
+)
+
+// Curve defines how many points each experience level requires. Reaching
+// level 2 takes Base points and every following level takes Growth times
+// as many points as the previous one. Levels start at 1 and stop at
+// MaxLevel.
+type Curve struct {
+	Base     int
+	Growth   float64
+	MaxLevel int
+}
+
+// DefaultCurve is used when no curve is configured.
+var DefaultCurve = Curve{Base: 100, Growth: 1.5, MaxLevel: 100}
+
+// Validate checks that the curve is usable.
+func (c Curve) Validate() error {
+	switch {
+	case c.Base <= 0:
+		return fmt.Errorf("level curve base must be positive, got %d", c.Base)
+	case c.Growth < 1:
+		return fmt.Errorf("level curve growth must be at least 1, got %g", c.Growth)
+	case c.MaxLevel < 1:
+		return fmt.Errorf("level curve max level must be at least 1, got %d", c.MaxLevel)
+	}
+	return nil
+}
+
+// step returns the points needed to advance from level to level+1.
+func (c Curve) step(level int) int {
+	return int(math.Round(float64(c.Base) * math.Pow(c.Growth, float64(level-1))))
+}
+
+// Threshold returns the total points needed to reach level.
+func (c Curve) Threshold(level int) int {
+	level = min(level, c.MaxLevel)
+	total := 0
+	for l := 1; l < level; l++ {
+		total += c.step(l)
+		if total < 0 {
+			return math.MaxInt
+		}
+	}
+	return total
+}
+
+// Level returns the experience level reached with the given points.
+func (c Curve) Level(points int) int {
+	level, total := 1, 0
+	for level < c.MaxLevel {
+		total += c.step(level)
+		if total > points || total < 0 {
+			break
+		}
+		level++
+	}
+	return level
+}
@@ -0,0 +1,70 @@
+package gamification_test
+
+import (
+	"testing"
+
+	"mathtermind-go/internal/gamification"
+)
+
+func TestCurveLevel(t *testing.T) {
+	curve := gamification.Curve{Base: 100, Growth: 1.5, MaxLevel: 5}
+
+	tests := []struct {
+		points int
+		want   int
+	}{
+		{0, 1},
+		{99, 1},
+		{100, 2},
+		{249, 2},
+		{250, 3},
+		{475, 4},
+		{812, 4},
+		{813, 5},
+		{1_000_000, 5},
+	}
+
+	for _, tt := range tests {
+		if got := curve.Level(tt.points); got != tt.want {
+			t.Errorf("Level(%d) = %d, want %d", tt.points, got, tt.want)
+		}
+	}
+}
+
+func TestCurveThreshold(t *testing.T) {
+	curve := gamification.Curve{Base: 100, Growth: 2, MaxLevel: 10}
+
+	for level := 1; level <= 10; level++ {
+		threshold := curve.Threshold(level)
+		if got := curve.Level(threshold); got != level {
+			t.Errorf("Level(Threshold(%d)) = %d", level, got)
+		}
+		if level > 1 {
+			if got := curve.Level(threshold - 1); got != level-1 {
+				t.Errorf("Level(Threshold(%d)-1) = %d, want %d", level, got, level-1)
+			}
+		}
+	}
+}
+
+func TestCurveValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		curve   gamification.Curve
+		wantErr bool
+	}{
+		{"default", gamification.DefaultCurve, false},
+		{"flat", gamification.Curve{Base: 50, Growth: 1, MaxLevel: 10}, false},
+		{"zero base", gamification.Curve{Base: 0, Growth: 1.5, MaxLevel: 10}, true},
+		{"shrinking", gamification.Curve{Base: 100, Growth: 0.5, MaxLevel: 10}, true},
+		{"no levels", gamification.Curve{Base: 100, Growth: 1.5, MaxLevel: 0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.curve.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
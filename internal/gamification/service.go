@@ -0,0 +1,199 @@
+// Package gamification awards points and experience levels. Every award is
+// appended to the points ledger under an event key, so crediting the same
+// event twice has no effect.
+package gamification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/settings"
+)
+
+// Reasons recorded in the points ledger.
+const (
+	ReasonLessonCompleted = "lesson_completed"
+	ReasonCorrectAnswer   = "correct_answer"
+	ReasonAchievement     = "achievement"
+)
+
+// Credit is a request to award points for an event.
+type Credit struct {
+	UserID   uuid.UUID
+	CourseID *uuid.UUID
+	Points   int
+	Reason   string
+	// EventKey identifies the triggering event; each key is credited at
+	// most once per user.
+	EventKey string
+	SourceID *uuid.UUID
+}
+
+// ScalePoints applies a multiplier to a points reward, rounding to the
+// nearest point.
+func ScalePoints(points int, multiplier float64) int {
+	return int(math.Round(float64(points) * multiplier))
+}
+
+// LessonCompleted returns the credit for completing a lesson: its points
+// reward scaled by the lesson points multiplier setting.
+func (s *Service) LessonCompleted(userID uuid.UUID, lesson *models.Lesson) Credit {
+	return Credit{
+		UserID:   userID,
+		CourseID: &lesson.CourseID,
+		Points:   ScalePoints(lesson.PointsReward, s.settings.LessonPointsMultiplier()),
+		Reason:   ReasonLessonCompleted,
+		EventKey: fmt.Sprintf("lesson:%s", lesson.ID),
+		SourceID: &lesson.ID,
+	}
+}
+
+// CorrectAnswer returns the credit for a correct answer. Only the first
+// correct answer to a question is credited.
+func CorrectAnswer(userID, courseID uuid.UUID, answer *models.UserAnswer) Credit {
+	return Credit{
+		UserID:   userID,
+		CourseID: &courseID,
+		Points:   answer.PointsEarned,
+		Reason:   ReasonCorrectAnswer,
+		EventKey: fmt.Sprintf("answer:%s:%s", answer.ContentID, answer.QuestionID),
+		SourceID: &answer.ID,
+	}
+}
+
+// Award is the result of a credited event.
+type Award struct {
+	Entry         *models.PointsEntry `json:"entry"`
+	Level         int                 `json:"level"`
+	LevelUp       bool                `json:"level_up"`
+	PreviousLevel int                 `json:"previous_level"`
+}
+
+// History is a page of a user's points ledger with their current standing.
+type History struct {
+	Points          int                  `json:"points"`
+	Level           int                  `json:"level"`
+	NextLevelPoints *int                 `json:"next_level_points,omitempty"`
+	Items           []models.PointsEntry `json:"items"`
+	Total           int                  `json:"total"`
+	Limit           int                  `json:"limit"`
+	Offset          int                  `json:"offset"`
+}
+
+// Service awards points and reports point history.
+type Service struct {
+	pool     *pgxpool.Pool
+	curve    Curve
+	settings *settings.Service
+}
+
+// NewService creates a gamification service using the given leveling curve
+// and runtime settings.
+func NewService(pool *pgxpool.Pool, curve Curve, runtime *settings.Service) *Service {
+	return &Service{pool: pool, curve: curve, settings: runtime}
+}
+
+// Curve returns the leveling curve.
+func (s *Service) Curve() Curve {
+	return s.curve
+}
+
+// Credit awards points in the transaction carried by ctx (see
+// db.ContextWithTx) and recomputes the user's experience level. It returns
+// nil when the event was already credited or is worth no points, and
+// db.ErrNoTx outside a transaction. It locks the course progress row of a
+// credit with a course and then the user row.
+func (s *Service) Credit(ctx context.Context, c Credit) (*Award, error) {
+	if c.Points <= 0 {
+		return nil, nil
+	}
+	tx, err := db.RequireTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The course progress row is locked before the user row, in the lock
+	// order of db.LockCourseProgress; callers may already hold it.
+	if c.CourseID != nil {
+		if _, err := db.LockCourseProgress(ctx, tx, c.UserID, *c.CourseID); err != nil {
+			return nil, err
+		}
+	}
+	// Locking the user row serializes credits, keeping balances in order.
+	balance, err := db.LockUserPoints(ctx, tx, c.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := db.InsertPointsEntry(ctx, tx, models.PointsEntry{
+		UserID:   c.UserID,
+		CourseID: c.CourseID,
+		Points:   c.Points,
+		Reason:   c.Reason,
+		EventKey: c.EventKey,
+		SourceID: c.SourceID,
+		Balance:  balance + c.Points,
+	})
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	award := &Award{
+		Entry:         entry,
+		Level:         s.curve.Level(entry.Balance),
+		PreviousLevel: s.curve.Level(balance),
+	}
+	award.LevelUp = award.Level > award.PreviousLevel
+
+	if err := db.SetUserPoints(ctx, tx, c.UserID, entry.Balance, award.Level); err != nil {
+		return nil, err
+	}
+	if c.CourseID != nil {
+		if err := db.AddCoursePoints(ctx, tx, c.UserID, *c.CourseID, c.Points); err != nil {
+			return nil, err
+		}
+	}
+	return award, nil
+}
+
+// History returns a page of the user's points ledger, newest first.
+func (s *Service) History(ctx context.Context, userID uuid.UUID, limit, offset int) (*History, error) {
+	h := &History{Limit: limit, Offset: offset}
+	err := pgx.BeginTxFunc(ctx, s.pool, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, func(tx pgx.Tx) error {
+		user, err := db.GetUserByID(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		h.Points = user.Points
+		h.Level = s.curve.Level(user.Points)
+		if h.Level < s.curve.MaxLevel {
+			next := s.curve.Threshold(h.Level + 1)
+			h.NextLevelPoints = &next
+		}
+
+		if h.Total, err = db.CountPointsEntries(ctx, tx, userID); err != nil {
+			return err
+		}
+		h.Items, err = db.ListPointsEntries(ctx, tx, userID, limit, offset)
+		return err
+	})
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, apperrors.NotFound("user", userID)
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to get points history")
+	}
+	return h, nil
+}
@@ -0,0 +1,26 @@
+package gamification_test
+
+import (
+	"testing"
+
+	"mathtermind-go/internal/gamification"
+)
+
+func TestScalePoints(t *testing.T) {
+	tests := []struct {
+		points     int
+		multiplier float64
+		want       int
+	}{
+		{10, 1, 10},
+		{10, 1.5, 15},
+		{15, 0.5, 8},
+		{10, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := gamification.ScalePoints(tt.points, tt.multiplier); got != tt.want {
+			t.Errorf("ScalePoints(%d, %g) = %d, want %d", tt.points, tt.multiplier, got, tt.want)
+		}
+	}
+}
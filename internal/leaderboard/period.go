@@ -0,0 +1,44 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period is the time window a leaderboard ranks points earned in.
+type Period string
+
+const (
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	PeriodAll   Period = "all"
+)
+
+// ParsePeriod parses a leaderboard period; the empty string is PeriodWeek.
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(s); p {
+	case "":
+		return PeriodWeek, nil
+	case PeriodWeek, PeriodMonth, PeriodAll:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown period %q", s)
+	}
+}
+
+// Since returns the first day of the period containing now, in UTC: the
+// Monday of the current week or the first day of the current month. It
+// returns the zero time for PeriodAll.
+func (p Period) Since(now time.Time) time.Time {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case PeriodWeek:
+		// Weeks start on Monday.
+		return today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	case PeriodMonth:
+		return today.AddDate(0, 0, 1-today.Day())
+	default:
+		return time.Time{}
+	}
+}
@@ -0,0 +1,63 @@
+package leaderboard_test
+
+import (
+	"testing"
+	"time"
+
+	"mathtermind-go/internal/leaderboard"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    leaderboard.Period
+		wantErr bool
+	}{
+		{"", leaderboard.PeriodWeek, false},
+		{"week", leaderboard.PeriodWeek, false},
+		{"month", leaderboard.PeriodMonth, false},
+		{"all", leaderboard.PeriodAll, false},
+		{"year", "", true},
+		{"WEEK", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := leaderboard.ParsePeriod(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriod(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePeriod(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodSince(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	kyiv := time.FixedZone("EET", 2*60*60)
+
+	tests := []struct {
+		name   string
+		period leaderboard.Period
+		now    time.Time
+		want   time.Time
+	}{
+		{"week on monday", leaderboard.PeriodWeek, time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC), day(3, 4)},
+		{"week on sunday", leaderboard.PeriodWeek, time.Date(2024, 3, 10, 23, 59, 0, 0, time.UTC), day(3, 4)},
+		{"week across months", leaderboard.PeriodWeek, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), day(2, 26)},
+		{"week in UTC", leaderboard.PeriodWeek, time.Date(2024, 3, 11, 1, 0, 0, 0, kyiv), day(3, 4)},
+		{"month", leaderboard.PeriodMonth, time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC), day(3, 1)},
+		{"month on first day", leaderboard.PeriodMonth, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), day(3, 1)},
+		{"all time", leaderboard.PeriodAll, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.period.Since(tt.now); !got.Equal(tt.want) {
+				t.Errorf("Since(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package leaderboard ranks learners by the points they earned in a period,
+// optionally limited to a course topic or an age group.
+package leaderboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/settings"
+)
+
+// Query selects a leaderboard.
+type Query struct {
+	Period   Period
+	Topic    string
+	AgeGroup string
+	// Limit is the number of top entries; zero uses the leaderboard size
+	// setting.
+	Limit int
+}
+
+// Board is a leaderboard as seen by one user.
+type Board struct {
+	Period   Period  `json:"period"`
+	Since    *string `json:"since,omitempty"`
+	Topic    string  `json:"topic,omitempty"`
+	AgeGroup string  `json:"age_group,omitempty"`
+	// Total is the number of ranked users.
+	Total   int                   `json:"total"`
+	Entries []db.LeaderboardEntry `json:"entries"`
+	// Me is the caller's entry, also when it is below the top entries. It
+	// is nil when the caller earned no matching points.
+	Me *db.LeaderboardEntry `json:"me"`
+}
+
+// Service serves leaderboards and keeps their precomputed points fresh.
+type Service struct {
+	pool     *pgxpool.Pool
+	settings *settings.Service
+}
+
+// NewService creates a leaderboard service reading its default size from
+// the runtime settings.
+func NewService(pool *pgxpool.Pool, runtime *settings.Service) *Service {
+	return &Service{pool: pool, settings: runtime}
+}
+
+// Get returns the leaderboard selected by q as seen by userID.
+func (s *Service) Get(ctx context.Context, userID uuid.UUID, q Query) (*Board, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = s.settings.LeaderboardSize()
+	}
+
+	board := &Board{Period: q.Period, Topic: q.Topic, AgeGroup: q.AgeGroup, Entries: []db.LeaderboardEntry{}}
+	filter := db.LeaderboardFilter{Since: q.Period.Since(time.Now()), Topic: q.Topic, AgeGroup: q.AgeGroup}
+	if !filter.Since.IsZero() {
+		since := filter.Since.Format(time.DateOnly)
+		board.Since = &since
+	}
+
+	entries, total, err := db.ListLeaderboard(ctx, s.pool, filter, userID, limit)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to get leaderboard")
+	}
+	board.Total = total
+	for i := range entries {
+		if i < limit {
+			board.Entries = append(board.Entries, entries[i])
+		}
+		if entries[i].UserID == userID {
+			board.Me = &entries[i]
+		}
+	}
+	return board, nil
+}
+
+// Refresh recomputes the precomputed leaderboard points. It is run
+// periodically by the scheduler and does nothing when another replica is
+// refreshing them.
+func (s *Service) Refresh(ctx context.Context) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		_, err := db.RefreshLeaderboard(ctx, tx)
+		return err
+	})
+}
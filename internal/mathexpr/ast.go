@@ -0,0 +1,274 @@
+package mathexpr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Node is a node of an expression tree.
+type Node interface {
+	// String formats the node unambiguously.
+	String() string
+	eval(env map[string]float64) (float64, error)
+}
+
+// Number is a numeric literal.
+type Number struct {
+	Value float64
+}
+
+// Variable is a named variable such as x.
+type Variable struct {
+	Name string
+}
+
+// Constant is a named mathematical constant such as pi.
+type Constant struct {
+	Name string
+}
+
+// Neg is unary negation.
+type Neg struct {
+	X Node
+}
+
+// Binary is a binary operation; Op is one of + - * / ^.
+type Binary struct {
+	Op          byte
+	Left, Right Node
+}
+
+// Call applies a one-argument function such as sin or sqrt.
+type Call struct {
+	Func string
+	Arg  Node
+}
+
+var functions = map[string]func(float64) float64{
+	"sin":  math.Sin,
+	"cos":  math.Cos,
+	"tan":  math.Tan,
+	"tg":   math.Tan,
+	"cot":  func(x float64) float64 { return 1 / math.Tan(x) },
+	"ctg":  func(x float64) float64 { return 1 / math.Tan(x) },
+	"asin": math.Asin,
+	"acos": math.Acos,
+	"atan": math.Atan,
+	"sinh": math.Sinh,
+	"cosh": math.Cosh,
+	"tanh": math.Tanh,
+	"sqrt": math.Sqrt,
+	"abs":  math.Abs,
+	"exp":  math.Exp,
+	"ln":   math.Log,
+	"lg":   math.Log10,
+	"log":  math.Log10,
+}
+
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// Eval evaluates n with the given variable values.
+func Eval(n Node, env map[string]float64) (float64, error) {
+	return n.eval(env)
+}
+
+// Vars returns the sorted names of the variables used in n.
+func Vars(n Node) []string {
+	set := make(map[string]bool)
+	walk(n, func(n Node) {
+		if v, ok := n.(Variable); ok {
+			set[v.Name] = true
+		}
+	})
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func walk(n Node, fn func(Node)) {
+	fn(n)
+	switch n := n.(type) {
+	case Neg:
+		walk(n.X, fn)
+	case Binary:
+		walk(n.Left, fn)
+		walk(n.Right, fn)
+	case Call:
+		walk(n.Arg, fn)
+	case sum:
+		for _, t := range n {
+			walk(t, fn)
+		}
+	case product:
+		for _, f := range n {
+			walk(f, fn)
+		}
+	case power:
+		walk(n.base, fn)
+		walk(n.exp, fn)
+	}
+}
+
+func (n Number) String() string {
+	return strconv.FormatFloat(n.Value, 'g', -1, 64)
+}
+
+func (n Number) eval(map[string]float64) (float64, error) {
+	return n.Value, nil
+}
+
+func (v Variable) String() string {
+	return v.Name
+}
+
+func (v Variable) eval(env map[string]float64) (float64, error) {
+	x, ok := env[v.Name]
+	if !ok {
+		return 0, fmt.Errorf("no value for variable %q", v.Name)
+	}
+	return x, nil
+}
+
+func (c Constant) String() string {
+	return c.Name
+}
+
+func (c Constant) eval(map[string]float64) (float64, error) {
+	return constants[c.Name], nil
+}
+
+func (n Neg) String() string {
+	return "(-" + n.X.String() + ")"
+}
+
+func (n Neg) eval(env map[string]float64) (float64, error) {
+	x, err := n.X.eval(env)
+	return -x, err
+}
+
+func (b Binary) String() string {
+	return "(" + b.Left.String() + " " + string(b.Op) + " " + b.Right.String() + ")"
+}
+
+func (b Binary) eval(env map[string]float64) (float64, error) {
+	l, err := b.Left.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.Right.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch b.Op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		return l / r, nil
+	case '^':
+		return math.Pow(l, r), nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", b.Op)
+}
+
+func (c Call) String() string {
+	return c.Func + "(" + c.Arg.String() + ")"
+}
+
+func (c Call) eval(env map[string]float64) (float64, error) {
+	x, err := c.Arg.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	fn, ok := functions[c.Func]
+	if !ok {
+		return 0, fmt.Errorf("unknown function %q", c.Func)
+	}
+	return fn(x), nil
+}
+
+// sum, product and power only appear in canonical trees.
+
+type sum []Node
+
+type product []Node
+
+type power struct {
+	base, exp Node
+}
+
+func (s sum) String() string {
+	parts := make([]string, len(s))
+	for i, t := range s {
+		parts[i] = t.String()
+	}
+	return "(" + strings.Join(parts, " + ") + ")"
+}
+
+func (s sum) eval(env map[string]float64) (float64, error) {
+	total := 0.0
+	for _, t := range s {
+		v, err := t.eval(env)
+		if err != nil {
+			return 0, err
+		}
+		total += v
+	}
+	return total, nil
+}
+
+func (p product) String() string {
+	parts := make([]string, len(p))
+	for i, f := range p {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, "*")
+}
+
+func (p product) eval(env map[string]float64) (float64, error) {
+	total := 1.0
+	for _, f := range p {
+		v, err := f.eval(env)
+		if err != nil {
+			return 0, err
+		}
+		total *= v
+	}
+	return total, nil
+}
+
+func (p power) String() string {
+	base := p.base.String()
+	if _, ok := p.base.(product); ok {
+		base = "(" + base + ")"
+	}
+	exp := p.exp.String()
+	if _, ok := p.exp.(product); ok {
+		exp = "(" + exp + ")"
+	}
+	return base + "^" + exp
+}
+
+func (p power) eval(env map[string]float64) (float64, error) {
+	b, err := p.base.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	e, err := p.exp.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(b, e), nil
+}
@@ -0,0 +1,297 @@
+package mathexpr
+
+import (
+	"math"
+	"sort"
+)
+
+// maxExpandedTerms bounds how many terms distributing a product over sums
+// may produce; larger products are left unexpanded.
+const maxExpandedTerms = 64
+
+// maxExpandedPower is the largest integer power of a sum that is expanded.
+const maxExpandedPower = 6
+
+// Canonical returns an equivalent expression in canonical form: numeric
+// constants are folded, sums and products are flattened, products are
+// distributed over sums, like terms and like factors are combined and
+// operands are sorted. Two expressions with the same canonical String are
+// equivalent; the converse does not hold, see Equivalent.
+func Canonical(n Node) Node {
+	switch n := n.(type) {
+	case Neg:
+		return mul(Number{Value: -1}, Canonical(n.X))
+	case Binary:
+		l, r := Canonical(n.Left), Canonical(n.Right)
+		switch n.Op {
+		case '+':
+			return add(l, r)
+		case '-':
+			return add(l, mul(Number{Value: -1}, r))
+		case '*':
+			return mul(l, r)
+		case '/':
+			return mul(l, pow(r, Number{Value: -1}))
+		case '^':
+			return pow(l, r)
+		}
+		return n
+	case Call:
+		arg := Canonical(n.Arg)
+		if x, ok := arg.(Number); ok {
+			if v := functions[n.Func](x.Value); isNice(v) {
+				return Number{Value: v}
+			}
+		}
+		return Call{Func: n.Func, Arg: arg}
+	default:
+		return n
+	}
+}
+
+// isNice reports whether v can be folded into a literal without making
+// the canonical form depend on floating point noise.
+func isNice(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0) && v == math.Round(v*1e6)/1e6
+}
+
+func isNumber(n Node, v float64) bool {
+	x, ok := n.(Number)
+	return ok && x.Value == v
+}
+
+func isInteger(n Node) (int, bool) {
+	x, ok := n.(Number)
+	if !ok || x.Value != math.Trunc(x.Value) || math.Abs(x.Value) > 1<<20 {
+		return 0, false
+	}
+	return int(x.Value), true
+}
+
+func sortNodes(nodes []Node) {
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].String() < nodes[j].String() })
+}
+
+// splitCoefficient splits a term into its numeric coefficient and the rest.
+func splitCoefficient(n Node) (float64, Node) {
+	switch n := n.(type) {
+	case Number:
+		return n.Value, nil
+	case product:
+		if x, ok := n[0].(Number); ok {
+			if len(n) == 2 {
+				return x.Value, n[1]
+			}
+			return x.Value, product(n[1:])
+		}
+	}
+	return 1, n
+}
+
+// add returns the canonical sum of canonical terms.
+func add(terms ...Node) Node {
+	var flat []Node
+	for _, t := range terms {
+		if s, ok := t.(sum); ok {
+			flat = append(flat, s...)
+		} else {
+			flat = append(flat, t)
+		}
+	}
+
+	constant := 0.0
+	coefficients := make(map[string]float64)
+	rests := make(map[string]Node)
+	var keys []string
+	for _, t := range flat {
+		c, rest := splitCoefficient(t)
+		if rest == nil {
+			constant += c
+			continue
+		}
+		key := rest.String()
+		if _, ok := rests[key]; !ok {
+			rests[key] = rest
+			keys = append(keys, key)
+		}
+		coefficients[key] += c
+	}
+
+	var out []Node
+	for _, key := range keys {
+		if c := coefficients[key]; c != 0 {
+			out = append(out, withCoefficient(c, rests[key]))
+		}
+	}
+	sortNodes(out)
+	if constant != 0 {
+		out = append(out, Number{Value: constant})
+	}
+
+	switch len(out) {
+	case 0:
+		return Number{Value: 0}
+	case 1:
+		return out[0]
+	}
+	return sum(out)
+}
+
+// withCoefficient multiplies a canonical non-numeric term by c.
+func withCoefficient(c float64, rest Node) Node {
+	if c == 1 {
+		return rest
+	}
+	if p, ok := rest.(product); ok {
+		return append(product{Number{Value: c}}, p...)
+	}
+	return product{Number{Value: c}, rest}
+}
+
+// splitPower splits a factor into base and exponent.
+func splitPower(n Node) (Node, Node) {
+	if p, ok := n.(power); ok {
+		return p.base, p.exp
+	}
+	return n, Number{Value: 1}
+}
+
+// mul returns the canonical product of canonical factors.
+func mul(factors ...Node) Node {
+	var flat []Node
+	for _, f := range factors {
+		if p, ok := f.(product); ok {
+			flat = append(flat, p...)
+		} else {
+			flat = append(flat, f)
+		}
+	}
+
+	coefficient := 1.0
+	var rest []Node
+	for _, f := range flat {
+		if x, ok := f.(Number); ok {
+			coefficient *= x.Value
+		} else {
+			rest = append(rest, f)
+		}
+	}
+	if coefficient == 0 {
+		return Number{Value: 0}
+	}
+
+	if expanded, ok := distribute(coefficient, rest); ok {
+		return expanded
+	}
+
+	// Combine like factors: x * x^2 = x^3.
+	exponents := make(map[string][]Node)
+	bases := make(map[string]Node)
+	var keys []string
+	for _, f := range rest {
+		base, exp := splitPower(f)
+		key := base.String()
+		if _, ok := bases[key]; !ok {
+			bases[key] = base
+			keys = append(keys, key)
+		}
+		exponents[key] = append(exponents[key], exp)
+	}
+
+	var out []Node
+	for _, key := range keys {
+		f := pow(bases[key], add(exponents[key]...))
+		if x, ok := f.(Number); ok {
+			coefficient *= x.Value
+			continue
+		}
+		out = append(out, f)
+	}
+	sortNodes(out)
+
+	switch {
+	case len(out) == 0:
+		return Number{Value: coefficient}
+	case len(out) == 1 && coefficient == 1:
+		return out[0]
+	case coefficient == 1:
+		return product(out)
+	}
+	return append(product{Number{Value: coefficient}}, out...)
+}
+
+// distribute expands a product containing sums into a sum of products.
+func distribute(coefficient float64, factors []Node) (Node, bool) {
+	var sums []sum
+	var others []Node
+	terms := 1
+	for _, f := range factors {
+		if s, ok := f.(sum); ok {
+			sums = append(sums, s)
+			terms *= len(s)
+		} else {
+			others = append(others, f)
+		}
+	}
+	if len(sums) == 0 || terms > maxExpandedTerms || (len(sums) == 1 && len(others) == 0 && coefficient == 1) {
+		return nil, false
+	}
+
+	partial := []Node{mul(append([]Node{Number{Value: coefficient}}, others...)...)}
+	for _, s := range sums {
+		var next []Node
+		for _, p := range partial {
+			for _, t := range s {
+				next = append(next, mul(p, t))
+			}
+		}
+		partial = next
+	}
+	return add(partial...), true
+}
+
+// pow returns the canonical power of a canonical base and exponent.
+func pow(base, exp Node) Node {
+	switch {
+	case isNumber(exp, 0):
+		return Number{Value: 1}
+	case isNumber(exp, 1):
+		return base
+	case isNumber(base, 1):
+		return Number{Value: 1}
+	}
+
+	if b, ok := base.(Number); ok {
+		if e, ok := exp.(Number); ok {
+			if v := math.Pow(b.Value, e.Value); isNice(v) {
+				return Number{Value: v}
+			}
+		}
+	}
+
+	if k, ok := isInteger(exp); ok {
+		switch b := base.(type) {
+		case power:
+			// (x^a)^k = x^(a*k) holds for integer k.
+			return pow(b.base, mul(b.exp, exp))
+		case product:
+			factors := make([]Node, len(b))
+			for i, f := range b {
+				factors[i] = pow(f, exp)
+			}
+			return mul(factors...)
+		case sum:
+			if k > 1 && k <= maxExpandedPower {
+				factors := make([]Node, k)
+				for i := range factors {
+					factors[i] = b
+				}
+				if expanded, ok := distribute(1, factors); ok {
+					return expanded
+				}
+			}
+		}
+	}
+
+	return power{base: base, exp: exp}
+}
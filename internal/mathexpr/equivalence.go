@@ -0,0 +1,85 @@
+package mathexpr
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+const (
+	// samplePoints is how many points two expressions must agree on.
+	samplePoints = 24
+	// maxSampleTries bounds the attempts to find points where both
+	// expressions are defined.
+	maxSampleTries = 200
+	// minSamplePoints is the fewest defined points accepted as evidence.
+	minSamplePoints = 6
+	// relTolerance is the relative difference accepted between values.
+	relTolerance = 1e-7
+)
+
+// UnknownVariableError is returned by Equivalent when an expression uses a
+// variable that was not declared.
+type UnknownVariableError struct {
+	Name string
+}
+
+func (e *UnknownVariableError) Error() string {
+	return fmt.Sprintf("unknown variable %q", e.Name)
+}
+
+// Equivalent reports whether a and b are mathematically equivalent over the
+// declared variables. Identical canonical forms are equivalent; otherwise
+// both expressions are evaluated at pseudo-random points and must agree
+// wherever both are defined. The points are derived from the expressions,
+// so the result is deterministic.
+func Equivalent(a, b Node, vars []string) (bool, error) {
+	for _, n := range []Node{a, b} {
+		for _, name := range Vars(n) {
+			if !slices.Contains(vars, name) {
+				return false, &UnknownVariableError{Name: name}
+			}
+		}
+	}
+
+	ca, cb := Canonical(a), Canonical(b)
+	if ca.String() == cb.String() {
+		return true, nil
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(ca.String() + "\x00" + cb.String()))
+	rng := rand.New(rand.NewPCG(h.Sum64(), 0x6d617468))
+
+	env := make(map[string]float64, len(vars))
+	agreed := 0
+	for try := 0; try < maxSampleTries && agreed < samplePoints; try++ {
+		for _, name := range vars {
+			// Mix small and large magnitudes of both signs.
+			scale := []float64{1, 3, 10}[try%3]
+			env[name] = (rng.Float64()*2 - 1) * scale
+		}
+		va, errA := ca.eval(env)
+		vb, errB := cb.eval(env)
+		if errA != nil || errB != nil {
+			return false, nil
+		}
+		if !isFinite(va) || !isFinite(vb) {
+			continue
+		}
+		if math.Abs(va-vb) > relTolerance*math.Max(1, math.Max(math.Abs(va), math.Abs(vb))) {
+			return false, nil
+		}
+		agreed++
+		if len(vars) == 0 {
+			return true, nil
+		}
+	}
+	return agreed >= minSamplePoints, nil
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,151 @@
+package mathexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokVariable
+	tokConstant
+	tokFunc
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// ParseError describes invalid input and the rune offset where it was found.
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// operatorAliases maps the typographic operators learners paste from
+// textbooks to their ASCII equivalents.
+var operatorAliases = map[rune]rune{
+	'×': '*',
+	'·': '*',
+	'⋅': '*',
+	'∙': '*',
+	'÷': '/',
+	'−': '-',
+	'–': '-',
+}
+
+// lex splits input into tokens. Runs of letters are split into known
+// function and constant names and single-letter variables, so "xsin(x)"
+// is read as x·sin(x) and "ab" as a·b.
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if alias, ok := operatorAliases[r]; ok {
+			r = alias
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			var sb strings.Builder
+			for i < len(runes) {
+				c := runes[i]
+				// A comma between digits is a decimal separator: 2,5 = 2.5.
+				if c == ',' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]) && !strings.Contains(sb.String(), ".") {
+					c = '.'
+				}
+				if !unicode.IsDigit(c) && c != '.' {
+					break
+				}
+				sb.WriteRune(c)
+				i++
+			}
+			v, err := strconv.ParseFloat(sb.String(), 64)
+			if err != nil {
+				return nil, &ParseError{Pos: start, Msg: fmt.Sprintf("invalid number %q", sb.String())}
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: sb.String(), num: v, pos: start})
+		case r == 'π':
+			tokens = append(tokens, token{kind: tokConstant, text: "pi", pos: i})
+			i++
+		case r == '√':
+			tokens = append(tokens, token{kind: tokFunc, text: "sqrt", pos: i})
+			i++
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) && runes[i] != 'π' {
+				i++
+			}
+			tokens = append(tokens, splitIdentifier(string(runes[start:i]), start)...)
+		case r == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			tokens = append(tokens, token{kind: tokOp, text: "^", pos: i})
+			i += 2
+		case strings.ContainsRune("+-*/^", r):
+			tokens = append(tokens, token{kind: tokOp, text: string(r), pos: i})
+			i++
+		case r == '(' || r == '[':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')' || r == ']':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		default:
+			return nil, &ParseError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", runes[i])}
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(runes)}), nil
+}
+
+// splitIdentifier splits a run of letters greedily into the longest known
+// function or constant names, treating every other letter as a variable.
+func splitIdentifier(ident string, pos int) []token {
+	runes := []rune(ident)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		matched := ""
+		for name := range functions {
+			if len(name) > len(matched) && strings.HasPrefix(string(runes[i:]), name) {
+				matched = name
+			}
+		}
+		for name := range constants {
+			if len(name) > len(matched) && strings.HasPrefix(string(runes[i:]), name) {
+				matched = name
+			}
+		}
+
+		switch {
+		case matched == "":
+			tokens = append(tokens, token{kind: tokVariable, text: string(runes[i]), pos: pos + i})
+			i++
+		case functions[matched] != nil:
+			tokens = append(tokens, token{kind: tokFunc, text: matched, pos: pos + i})
+			i += len([]rune(matched))
+		default:
+			tokens = append(tokens, token{kind: tokConstant, text: matched, pos: pos + i})
+			i += len([]rune(matched))
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,65 @@
+package mathexpr_test
+
+import (
+	"testing"
+
+	"mathtermind-go/internal/mathexpr"
+)
+
+func TestEquivalent(t *testing.T) {
+	tests := []struct {
+		expected string
+		answer   string
+		vars     []string
+		want     bool
+	}{
+		{"2x+2", "2(x+1)", []string{"x"}, true},
+		{"x^2+2x+1", "(x+1)^2", []string{"x"}, true},
+		{"a^2-b^2", "(a-b)(a+b)", []string{"a", "b"}, true},
+		{"sin(x)^2+cos(x)^2", "1", []string{"x"}, true},
+		{"2ln(x)", "ln(x^2)", []string{"x"}, true},
+		{"1/2", "0,5", nil, true},
+		{"x/2", "0.5x", []string{"x"}, true},
+		{"2·π·r", "2πr", []string{"r"}, true},
+		{"sqrt(x^2)", "x", []string{"x"}, false},
+		{"2x+2", "2x+1", []string{"x"}, false},
+		{"x^2", "2x", []string{"x"}, false},
+		{"1/3", "0.33", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected+" = "+tt.answer, func(t *testing.T) {
+			a, err := mathexpr.Parse(tt.expected)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expected, err)
+			}
+			b, err := mathexpr.Parse(tt.answer)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.answer, err)
+			}
+			got, err := mathexpr.Equivalent(a, b, tt.vars)
+			if err != nil {
+				t.Fatalf("Equivalent() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Equivalent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEquivalentUnknownVariable(t *testing.T) {
+	a, _ := mathexpr.Parse("2x")
+	b, _ := mathexpr.Parse("2y")
+	if _, err := mathexpr.Equivalent(a, b, []string{"x"}); err == nil {
+		t.Error("Equivalent() accepted an undeclared variable")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{"", "x+", "(x+1", "2 3", "x$", "*x"} {
+		if _, err := mathexpr.Parse(input); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package mathexpr parses school-level mathematical expressions, brings
+// them to a canonical form and decides whether two expressions are
+// mathematically equivalent.
+//
+// The accepted syntax is the one learners naturally type: implicit
+// multiplication ("2x", "2(x+1)", "x sin x"), ^ or ** for powers, decimal
+// commas, typographic operators (× · ÷ −), π and √, and the usual
+// functions (sin, cos, tg, sqrt, ln, lg, abs, ...).
+package mathexpr
+
+import "fmt"
+
+// Parse parses an expression.
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, &ParseError{Pos: 0, Msg: "empty expression"}
+	}
+	n, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &ParseError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+	return n, nil
+}
+
+// parser is a recursive descent parser over the grammar
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/") unary | power }   (juxtaposition multiplies)
+//	unary   = ("-" | "+") unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number | variable | constant | func ( "(" expr ")" | power ) | "(" expr ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isOp(ops string) bool {
+	tok := p.peek()
+	if tok.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if tok.text == string(op) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseExpr() (Node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+-") {
+		op := p.next().text[0]
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = Binary{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var right Node
+		var op byte
+		switch {
+		case p.isOp("*/"):
+			op = p.next().text[0]
+			right, err = p.parseUnary()
+		case p.startsImplicitFactor():
+			op = '*'
+			right, err = p.parsePower()
+		default:
+			return left, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		left = Binary{Op: op, Left: left, Right: right}
+	}
+}
+
+// startsImplicitFactor reports whether the next token can be multiplied by
+// juxtaposition. Numbers are excluded so that "2 3" is rejected rather than
+// silently read as 6.
+func (p *parser) startsImplicitFactor() bool {
+	switch p.peek().kind {
+	case tokVariable, tokConstant, tokFunc, tokLParen:
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.isOp("-") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Neg{X: x}, nil
+	}
+	if p.isOp("+") {
+		p.next()
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+func (p *parser) parsePower() (Node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("^") {
+		p.next()
+		exp, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Binary{Op: '^', Left: base, Right: exp}, nil
+	}
+	return base, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		return Number{Value: tok.num}, nil
+	case tokVariable:
+		return Variable{Name: tok.text}, nil
+	case tokConstant:
+		return Constant{Name: tok.text}, nil
+	case tokFunc:
+		var arg Node
+		var err error
+		if p.peek().kind == tokLParen {
+			arg, err = p.parsePrimary()
+		} else {
+			arg, err = p.parsePower()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return Call{Func: tok.text, Arg: arg}, nil
+	case tokLParen:
+		n, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &ParseError{Pos: closing.pos, Msg: "missing closing parenthesis"}
+		}
+		return n, nil
+	case tokEOF:
+		return nil, &ParseError{Pos: tok.pos, Msg: "unexpected end of expression"}
+	default:
+		return nil, &ParseError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+}
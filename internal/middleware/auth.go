@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"mathtermind-go/internal/auth"
+	apperrors "mathtermind-go/internal/errors"
+)
+
+type identityKeyType struct{}
+
+var identityKey = identityKeyType{}
+
+// Authenticate requires a valid "Authorization: Bearer <access token>"
+// header and stores the authenticated user in the request context.
+func Authenticate(tokens *auth.TokenManager) func(http.Handler) http.Handler {
+	return authenticate(tokens, false)
+}
+
+// AuthenticateStream is Authenticate that also accepts the access token in
+// the access_token query parameter, for WebSocket and EventSource clients,
+// which cannot set headers.
+func AuthenticateStream(tokens *auth.TokenManager) func(http.Handler) http.Handler {
+	return authenticate(tokens, true)
+}
+
+// OptionalAuthenticate is Authenticate for public routes: requests without
+// an Authorization header are served anonymously, while an invalid token is
+// still rejected.
+func OptionalAuthenticate(tokens *auth.TokenManager) func(http.Handler) http.Handler {
+	authenticated := authenticate(tokens, false)
+	return func(next http.Handler) http.Handler {
+		withIdentity := authenticated(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withIdentity.ServeHTTP(w, r)
+		})
+	}
+}
+
+func authenticate(tokens *auth.TokenManager, allowQuery bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if header == "" && allowQuery {
+				token = r.URL.Query().Get("access_token")
+				ok = true
+			}
+			if !ok || token == "" {
+				apperrors.WriteError(w, apperrors.New(apperrors.ErrCodeAuthError, "Missing bearer token"))
+				return
+			}
+
+			identity, err := tokens.ParseAccessToken(token)
+			if err != nil {
+				apperrors.WriteError(w, apperrors.New(apperrors.ErrCodeTokenError, "Invalid or expired access token"))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// RequirePermission rejects requests whose authenticated user lacks any of
+// the given permissions. It must be installed after Authenticate.
+func RequirePermission(perms ...auth.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := CurrentUser(r.Context())
+			if !ok {
+				apperrors.WriteError(w, apperrors.New(apperrors.ErrCodeAuthError, "Authentication required"))
+				return
+			}
+			for _, p := range perms {
+				if !identity.Can(p) {
+					apperrors.WriteError(w, apperrors.New(apperrors.ErrCodePermissionDenied, "You don't have permission to perform this action").
+						WithDetails(map[string]any{"permission": p, "role": identity.Role}))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithIdentity returns a copy of ctx carrying the authenticated user.
+func WithIdentity(ctx context.Context, identity *auth.Identity) context.Context {
+	return context.WithValue(ctx, identityKey, identity)
+}
+
+// CurrentUser returns the authenticated user stored by Authenticate.
+func CurrentUser(ctx context.Context) (*auth.Identity, bool) {
+	identity, ok := ctx.Value(identityKey).(*auth.Identity)
+	return identity, ok
+}
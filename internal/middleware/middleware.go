@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"mathtermind-go/internal/logger"
@@ -43,5 +44,27 @@ func AddMiddleware(r *chi.Mux) {
 	r.Use(MetricsMiddleware)
 
 	// Add timeouts
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(Timeout(60 * time.Second))
+}
+
+// Timeout cancels the request context after d, like chi's Timeout, except
+// for long-lived WebSocket and event stream requests.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	timeout := middleware.Timeout(d)
+	return func(next http.Handler) http.Handler {
+		timed := timeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isStream(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			timed.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isStream reports whether r opens a WebSocket or an event stream.
+func isStream(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
 }
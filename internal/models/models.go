@@ -24,6 +24,7 @@ type User struct {
 	AvatarURL         *string `json:"avatar_url,omitempty" db:"avatar_url"`
 	ProfileData       JSONB   `json:"profile_data,omitempty" db:"profile_data"`
 	AgeGroup          string  `json:"age_group" db:"age_group"`
+	Role              string  `json:"role" db:"role"`
 	Points            int     `json:"points" db:"points"`
 	ExperienceLevel   int     `json:"experience_level" db:"experience_level"`
 	TotalStudyTimeMin int     `json:"total_study_time_min" db:"total_study_time_min"`
@@ -47,6 +48,17 @@ type UserSetting struct {
 	AccessibilityHighContrast     bool      `json:"accessibility_high_contrast" db:"accessibility_high_contrast"`
 	StudyDailyGoalMin             int       `json:"study_daily_goal_min" db:"study_daily_goal_min"`
 	StudyPreferredSubject         string    `json:"study_preferred_subject" db:"study_preferred_subject"`
+	Timezone                      string    `json:"timezone" db:"timezone"`
+}
+
+// Setting is an application setting. Protected settings cannot be changed
+// through the API.
+type Setting struct {
+	Base
+	Key         string  `json:"key" db:"key"`
+	Value       string  `json:"value" db:"value"`
+	Description *string `json:"description,omitempty" db:"description"`
+	IsProtected bool    `json:"is_protected" db:"is_protected"`
 }
 
 // UserNotification represents a notification sent to a user
@@ -63,10 +75,11 @@ type UserNotification struct {
 // Course represents a learning course
 type Course struct {
 	Base
-	Topic       string `json:"topic" db:"topic"`
-	Name        string `json:"name" db:"name"`
-	Description string `json:"description" db:"description"`
-	DurationMin int    `json:"duration_min" db:"duration_min"`
+	Topic       string     `json:"topic" db:"topic"`
+	Name        string     `json:"name" db:"name"`
+	Description string     `json:"description" db:"description"`
+	DurationMin int        `json:"duration_min" db:"duration_min"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
 
 	// Relationships
 	Lessons []Lesson `json:"lessons,omitempty"`
@@ -257,20 +270,85 @@ type CompletedCourse struct {
 	Course *Course `json:"course,omitempty"`
 }
 
+// Achievement is an award users earn when its rule is met
+type Achievement struct {
+	Base
+	Key         string  `json:"key" db:"key"`
+	Name        string  `json:"name" db:"name"`
+	Description string  `json:"description" db:"description"`
+	Icon        *string `json:"icon,omitempty" db:"icon"`
+	Rule        JSONB   `json:"rule" db:"rule"`
+	Points      int     `json:"points" db:"points"`
+	IsActive    bool    `json:"is_active" db:"is_active"`
+}
+
+// UserAchievement records an achievement earned by a user
+type UserAchievement struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	AchievementID uuid.UUID  `json:"achievement_id" db:"achievement_id"`
+	CourseID      *uuid.UUID `json:"course_id,omitempty" db:"course_id"`
+	AwardedAt     time.Time  `json:"awarded_at" db:"awarded_at"`
+
+	// Relationships
+	Achievement *Achievement `json:"achievement,omitempty"`
+}
+
+// StudySession is a period of study reported by a client through heartbeats
+type StudySession struct {
+	Base
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	ContentID       *uuid.UUID `json:"content_id,omitempty" db:"content_id"`
+	StartedAt       time.Time  `json:"started_at" db:"started_at"`
+	LastHeartbeatAt time.Time  `json:"last_heartbeat_at" db:"last_heartbeat_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	DurationSec     int        `json:"duration_sec" db:"duration_sec"`
+}
+
+// PointsEntry is an entry of the append-only points ledger
+type PointsEntry struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	CourseID  *uuid.UUID `json:"course_id,omitempty" db:"course_id"`
+	Points    int        `json:"points" db:"points"`
+	Reason    string     `json:"reason" db:"reason"`
+	EventKey  string     `json:"event_key" db:"event_key"`
+	SourceID  *uuid.UUID `json:"source_id,omitempty" db:"source_id"`
+	Balance   int        `json:"balance" db:"balance"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
 // UserAnswer records a user's answer to a question
 type UserAnswer struct {
 	Base
-	UserID       uuid.UUID `json:"user_id" db:"user_id"`
-	ContentID    uuid.UUID `json:"content_id" db:"content_id"`
-	QuestionID   string    `json:"question_id" db:"question_id"`
-	AnswerData   JSONB     `json:"answer_data" db:"answer_data"`
-	IsCorrect    bool      `json:"is_correct" db:"is_correct"`
-	PointsEarned int       `json:"points_earned" db:"points_earned"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	ContentID    uuid.UUID  `json:"content_id" db:"content_id"`
+	QuestionID   string     `json:"question_id" db:"question_id"`
+	AnswerData   JSONB      `json:"answer_data" db:"answer_data"`
+	IsCorrect    bool       `json:"is_correct" db:"is_correct"`
+	PointsEarned int        `json:"points_earned" db:"points_earned"`
+	AttemptID    *uuid.UUID `json:"attempt_id,omitempty" db:"attempt_id"`
 
 	// Relationships
 	User    *User    `json:"user,omitempty"`
 	Content *Content `json:"content,omitempty"`
 }
 
+// AssessmentAttempt records a timed attempt at an assessment
+type AssessmentAttempt struct {
+	Base
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	ContentID     uuid.UUID  `json:"content_id" db:"content_id"`
+	AttemptNumber int        `json:"attempt_number" db:"attempt_number"`
+	StartedAt     time.Time  `json:"started_at" db:"started_at"`
+	DeadlineAt    *time.Time `json:"deadline_at,omitempty" db:"deadline_at"`
+	SubmittedAt   *time.Time `json:"submitted_at,omitempty" db:"submitted_at"`
+	Score         *float64   `json:"score,omitempty" db:"score"`
+	Passed        *bool      `json:"passed,omitempty" db:"passed"`
+
+	// Relationships
+	Answers []UserAnswer `json:"answers,omitempty"`
+}
+
 // JSONB is a wrapper around map[string]interface{} for JSONB database fields
 type JSONB map[string]any
@@ -0,0 +1,191 @@
+// Package notifications delivers notifications to users' inboxes, honoring
+// their notification settings, and serves the inbox.
+package notifications
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/pagination"
+	"mathtermind-go/internal/realtime"
+)
+
+// Notification types that users can turn off in their settings. Other
+// types are always delivered.
+const (
+	TypeAchievement = "achievement"
+	TypeReminder    = "reminder"
+)
+
+// Allowed reports whether a user with preferences p wants notifications of
+// the given type.
+func Allowed(p db.NotificationPreferences, notificationType string) bool {
+	switch notificationType {
+	case TypeAchievement:
+		return p.AchievementAlerts
+	case TypeReminder:
+		return p.DailyReminder
+	default:
+		return true
+	}
+}
+
+// ListQuery selects a page of a user's inbox.
+type ListQuery struct {
+	IsRead *bool
+	Type   string
+	// Cursor continues a previous page; empty starts from the newest.
+	Cursor string
+	Limit  int
+}
+
+// Page is a page of a user's inbox, newest first.
+type Page struct {
+	pagination.Page[models.UserNotification]
+	UnreadCount int `json:"unread_count"`
+}
+
+// Service sends notifications and manages users' inboxes.
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+// NewService creates a notifications service.
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Send delivers a notification to the user unless their settings turn
+// notifications of this type off, in which case it returns nil.
+func (s *Service) Send(ctx context.Context, userID uuid.UUID, notificationType, title, message string, relatedID *uuid.UUID) (*models.UserNotification, error) {
+	n, err := s.SendTx(ctx, s.pool, userID, notificationType, title, message, relatedID)
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to send notification")
+	}
+	return n, nil
+}
+
+// SendTx is Send running on q, so the notification can be created in the
+// transaction of the event that caused it. Connected clients are notified
+// once the transaction commits.
+func (s *Service) SendTx(ctx context.Context, q db.Querier, userID uuid.UUID, notificationType, title, message string, relatedID *uuid.UUID) (*models.UserNotification, error) {
+	if notificationType == "" || len(notificationType) > 50 {
+		return nil, apperrors.Errorf(apperrors.ErrCodeValidation, "invalid notification type %q", notificationType)
+	}
+	if title == "" || len(title) > 255 {
+		return nil, apperrors.New(apperrors.ErrCodeValidation, "Notification title must be 1 to 255 characters")
+	}
+
+	prefs, err := db.GetNotificationPreferences(ctx, q, userID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, apperrors.NotFound("user", userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !Allowed(prefs, notificationType) {
+		return nil, nil
+	}
+
+	n := &models.UserNotification{
+		UserID:    userID,
+		Type:      notificationType,
+		Title:     title,
+		Message:   message,
+		RelatedID: relatedID,
+	}
+	if err := db.InsertNotification(ctx, q, n); err != nil {
+		return nil, err
+	}
+	if err := realtime.Publish(ctx, q, userID, realtime.EventNotification, n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// listSort is the order of the inbox, newest first.
+var listSort = pagination.Sort{Field: "created_at", Desc: true}
+
+// List returns a page of the user's inbox.
+func (s *Service) List(ctx context.Context, userID uuid.UUID, lq ListQuery) (*Page, error) {
+	filter := db.NotificationFilter{IsRead: lq.IsRead, Type: lq.Type}
+	if lq.Cursor != "" {
+		var createdAt time.Time
+		cursor, err := pagination.Decode(lq.Cursor, listSort)
+		if err == nil {
+			err = cursor.DecodeKey(&createdAt)
+		}
+		if err != nil {
+			return nil, apperrors.Errorf(apperrors.ErrCodeValidation, "invalid cursor parameter").WithDetails(map[string]any{"cursor": lq.Cursor})
+		}
+		filter.BeforeCreatedAt, filter.BeforeID = &createdAt, cursor.ID
+	}
+
+	// One extra row tells whether there is a next page.
+	items, err := db.ListNotifications(ctx, s.pool, userID, filter, lq.Limit+1)
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to list notifications")
+	}
+	p, err := pagination.NewPage(items, lq.Limit, func(n models.UserNotification) (string, error) {
+		return pagination.Encode(listSort, n.CreatedAt, n.ID)
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to list notifications")
+	}
+	page := &Page{Page: *p}
+
+	if page.UnreadCount, err = db.CountUnreadNotifications(ctx, s.pool, userID); err != nil {
+		return nil, apperrors.WrapDB(err, "failed to count unread notifications")
+	}
+	return page, nil
+}
+
+// UnreadCount returns the number of unread notifications in the inbox.
+func (s *Service) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	n, err := db.CountUnreadNotifications(ctx, s.pool, userID)
+	if err != nil {
+		return 0, apperrors.WrapDB(err, "failed to count unread notifications")
+	}
+	return n, nil
+}
+
+// MarkRead marks one notification read.
+func (s *Service) MarkRead(ctx context.Context, userID, id uuid.UUID) (*models.UserNotification, error) {
+	n, err := db.MarkNotificationRead(ctx, s.pool, userID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, apperrors.NotFound("notification", id)
+	}
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to mark notification read")
+	}
+	return n, nil
+}
+
+// MarkAllRead marks every unread notification read, or only those of
+// notificationType when it is set, and returns how many were marked.
+func (s *Service) MarkAllRead(ctx context.Context, userID uuid.UUID, notificationType string) (int64, error) {
+	n, err := db.MarkAllNotificationsRead(ctx, s.pool, userID, notificationType)
+	if err != nil {
+		return 0, apperrors.WrapDB(err, "failed to mark notifications read")
+	}
+	return n, nil
+}
+
+// Delete deletes a notification from the inbox.
+func (s *Service) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	err := db.DeleteNotification(ctx, s.pool, userID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		return apperrors.NotFound("notification", id)
+	}
+	if err != nil {
+		return apperrors.WrapDB(err, "failed to delete notification")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package notifications_test
+
+import (
+	"testing"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/notifications"
+)
+
+func TestAllowed(t *testing.T) {
+	all := db.NotificationPreferences{DailyReminder: true, AchievementAlerts: true}
+	none := db.NotificationPreferences{}
+
+	tests := []struct {
+		name  string
+		prefs db.NotificationPreferences
+		typ   string
+		want  bool
+	}{
+		{"achievement on", all, notifications.TypeAchievement, true},
+		{"achievement off", none, notifications.TypeAchievement, false},
+		{"reminder on", all, notifications.TypeReminder, true},
+		{"reminder off", db.NotificationPreferences{AchievementAlerts: true}, notifications.TypeReminder, false},
+		{"other types always", none, "system", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notifications.Allowed(tt.prefs, tt.typ); got != tt.want {
+				t.Errorf("Allowed(%+v, %q) = %v, want %v", tt.prefs, tt.typ, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package pagination implements keyset pagination: lists sorted by a field
+// with the row ID breaking ties, sort parameters limited to known fields,
+// and opaque cursors pointing after the last row of a page.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned by Decode for malformed cursors and for
+// cursors taken from a list sorted differently.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Sort orders a list by a field. Rows with equal values are ordered by ID
+// in the same direction.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// String returns the sort in the form accepted by ParseSort.
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// ParseSort parses a sort parameter: a field name, prefixed with "-" for
+// descending order. The empty string is def; fields not in allowed are
+// rejected.
+func ParseSort(v string, allowed []string, def Sort) (Sort, error) {
+	if v == "" {
+		return def, nil
+	}
+	s := Sort{Field: strings.TrimPrefix(v, "-"), Desc: strings.HasPrefix(v, "-")}
+	if !slices.Contains(allowed, s.Field) {
+		return Sort{}, fmt.Errorf("unknown sort field %q", s.Field)
+	}
+	return s, nil
+}
+
+// Cursor is a position in a sorted list: the sort key and ID of the row
+// the next page starts after.
+type Cursor struct {
+	Sort Sort
+	ID   uuid.UUID
+	key  json.RawMessage
+}
+
+// DecodeKey unmarshals the sort key of the cursor into dst.
+func (c *Cursor) DecodeKey(dst any) error {
+	if err := json.Unmarshal(c.key, dst); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+type wireCursor struct {
+	Sort string          `json:"s"`
+	Key  json.RawMessage `json:"k"`
+	ID   uuid.UUID       `json:"i"`
+}
+
+// Encode returns the opaque cursor pointing after the row with the given
+// sort key and ID in a list sorted by s. The key must be JSON-encodable.
+func Encode(s Sort, key any, id uuid.UUID) (string, error) {
+	k, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(wireCursor{Sort: s.String(), Key: k, ID: id})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Decode parses a cursor returned by Encode for a list sorted by s.
+func Decode(cursor string, s Sort) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var w wireCursor
+	if err := json.Unmarshal(raw, &w); err != nil || w.Sort != s.String() || len(w.Key) == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{Sort: s, ID: w.ID, key: w.Key}, nil
+}
+
+// Page is a page of a list with the cursor of the next page.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor is set when HasMore is.
+	NextCursor *string `json:"next_cursor,omitempty"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// NewPage builds a page from the rows fetched for it. Lists fetch limit+1
+// rows: the extra row is dropped and only tells that there is a next page,
+// whose cursor is taken from the last row kept.
+func NewPage[T any](rows []T, limit int, cursor func(T) (string, error)) (*Page[T], error) {
+	page := &Page[T]{Items: rows}
+	if limit > 0 && len(rows) > limit {
+		page.Items, page.HasMore = rows[:limit], true
+		next, err := cursor(page.Items[limit-1])
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = &next
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page, nil
+}
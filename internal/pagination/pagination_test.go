@@ -0,0 +1,122 @@
+package pagination_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/pagination"
+)
+
+func TestParseSort(t *testing.T) {
+	allowed := []string{"name", "created_at"}
+	def := pagination.Sort{Field: "created_at", Desc: true}
+
+	tests := []struct {
+		in      string
+		want    pagination.Sort
+		wantErr bool
+	}{
+		{"", def, false},
+		{"name", pagination.Sort{Field: "name"}, false},
+		{"-name", pagination.Sort{Field: "name", Desc: true}, false},
+		{"created_at", pagination.Sort{Field: "created_at"}, false},
+		{"password_hash", pagination.Sort{}, true},
+		{"-", pagination.Sort{}, true},
+		{"--name", pagination.Sort{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := pagination.ParseSort(tt.in, allowed, def)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSort(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSort(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if !tt.wantErr && tt.in != "" && got.String() != tt.in {
+				t.Errorf("ParseSort(%q).String() = %q", tt.in, got.String())
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	byTime := pagination.Sort{Field: "created_at", Desc: true}
+	createdAt := time.Date(2024, 3, 10, 12, 30, 15, 123456000, time.FixedZone("EET", 2*60*60))
+	id := uuid.New()
+
+	encoded, err := pagination.Encode(byTime, createdAt, id)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	cursor, err := pagination.Decode(encoded, byTime)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	var gotAt time.Time
+	if err := cursor.DecodeKey(&gotAt); err != nil {
+		t.Fatalf("DecodeKey() error = %v", err)
+	}
+	if !gotAt.Equal(createdAt) || cursor.ID != id || cursor.Sort != byTime {
+		t.Errorf("Decode() = (%v, %v, %+v), want (%v, %v, %+v)", gotAt, cursor.ID, cursor.Sort, createdAt, id, byTime)
+	}
+
+	var duration int
+	if err := cursor.DecodeKey(&duration); !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Errorf("DecodeKey() into an int error = %v, want ErrInvalidCursor", err)
+	}
+
+	// A cursor is only valid for the sort it was taken from.
+	for _, s := range []pagination.Sort{{Field: "created_at"}, {Field: "name", Desc: true}} {
+		if _, err := pagination.Decode(encoded, s); !errors.Is(err, pagination.ErrInvalidCursor) {
+			t.Errorf("Decode() with sort %v error = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+
+	for _, bad := range []string{"", "not base64!", "bm90IGpzb24", "e30"} {
+		if _, err := pagination.Decode(bad, byTime); !errors.Is(err, pagination.ErrInvalidCursor) {
+			t.Errorf("Decode(%q) error = %v, want ErrInvalidCursor", bad, err)
+		}
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	cursor := func(n int) (string, error) { return fmt.Sprint(n), nil }
+
+	tests := []struct {
+		name     string
+		rows     []int
+		limit    int
+		want     []int
+		wantNext string
+	}{
+		{"empty", nil, 3, []int{}, ""},
+		{"short", []int{1, 2}, 3, []int{1, 2}, ""},
+		{"full", []int{1, 2, 3}, 3, []int{1, 2, 3}, ""},
+		{"more", []int{1, 2, 3, 4}, 3, []int{1, 2, 3}, "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := pagination.NewPage(tt.rows, tt.limit, cursor)
+			if err != nil {
+				t.Fatalf("NewPage() error = %v", err)
+			}
+			if !slices.Equal(page.Items, tt.want) {
+				t.Errorf("Items = %v, want %v", page.Items, tt.want)
+			}
+			if page.HasMore != (tt.wantNext != "") {
+				t.Errorf("HasMore = %v, want %v", page.HasMore, tt.wantNext != "")
+			}
+			if (page.NextCursor == nil) != (tt.wantNext == "") || (page.NextCursor != nil && *page.NextCursor != tt.wantNext) {
+				t.Errorf("NextCursor = %v, want %q", page.NextCursor, tt.wantNext)
+			}
+		})
+	}
+}
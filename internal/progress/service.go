@@ -0,0 +1,227 @@
+// Package progress maintains a learner's progress through courses: content
+// completion, the per-course progress summary and lesson and course
+// completion records.
+package progress
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/achievements"
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/gamification"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/realtime"
+	"mathtermind-go/internal/repository"
+)
+
+// Update describes the changes caused by completing a content item.
+// LessonCompleted and CourseCompleted are only set when the lesson or
+// course was completed by this update.
+type Update struct {
+	Content         *models.UserContentProgress `json:"content"`
+	Progress        *models.Progress            `json:"progress"`
+	LessonCompleted *models.CompletedLesson     `json:"lesson_completed,omitempty"`
+	CourseCompleted *models.CompletedCourse     `json:"course_completed,omitempty"`
+	// Award is set when completing the lesson earned points.
+	Award *gamification.Award `json:"award,omitempty"`
+	// Achievements lists the achievements earned by this update.
+	Achievements []achievements.Earned `json:"achievements,omitempty"`
+}
+
+// CourseProgress is a user's detailed progress in one course.
+type CourseProgress struct {
+	Progress         *models.Progress             `json:"progress"`
+	Contents         []models.UserContentProgress `json:"contents"`
+	CompletedLessons []models.CompletedLesson     `json:"completed_lessons"`
+	CompletedCourse  *models.CompletedCourse      `json:"completed_course,omitempty"`
+}
+
+// Awards credits points in the transaction carried by the context. It is
+// implemented by *gamification.Service.
+type Awards interface {
+	// LessonCompleted returns the credit for completing a lesson.
+	LessonCompleted(userID uuid.UUID, lesson *models.Lesson) gamification.Credit
+	Credit(ctx context.Context, c gamification.Credit) (*gamification.Award, error)
+}
+
+// Evaluator evaluates events against achievements in the transaction
+// carried by the context. It is implemented by *achievements.Service.
+type Evaluator interface {
+	Evaluate(ctx context.Context, e achievements.Event) ([]achievements.Earned, error)
+}
+
+// Publisher pushes events to a user's connected clients once the
+// transaction carried by the context commits. It is implemented by
+// realtime.TxPublisher.
+type Publisher interface {
+	Publish(ctx context.Context, userID uuid.UUID, eventType string, data any) error
+}
+
+// Service records and reports learner progress.
+type Service struct {
+	repos        *repository.Repositories
+	awards       Awards
+	achievements Evaluator
+	events       Publisher
+}
+
+// NewService creates a progress service that credits lesson completions
+// through awards, evaluates completions against achievements and pushes
+// updates through events.
+func NewService(repos *repository.Repositories, awards Awards, achievements Evaluator, events Publisher) *Service {
+	return &Service{repos: repos, awards: awards, achievements: achievements, events: events}
+}
+
+// Complete marks a content item completed for the user and updates
+// everything derived from it: the course progress row, and the lesson and
+// course completion records once they are due. Completing a lesson credits
+// its points reward, and lesson and course completions are evaluated
+// against achievements. The update is pushed to the user's connected
+// clients when the transaction commits.
+//
+// Complete must run in the transaction carried by ctx (see
+// db.ContextWithTx), which the repositories and collaborators join. The
+// course progress row is locked first, so concurrent completions in the
+// same course are serialized and the derived counters cannot drift.
+// Completing an item again only adds to the time spent.
+func (s *Service) Complete(ctx context.Context, userID uuid.UUID, content *models.Content, timeSpentMin int) (*Update, error) {
+	courseID, err := s.repos.Contents.CourseID(ctx, content.ID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.repos.Progress.LockCourse(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &Update{}
+	if update.Content, err = s.repos.Progress.CompleteContent(ctx, userID, content.ID, timeSpentMin); err != nil {
+		return nil, err
+	}
+
+	update.LessonCompleted, err = s.repos.Progress.CompleteLessonIfDone(ctx, userID, content.LessonID)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+	if update.LessonCompleted != nil {
+		lesson, err := s.repos.Lessons.Get(ctx, content.LessonID)
+		if err != nil {
+			return nil, err
+		}
+		if update.Award, err = s.awards.Credit(ctx, s.awards.LessonCompleted(userID, lesson)); err != nil {
+			return nil, err
+		}
+		if err := s.evaluate(ctx, update, achievements.EventLessonCompleted, userID, courseID); err != nil {
+			return nil, err
+		}
+
+		update.CourseCompleted, err = s.repos.Progress.CompleteCourseIfDone(ctx, userID, courseID)
+		if err != nil && !errors.Is(err, db.ErrNotFound) {
+			return nil, err
+		}
+	}
+	if update.CourseCompleted != nil {
+		if err := s.evaluate(ctx, update, achievements.EventCourseCompleted, userID, courseID); err != nil {
+			return nil, err
+		}
+		if err := s.repos.Progress.SetCourseAchievements(ctx, userID, courseID); err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Progress, err = s.repos.Progress.Refresh(ctx, progress.ID, timeSpentMin); err != nil {
+		return nil, err
+	}
+
+	// Achievements are pushed as events of their own.
+	event := *update
+	event.Achievements = nil
+	if err := s.events.Publish(ctx, userID, realtime.EventProgress, event); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// evaluate evaluates a completion event against achievements and adds the
+// achievements earned to update.
+func (s *Service) evaluate(ctx context.Context, update *Update, t achievements.EventType, userID, courseID uuid.UUID) error {
+	earned, err := s.achievements.Evaluate(ctx, achievements.Event{
+		Type:     t,
+		UserID:   userID,
+		CourseID: &courseID,
+		At:       time.Now(),
+	})
+	update.Achievements = append(update.Achievements, earned...)
+	return err
+}
+
+// CompleteContent marks a theory, interactive or resource item completed.
+// Exercises and assessments are completed by answering them and are
+// rejected with ErrCodeInvalidState.
+func (s *Service) CompleteContent(ctx context.Context, userID, contentID uuid.UUID, timeSpentMin int) (*Update, error) {
+	var update *Update
+	err := s.repos.Tx.WithTx(ctx, func(ctx context.Context) error {
+		content, err := s.repos.Contents.Get(ctx, contentID)
+		if errors.Is(err, db.ErrNotFound) {
+			return apperrors.NotFound("content", contentID)
+		}
+		if err != nil {
+			return err
+		}
+		switch content.ContentType {
+		case models.ContentTypeExercise, models.ContentTypeAssessment:
+			return apperrors.Errorf(apperrors.ErrCodeInvalidState, "%s content is completed by answering it", content.ContentType)
+		}
+
+		update, err = s.Complete(ctx, userID, content, timeSpentMin)
+		return err
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to complete content")
+	}
+	return update, nil
+}
+
+// ListProgress returns the user's progress in every course they started.
+func (s *Service) ListProgress(ctx context.Context, userID uuid.UUID) ([]models.Progress, error) {
+	progress, err := s.repos.Progress.List(ctx, userID)
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to list progress")
+	}
+	return progress, nil
+}
+
+// GetCourseProgress returns the user's detailed progress in a course.
+func (s *Service) GetCourseProgress(ctx context.Context, userID, courseID uuid.UUID) (*CourseProgress, error) {
+	var cp CourseProgress
+	err := s.repos.Tx.WithReadOnlyTx(ctx, func(ctx context.Context) error {
+		var err error
+		cp.Progress, err = s.repos.Progress.Get(ctx, userID, courseID)
+		if errors.Is(err, db.ErrNotFound) {
+			return apperrors.NotFound("progress", courseID)
+		}
+		if err != nil {
+			return err
+		}
+		if cp.Contents, err = s.repos.Progress.ListContents(ctx, userID, courseID); err != nil {
+			return err
+		}
+		if cp.CompletedLessons, err = s.repos.Progress.ListCompletedLessons(ctx, userID, courseID); err != nil {
+			return err
+		}
+		cp.CompletedCourse, err = s.repos.Progress.GetCompletedCourse(ctx, userID, courseID)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to get progress")
+	}
+	return &cp, nil
+}
@@ -0,0 +1,232 @@
+package progress_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/achievements"
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/gamification"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/progress"
+	"mathtermind-go/internal/realtime"
+	"mathtermind-go/internal/repository"
+)
+
+// fakeTx runs units of work without a database transaction.
+type fakeTx struct{}
+
+func (fakeTx) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) WithReadOnlyTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeContents struct {
+	repository.ContentRepository
+
+	courseID uuid.UUID
+	contents map[uuid.UUID]*models.Content
+}
+
+func (f *fakeContents) Get(_ context.Context, id uuid.UUID) (*models.Content, error) {
+	c, ok := f.contents[id]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return c, nil
+}
+
+func (f *fakeContents) CourseID(_ context.Context, id uuid.UUID) (uuid.UUID, error) {
+	if _, ok := f.contents[id]; !ok {
+		return uuid.Nil, db.ErrNotFound
+	}
+	return f.courseID, nil
+}
+
+type fakeLessons struct {
+	repository.LessonRepository
+
+	lesson *models.Lesson
+}
+
+func (f *fakeLessons) Get(_ context.Context, id uuid.UUID) (*models.Lesson, error) {
+	if id != f.lesson.ID {
+		return nil, db.ErrNotFound
+	}
+	return f.lesson, nil
+}
+
+// fakeProgress completes the lesson and the course with their only content
+// item.
+type fakeProgress struct {
+	repository.ProgressRepository
+
+	progress *models.Progress
+	// achievementsSet is set once the course achievements were stored.
+	achievementsSet bool
+}
+
+func (f *fakeProgress) LockCourse(_ context.Context, userID, courseID uuid.UUID) (*models.Progress, error) {
+	if f.progress == nil {
+		f.progress = &models.Progress{Base: models.Base{ID: uuid.New()}, UserID: userID, CourseID: courseID}
+	}
+	return f.progress, nil
+}
+
+func (f *fakeProgress) CompleteContent(_ context.Context, userID, contentID uuid.UUID, timeSpentMin int) (*models.UserContentProgress, error) {
+	return &models.UserContentProgress{UserID: userID, ContentID: contentID, IsCompleted: true, TimeSpentMin: timeSpentMin}, nil
+}
+
+func (f *fakeProgress) CompleteLessonIfDone(_ context.Context, userID, lessonID uuid.UUID) (*models.CompletedLesson, error) {
+	return &models.CompletedLesson{UserID: userID, LessonID: lessonID, CourseID: f.progress.CourseID}, nil
+}
+
+func (f *fakeProgress) CompleteCourseIfDone(_ context.Context, userID, courseID uuid.UUID) (*models.CompletedCourse, error) {
+	return &models.CompletedCourse{UserID: userID, CourseID: courseID, CompletedLessonsCount: 1}, nil
+}
+
+func (f *fakeProgress) SetCourseAchievements(context.Context, uuid.UUID, uuid.UUID) error {
+	f.achievementsSet = true
+	return nil
+}
+
+func (f *fakeProgress) Refresh(_ context.Context, progressID uuid.UUID, timeSpentMin int) (*models.Progress, error) {
+	p := *f.progress
+	p.ProgressPercentage = 100
+	p.TimeSpentMin += timeSpentMin
+	return &p, nil
+}
+
+type fakeAwards struct {
+	credited []gamification.Credit
+}
+
+func (f *fakeAwards) LessonCompleted(userID uuid.UUID, lesson *models.Lesson) gamification.Credit {
+	return gamification.Credit{UserID: userID, CourseID: &lesson.CourseID, Points: lesson.PointsReward, EventKey: "lesson:" + lesson.ID.String()}
+}
+
+func (f *fakeAwards) Credit(_ context.Context, c gamification.Credit) (*gamification.Award, error) {
+	f.credited = append(f.credited, c)
+	return &gamification.Award{Entry: &models.PointsEntry{Points: c.Points}, Level: 1}, nil
+}
+
+type fakeEvaluator struct {
+	events []achievements.EventType
+}
+
+func (f *fakeEvaluator) Evaluate(_ context.Context, e achievements.Event) ([]achievements.Earned, error) {
+	f.events = append(f.events, e.Type)
+	return []achievements.Earned{{Achievement: &models.Achievement{Key: string(e.Type)}}}, nil
+}
+
+type fakePublisher struct {
+	published []any
+}
+
+func (f *fakePublisher) Publish(_ context.Context, _ uuid.UUID, eventType string, data any) error {
+	if eventType != realtime.EventProgress {
+		return nil
+	}
+	f.published = append(f.published, data)
+	return nil
+}
+
+// fixture is a course with one lesson holding a theory and an exercise item.
+type fixture struct {
+	theory, exercise *models.Content
+	lesson           *models.Lesson
+	progress         *fakeProgress
+	awards           *fakeAwards
+	evaluator        *fakeEvaluator
+	events           *fakePublisher
+	service          *progress.Service
+}
+
+func newFixture() *fixture {
+	lesson := &models.Lesson{Base: models.Base{ID: uuid.New()}, CourseID: uuid.New(), PointsReward: 10}
+	f := &fixture{
+		theory:    &models.Content{Base: models.Base{ID: uuid.New()}, LessonID: lesson.ID, ContentType: models.ContentTypeTheory},
+		exercise:  &models.Content{Base: models.Base{ID: uuid.New()}, LessonID: lesson.ID, ContentType: models.ContentTypeExercise},
+		lesson:    lesson,
+		progress:  &fakeProgress{},
+		awards:    &fakeAwards{},
+		evaluator: &fakeEvaluator{},
+		events:    &fakePublisher{},
+	}
+	repos := &repository.Repositories{
+		Tx: fakeTx{},
+		Contents: &fakeContents{courseID: lesson.CourseID, contents: map[uuid.UUID]*models.Content{
+			f.theory.ID: f.theory, f.exercise.ID: f.exercise,
+		}},
+		Lessons:  &fakeLessons{lesson: lesson},
+		Progress: f.progress,
+	}
+	f.service = progress.NewService(repos, f.awards, f.evaluator, f.events)
+	return f
+}
+
+func TestCompleteContent(t *testing.T) {
+	f := newFixture()
+	update, err := f.service.CompleteContent(context.Background(), uuid.New(), f.theory.ID, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if update.Content == nil || !update.Content.IsCompleted || update.Content.TimeSpentMin != 5 {
+		t.Errorf("content progress = %+v, want completed with 5 minutes", update.Content)
+	}
+	if update.LessonCompleted == nil || update.CourseCompleted == nil {
+		t.Errorf("update = %+v, want the lesson and the course completed", update)
+	}
+	if update.Progress == nil || update.Progress.ProgressPercentage != 100 {
+		t.Errorf("progress = %+v, want the refreshed row", update.Progress)
+	}
+	if len(f.awards.credited) != 1 || f.awards.credited[0].Points != 10 || update.Award == nil {
+		t.Errorf("credited %+v with award %+v, want the lesson reward", f.awards.credited, update.Award)
+	}
+	wantEvents := []achievements.EventType{achievements.EventLessonCompleted, achievements.EventCourseCompleted}
+	if len(f.evaluator.events) != 2 || f.evaluator.events[0] != wantEvents[0] || f.evaluator.events[1] != wantEvents[1] {
+		t.Errorf("evaluated %v, want %v", f.evaluator.events, wantEvents)
+	}
+	if len(update.Achievements) != 2 || !f.progress.achievementsSet {
+		t.Errorf("achievements = %+v (stored %v), want both stored", update.Achievements, f.progress.achievementsSet)
+	}
+
+	// Achievements are published as events of their own.
+	if len(f.events.published) != 1 {
+		t.Fatalf("published %d progress events, want 1", len(f.events.published))
+	}
+	if event := f.events.published[0].(progress.Update); event.Achievements != nil || event.Progress != update.Progress {
+		t.Errorf("published %+v, want the update without achievements", event)
+	}
+}
+
+func TestCompleteContentErrors(t *testing.T) {
+	f := newFixture()
+	tests := []struct {
+		name      string
+		contentID uuid.UUID
+		wantCode  apperrors.ErrorCode
+	}{
+		{"missing", uuid.New(), apperrors.ErrCodeNotFound},
+		{"answered", f.exercise.ID, apperrors.ErrCodeInvalidState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.CompleteContent(context.Background(), uuid.New(), tt.contentID, 5)
+			e, ok := apperrors.As(err)
+			if !ok || e.Code != tt.wantCode {
+				t.Fatalf("error = %v, want code %s", err, tt.wantCode)
+			}
+		})
+	}
+	if len(f.events.published) != 0 {
+		t.Errorf("published %d events for rejected completions", len(f.events.published))
+	}
+}
@@ -0,0 +1,127 @@
+package progress
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+)
+
+// StateValue is the value of one piece of resumable content state. Which
+// fields are used depends on the state type and is up to the client.
+type StateValue struct {
+	NumericValue *float64
+	JSONValue    models.JSONB
+	TextValue    *string
+}
+
+// Resume tells a client where the learner stopped: the course, lesson and
+// content item they interacted with last, and the state saved for it.
+type Resume struct {
+	Course          *models.Course              `json:"course"`
+	Lesson          *models.Lesson              `json:"lesson"`
+	Content         *models.Content             `json:"content"`
+	Progress        *models.Progress            `json:"progress,omitempty"`
+	ContentProgress *models.UserContentProgress `json:"content_progress,omitempty"`
+	States          []models.ContentState       `json:"states"`
+}
+
+// SaveState saves a piece of resumable state for a content item and marks
+// the item and its course as the ones the user accessed last.
+func (s *Service) SaveState(ctx context.Context, userID, contentID uuid.UUID, stateType string, value StateValue) (*models.ContentState, error) {
+	var state *models.ContentState
+	err := s.repos.Tx.WithTx(ctx, func(ctx context.Context) error {
+		courseID, err := s.repos.Contents.CourseID(ctx, contentID)
+		if errors.Is(err, db.ErrNotFound) {
+			return apperrors.NotFound("content", contentID)
+		}
+		if err != nil {
+			return err
+		}
+		progress, err := s.repos.Progress.LockCourse(ctx, userID, courseID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.repos.Progress.LockContent(ctx, userID, contentID); err != nil {
+			return err
+		}
+
+		state, err = s.repos.Progress.SaveState(ctx, models.ContentState{
+			UserID:       userID,
+			ProgressID:   progress.ID,
+			ContentID:    contentID,
+			StateType:    stateType,
+			NumericValue: value.NumericValue,
+			JSONValue:    value.JSONValue,
+			TextValue:    value.TextValue,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to save content state")
+	}
+	return state, nil
+}
+
+// ListStates returns all state the user saved for a content item.
+func (s *Service) ListStates(ctx context.Context, userID, contentID uuid.UUID) ([]models.ContentState, error) {
+	states, err := s.repos.Progress.ListStates(ctx, userID, contentID)
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to list content states")
+	}
+	return states, nil
+}
+
+// DeleteState removes one piece of saved state.
+func (s *Service) DeleteState(ctx context.Context, userID, contentID uuid.UUID, stateType string) error {
+	err := s.repos.Progress.DeleteState(ctx, userID, contentID, stateType)
+	if errors.Is(err, db.ErrNotFound) {
+		return apperrors.NotFound("content state", stateType)
+	}
+	if err != nil {
+		return apperrors.WrapDB(err, "failed to delete content state")
+	}
+	return nil
+}
+
+// Resume returns where the user stopped learning. It fails with
+// ErrCodeNotFound when the user has not interacted with any content yet.
+func (s *Service) Resume(ctx context.Context, userID uuid.UUID) (*Resume, error) {
+	var res Resume
+	err := s.repos.Tx.WithReadOnlyTx(ctx, func(ctx context.Context) error {
+		last, err := s.repos.Progress.Last(ctx, userID)
+		if errors.Is(err, db.ErrNotFound) {
+			return apperrors.New(apperrors.ErrCodeNotFound, "Nothing to resume yet")
+		}
+		if err != nil {
+			return err
+		}
+		res.ContentProgress = last
+
+		if res.Content, err = s.repos.Contents.Get(ctx, last.ContentID); err != nil {
+			return err
+		}
+		if res.Lesson, err = s.repos.Lessons.Get(ctx, res.Content.LessonID); err != nil {
+			return err
+		}
+		if res.Course, err = s.repos.Courses.GetSummary(ctx, res.Lesson.CourseID); err != nil {
+			return err
+		}
+
+		res.Progress, err = s.repos.Progress.Get(ctx, userID, res.Course.ID)
+		if err != nil && !errors.Is(err, db.ErrNotFound) {
+			return err
+		}
+
+		res.States, err = s.repos.Progress.ListStates(ctx, userID, last.ContentID)
+		return err
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to resume")
+	}
+	return &res, nil
+}
@@ -0,0 +1,97 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+)
+
+// Channel is the PostgreSQL notification channel events are published on.
+const Channel = "realtime_events"
+
+// maxPayload keeps published events under PostgreSQL's 8000 byte limit on
+// notification payloads.
+const maxPayload = 7900
+
+// Event types pushed to clients.
+const (
+	EventNotification = "notification"
+	EventAchievement  = "achievement"
+	EventProgress     = "progress"
+)
+
+// Event is a message pushed to a user's connected clients.
+type Event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+	// Truncated is set when the data was too large to publish; clients
+	// should fetch the current state instead.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// envelope is an event addressed to a user, as published on Channel.
+type envelope struct {
+	UserID uuid.UUID `json:"user_id"`
+	Event
+}
+
+// Encode returns the notification payload publishing an event with data to
+// the user. Data too large for a payload is left out and the event is
+// marked truncated.
+func Encode(userID uuid.UUID, eventType string, data any) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s event: %w", eventType, err)
+	}
+	env := envelope{UserID: userID, Event: Event{Type: eventType, Data: raw}}
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s event: %w", eventType, err)
+	}
+	if len(payload) > maxPayload {
+		env.Data, env.Truncated = nil, true
+		return json.Marshal(env)
+	}
+	return payload, nil
+}
+
+// Decode parses a payload returned by Encode.
+func Decode(payload []byte) (uuid.UUID, Event, error) {
+	var env envelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return uuid.Nil, Event{}, fmt.Errorf("decode event: %w", err)
+	}
+	if env.UserID == uuid.Nil || env.Type == "" {
+		return uuid.Nil, Event{}, fmt.Errorf("decode event: missing user or type")
+	}
+	return env.UserID, env.Event, nil
+}
+
+// Publish publishes an event to the user's clients on every replica. Run on
+// a transaction, the event is only delivered if the transaction commits.
+func Publish(ctx context.Context, q db.Querier, userID uuid.UUID, eventType string, data any) error {
+	payload, err := Encode(userID, eventType, data)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx, "SELECT pg_notify($1, $2)", Channel, string(payload))
+	return err
+}
+
+// TxPublisher publishes events in the transaction carried by the context
+// (see db.ContextWithTx), so they are only delivered if it commits.
+type TxPublisher struct{}
+
+// Publish is Publish on the transaction carried by ctx. It returns
+// db.ErrNoTx outside a transaction.
+func (TxPublisher) Publish(ctx context.Context, userID uuid.UUID, eventType string, data any) error {
+	tx, err := db.RequireTx(ctx)
+	if err != nil {
+		return err
+	}
+	return Publish(ctx, tx, userID, eventType, data)
+}
@@ -0,0 +1,135 @@
+// Package realtime pushes per-user events to connected clients over
+// WebSocket or Server-Sent Events. Events are published with PostgreSQL
+// NOTIFY, so an event published on one replica reaches clients connected
+// to any replica.
+package realtime
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+)
+
+// ErrClosed is returned by Subscribe once the hub is closed.
+var ErrClosed = errors.New("realtime hub closed")
+
+// subscriptionBuffer is the number of events a client may fall behind
+// before it is disconnected.
+const subscriptionBuffer = 32
+
+// Subscription receives the events of one user.
+type Subscription struct {
+	userID uuid.UUID
+	events chan Event
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription ends: when the hub closes or the client falls too far behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Hub listens for published events and fans them out to the subscriptions
+// of their users.
+type Hub struct {
+	pool *pgxpool.Pool
+
+	mu     sync.Mutex
+	subs   map[uuid.UUID]map[*Subscription]struct{}
+	closed bool
+}
+
+// NewHub creates a hub listening on the pool's database.
+func NewHub(pool *pgxpool.Pool) *Hub {
+	return &Hub{pool: pool, subs: make(map[uuid.UUID]map[*Subscription]struct{})}
+}
+
+// Subscribe starts delivering the user's events.
+func (h *Hub) Subscribe(userID uuid.UUID) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+	sub := &Subscription{userID: userID, events: make(chan Event, subscriptionBuffer)}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe stops delivering events to sub. It is safe to call more than
+// once.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+// remove ends sub; h.mu must be held.
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subs[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.userID)
+	}
+	close(sub.events)
+}
+
+// dispatch delivers an event to the user's subscriptions, ending those
+// whose buffer is full.
+func (h *Hub) dispatch(userID uuid.UUID, e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[userID] {
+		select {
+		case sub.events <- e:
+		default:
+			slog.Warn("Dropping slow realtime client", "user_id", userID)
+			h.remove(sub)
+		}
+	}
+}
+
+// Close ends every subscription and rejects new ones. Connected clients are
+// disconnected, which lets the HTTP server shut down.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, subs := range h.subs {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// Run listens for published events until ctx is done, reconnecting after
+// connection failures. Events published while reconnecting are lost;
+// clients fetch the current state when they reconnect anyway.
+func (h *Hub) Run(ctx context.Context) {
+	db.Listen(ctx, h.pool, db.Listener{Channel: Channel, OnNotify: h.handle})
+}
+
+// handle dispatches a published event.
+func (h *Hub) handle(_ context.Context, payload string) {
+	userID, e, err := Decode([]byte(payload))
+	if err != nil {
+		slog.Warn("Ignoring malformed realtime event", "error", err)
+		return
+	}
+	h.dispatch(userID, e)
+}
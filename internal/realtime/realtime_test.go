@@ -0,0 +1,75 @@
+package realtime_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/realtime"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	userID := uuid.New()
+	data := map[string]any{"title": "Achievement unlocked"}
+
+	payload, err := realtime.Encode(userID, realtime.EventNotification, data)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	gotUser, e, err := realtime.Decode(payload)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if gotUser != userID || e.Type != realtime.EventNotification || e.Truncated {
+		t.Errorf("Decode() = (%v, %+v), want user %v and an untruncated %s event", gotUser, e, userID, realtime.EventNotification)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(e.Data, &got); err != nil || got["title"] != data["title"] {
+		t.Errorf("Decode() data = %s, want %v", e.Data, data)
+	}
+}
+
+func TestEncodeTruncatesLargeData(t *testing.T) {
+	userID := uuid.New()
+	payload, err := realtime.Encode(userID, realtime.EventProgress, strings.Repeat("x", 10000))
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if len(payload) >= 8000 {
+		t.Errorf("Encode() payload is %d bytes, want under 8000", len(payload))
+	}
+	_, e, err := realtime.Decode(payload)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !e.Truncated || e.Data != nil {
+		t.Errorf("Decode() = %+v, want truncated event without data", e)
+	}
+}
+
+func TestDecodeRejectsMalformed(t *testing.T) {
+	for _, payload := range []string{"", "{", `{"type":"progress"}`, `{"user_id":"` + uuid.NewString() + `"}`} {
+		if _, _, err := realtime.Decode([]byte(payload)); err == nil {
+			t.Errorf("Decode(%q) succeeded, want error", payload)
+		}
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	hub := realtime.NewHub(nil)
+	sub, err := hub.Subscribe(uuid.New())
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	hub.Close()
+	if _, ok := <-sub.Events(); ok {
+		t.Error("Events() still open after Close")
+	}
+	hub.Unsubscribe(sub)
+	if _, err := hub.Subscribe(uuid.New()); err != realtime.ErrClosed {
+		t.Errorf("Subscribe() after Close error = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,137 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/google/uuid"
+)
+
+const (
+	// keepAliveInterval is how often idle connections are pinged, keeping
+	// proxies from closing them.
+	keepAliveInterval = 25 * time.Second
+	// writeTimeout bounds writing one message to a client.
+	writeTimeout = 10 * time.Second
+)
+
+// IsWebSocket reports whether r asks to upgrade to a WebSocket.
+func IsWebSocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// Serve streams the user's events to the client over a WebSocket when the
+// request asks for an upgrade and as Server-Sent Events otherwise. It
+// returns when the client disconnects or the hub closes.
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, userID uuid.UUID) error {
+	sub, err := h.Subscribe(userID)
+	if err != nil {
+		return err
+	}
+	defer h.Unsubscribe(sub)
+
+	// The connection outlives the server's read and write timeouts.
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	if IsWebSocket(r) {
+		return serveWebSocket(w, r, sub)
+	}
+	return serveSSE(w, r, rc, sub)
+}
+
+func serveWebSocket(w http.ResponseWriter, r *http.Request, sub *Subscription) error {
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		// Clients authenticate with a token rather than cookies, so
+		// cross-origin connections carry no ambient credentials.
+		OriginPatterns: []string{"*"},
+	})
+	if err != nil {
+		// Accept has already written the error response.
+		slog.Debug("WebSocket upgrade failed", "error", err)
+		return nil
+	}
+	defer c.CloseNow()
+
+	// Clients only listen; CloseRead handles their control frames and
+	// cancels ctx when they go away.
+	ctx := c.CloseRead(r.Context())
+	ping := time.NewTicker(keepAliveInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ping.C:
+			if err := withTimeout(ctx, c.Ping); err != nil {
+				return nil
+			}
+		case e, ok := <-sub.Events():
+			if !ok {
+				c.Close(websocket.StatusGoingAway, "server closing connection")
+				return nil
+			}
+			err := withTimeout(ctx, func(ctx context.Context) error { return wsjson.Write(ctx, c, e) })
+			if err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+func serveSSE(w http.ResponseWriter, r *http.Request, rc *http.ResponseController, sub *Subscription) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if err := rc.Flush(); err != nil {
+		return nil
+	}
+
+	ping := time.NewTicker(keepAliveInterval)
+	defer ping.Stop()
+
+	for {
+		var msg string
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-ping.C:
+			msg = ": ping\n\n"
+		case e, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				return nil
+			}
+			msg = fmt.Sprintf("event: %s\ndata: %s\n\n", e.Type, data)
+		}
+
+		rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := fmt.Fprint(w, msg); err != nil {
+			return nil
+		}
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
+
+// withTimeout runs fn with a context bounded by writeTimeout.
+func withTimeout(ctx context.Context, fn func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+	return fn(ctx)
+}
@@ -0,0 +1,91 @@
+// Package reminders sends daily study reminders to learners who have not
+// met their daily goal by their chosen reminder time.
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/notifications"
+)
+
+// Window is how long after a user's reminder time the reminder is still
+// sent, so reminders missed while no replica was running go out late
+// rather than not at all.
+const Window = time.Hour
+
+// batchSize is the number of due reminders loaded at once.
+const batchSize = 500
+
+// Service sends daily study reminders.
+type Service struct {
+	pool          *pgxpool.Pool
+	notifications *notifications.Service
+}
+
+// NewService creates a reminders service delivering reminders through
+// notifier.
+func NewService(pool *pgxpool.Pool, notifier *notifications.Service) *Service {
+	return &Service{pool: pool, notifications: notifier}
+}
+
+// SendDue sends a reminder notification to every user whose reminder is due
+// and returns the number sent. Each user is reminded at most once per day
+// in their timezone.
+func (s *Service) SendDue(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		due, err := db.ListDueReminders(ctx, s.pool, Window, batchSize)
+		if err != nil {
+			return sent, fmt.Errorf("list due reminders: %w", err)
+		}
+		for _, r := range due {
+			ok, err := s.send(ctx, r)
+			if err != nil {
+				return sent, fmt.Errorf("remind user %s: %w", r.UserID, err)
+			}
+			if ok {
+				sent++
+			}
+		}
+		// Every user listed is now recorded as reminded, so a full batch
+		// means there may be more.
+		if len(due) < batchSize {
+			return sent, nil
+		}
+	}
+}
+
+// send records and sends one reminder. It reports false when another round
+// already sent it.
+func (s *Service) send(ctx context.Context, r db.DueReminder) (bool, error) {
+	sent := false
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		err := db.RecordStudyReminder(ctx, tx, r.UserID, r.LocalDate)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		n, err := s.notifications.SendTx(ctx, tx, r.UserID, notifications.TypeReminder, "Time to study", Message(r.StudiedMin, r.GoalMin), nil)
+		sent = n != nil
+		return err
+	})
+	return sent, err
+}
+
+// Message returns the text of a reminder to a user who studied studiedMin
+// of their goalMin minute daily goal today.
+func Message(studiedMin, goalMin int) string {
+	if studiedMin <= 0 {
+		return fmt.Sprintf("You haven't studied yet today. A %d minute session reaches your daily goal.", goalMin)
+	}
+	return fmt.Sprintf("You've studied %d of %d minutes today. %d more minutes reach your daily goal.", studiedMin, goalMin, goalMin-studiedMin)
+}
@@ -0,0 +1,27 @@
+package reminders_test
+
+import (
+	"testing"
+
+	"mathtermind-go/internal/reminders"
+)
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		studiedMin int
+		goalMin    int
+		want       string
+	}{
+		{"not started", 0, 30, "You haven't studied yet today. A 30 minute session reaches your daily goal."},
+		{"part way", 10, 30, "You've studied 10 of 30 minutes today. 20 more minutes reach your daily goal."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reminders.Message(tt.studiedMin, tt.goalMin); got != tt.want {
+				t.Errorf("Message(%d, %d) = %q, want %q", tt.studiedMin, tt.goalMin, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package render
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache keeps the most recently used rendered documents by hash.
+type Cache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // of *Document, most recently used first
+	items map[string]*list.Element
+}
+
+// NewCache creates a cache of at most size documents.
+func NewCache(size int) *Cache {
+	return &Cache{size: size, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// Get returns the document with the hash, if it is cached.
+func (c *Cache) Get(hash string) (*Document, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[hash]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*Document), true
+}
+
+// Put caches a document, evicting the least recently used one when the
+// cache is full.
+func (c *Cache) Put(doc *Document) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[doc.Hash]; ok {
+		e.Value = doc
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[doc.Hash] = c.order.PushFront(doc)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*Document).Hash)
+	}
+}
+
+// Len returns the number of cached documents.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
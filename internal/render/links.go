@@ -0,0 +1,108 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+
+	"mathtermind-go/internal/models"
+)
+
+// LessonScheme is the URL scheme of links to lessons of the same course:
+// lesson:<id> or lesson:<order>, optionally followed by a #fragment.
+const LessonScheme = "lesson:"
+
+// Links maps the lesson references a text may link to, lesson IDs and
+// orders, to the URLs of the lessons.
+type Links map[string]string
+
+// LessonURL is the path of a lesson in the app.
+func LessonURL(courseID, lessonID uuid.UUID) string {
+	return fmt.Sprintf("/courses/%s/lessons/%s", courseID, lessonID)
+}
+
+// CourseLinks returns the links to the lessons of a course.
+func CourseLinks(courseID uuid.UUID, lessons []models.Lesson) Links {
+	links := make(Links, 2*len(lessons))
+	for _, l := range lessons {
+		url := LessonURL(courseID, l.ID)
+		links[l.ID.String()] = url
+		links[strconv.Itoa(l.LessonOrder)] = url
+	}
+	return links
+}
+
+// resolve returns the URL of a lesson reference.
+func (l Links) resolve(ref string) (string, bool) {
+	ref, fragment, _ := strings.Cut(ref, "#")
+	url, ok := l[strings.ToLower(ref)]
+	if !ok {
+		return "", false
+	}
+	if fragment != "" {
+		url += "#" + fragment
+	}
+	return url, true
+}
+
+// writeTo writes the links in a stable order, for hashing.
+func (l Links) writeTo(buf *bytes.Buffer) {
+	refs := make([]string, 0, len(l))
+	for ref := range l {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		buf.WriteString(ref)
+		buf.WriteByte('=')
+		buf.WriteString(l[ref])
+		buf.WriteByte('\n')
+	}
+}
+
+var (
+	linksKey       = parser.NewContextKey()
+	brokenLinksKey = parser.NewContextKey()
+)
+
+// lessonLinkTransformer resolves lesson: links with the Links in the parser
+// context. Links to unknown lessons are replaced by their text and
+// recorded in the context.
+type lessonLinkTransformer struct{}
+
+func (lessonLinkTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	links, _ := pc.Get(linksKey).(Links)
+	var (
+		lessonLinks []*ast.Link
+		broken      []string
+	)
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*ast.Link); ok && entering && bytes.HasPrefix(link.Destination, []byte(LessonScheme)) {
+			lessonLinks = append(lessonLinks, link)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, link := range lessonLinks {
+		ref := string(link.Destination[len(LessonScheme):])
+		if url, ok := links.resolve(ref); ok {
+			link.Destination = []byte(url)
+			link.SetAttributeString("class", []byte("lesson-link"))
+			continue
+		}
+		broken = append(broken, string(link.Destination))
+		parent := link.Parent()
+		for c := link.FirstChild(); c != nil; c = link.FirstChild() {
+			parent.InsertBefore(parent, link, c)
+		}
+		parent.RemoveChild(parent, link)
+	}
+	pc.Set(brokenLinksKey, broken)
+}
@@ -0,0 +1,213 @@
+package render
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Node kinds of math.
+var (
+	kindMathInline = ast.NewNodeKind("MathInline")
+	kindMathBlock  = ast.NewNodeKind("MathBlock")
+)
+
+// mathInline is TeX between $ or $$ delimiters within a paragraph.
+type mathInline struct {
+	ast.BaseInline
+
+	// Display is set for $$ delimiters.
+	Display bool
+	// Segment is the TeX source without the delimiters.
+	Segment text.Segment
+}
+
+func (n *mathInline) Kind() ast.NodeKind { return kindMathInline }
+
+func (n *mathInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"TeX": string(n.Segment.Value(source))}, nil)
+}
+
+// mathBlock is TeX between lines starting and ending with $$.
+type mathBlock struct {
+	ast.BaseBlock
+
+	// closed is set when the block ended on its opening line.
+	closed bool
+}
+
+func (n *mathBlock) Kind() ast.NodeKind { return kindMathBlock }
+
+func (n *mathBlock) IsRaw() bool { return true }
+
+func (n *mathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// tex returns the TeX source of the block.
+func (n *mathBlock) tex(source []byte) []byte {
+	var buf bytes.Buffer
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		buf.Write(seg.Value(source))
+	}
+	return bytes.TrimSpace(buf.Bytes())
+}
+
+var mathDelim = []byte("$$")
+
+// mathBlockParser parses display math blocks:
+//
+//	$$
+//	\sum_{i=1}^n i = \frac{n(n+1)}{2}
+//	$$
+//
+// The TeX may also start on the opening line and end on the closing one,
+// or the whole block may be on one line.
+type mathBlockParser struct{}
+
+func (mathBlockParser) Trigger() []byte { return []byte{'$'} }
+
+func (mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], mathDelim) {
+		return nil, parser.NoChildren
+	}
+	start := pos + len(mathDelim)
+	rest := util.TrimRightSpace(line[start:])
+	node := &mathBlock{}
+	switch i := bytes.Index(rest, mathDelim); {
+	case i < 0:
+		node.Lines().Append(text.NewSegment(segment.Start+start, segment.Stop))
+	case i == len(rest)-len(mathDelim):
+		node.Lines().Append(text.NewSegment(segment.Start+start, segment.Start+start+i))
+		node.closed = true
+	default:
+		// Text after the closing delimiter: $$...$$ within a paragraph.
+		return nil, parser.NoChildren
+	}
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+func (mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	if node.(*mathBlock).closed {
+		return parser.Close
+	}
+	line, segment := reader.PeekLine()
+	if trimmed := util.TrimRightSpace(line); bytes.HasSuffix(trimmed, mathDelim) {
+		node.Lines().Append(text.NewSegment(segment.Start, segment.Start+len(trimmed)-len(mathDelim)))
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return parser.Continue | parser.NoChildren
+}
+
+func (mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (mathBlockParser) CanInterruptParagraph() bool { return true }
+
+func (mathBlockParser) CanAcceptIndentedLine() bool { return false }
+
+// inlineMathParser parses $...$ and $$...$$ within a line. Like pandoc, it
+// requires the opening $ to be followed by a non-space and the closing $
+// to follow a non-space and not be followed by a digit, so that amounts
+// such as "$5 and $10" stay text. A backslash escapes a $ within the TeX,
+// and math cannot extend into a code span.
+type inlineMathParser struct{}
+
+func (inlineMathParser) Trigger() []byte { return []byte{'$'} }
+
+func (inlineMathParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	delim := 1
+	if bytes.HasPrefix(line, mathDelim) {
+		delim = 2
+	}
+	body := line[delim:]
+	if len(body) == 0 || util.IsSpace(body[0]) || body[0] == '$' {
+		return nil
+	}
+	for i := 1; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '`':
+			return nil
+		case '$':
+			if util.IsSpace(body[i-1]) {
+				continue
+			}
+			if delim == 2 {
+				if i+1 >= len(body) || body[i+1] != '$' {
+					continue
+				}
+			} else if i+1 < len(body) && util.IsNumeric(body[i+1]) {
+				continue
+			}
+			node := &mathInline{
+				Display: delim == 2,
+				Segment: text.NewSegment(segment.Start+delim, segment.Start+delim+i),
+			}
+			block.Advance(delim + i + delim)
+			return node
+		}
+	}
+	return nil
+}
+
+// mathRenderer writes math as escaped TeX in the delimiters MathJax and
+// KaTeX's auto-render look for, within elements with the math class.
+type mathRenderer struct{}
+
+func (r mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindMathInline, r.renderInline)
+	reg.Register(kindMathBlock, r.renderBlock)
+}
+
+func (mathRenderer) renderInline(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	m := n.(*mathInline)
+	if m.Display {
+		_, _ = w.WriteString(`<span class="math display">\[`)
+		_, _ = w.Write(util.EscapeHTML(m.Segment.Value(source)))
+		_, _ = w.WriteString(`\]</span>`)
+	} else {
+		_, _ = w.WriteString(`<span class="math inline">\(`)
+		_, _ = w.Write(util.EscapeHTML(m.Segment.Value(source)))
+		_, _ = w.WriteString(`\)</span>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (mathRenderer) renderBlock(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`<div class="math display">\[`)
+	_, _ = w.Write(util.EscapeHTML(n.(*mathBlock).tex(source)))
+	_, _ = w.WriteString("\\]</div>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+// mathExtension adds inline and display math to Markdown.
+type mathExtension struct{}
+
+func (mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(mathBlockParser{}, 701)),
+		parser.WithInlineParsers(util.Prioritized(inlineMathParser{}, 501)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(mathRenderer{}, 500)))
+}
@@ -0,0 +1,119 @@
+// Package render renders the Markdown of theory content to HTML.
+//
+// Texts may contain inline math between $ or $$ and display math blocks
+// between $$ lines; the TeX is passed through, escaped, for MathJax or
+// KaTeX to typeset in the browser. Links with the lesson: scheme point to
+// other lessons of the course. The HTML is sanitized, and rendered
+// documents are cached by the hash of what they are rendered from.
+package render
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Version identifies the rendering rules. It is part of the hash of every
+// document, so changing the rules must change it.
+const Version = "1"
+
+// DefaultCacheSize is the number of documents a renderer caches by default.
+const DefaultCacheSize = 1024
+
+// Document is a rendered text. Documents are shared through the cache and
+// must not be modified.
+type Document struct {
+	// Hash identifies the text, the links and the rendering rules the
+	// document was rendered from.
+	Hash string    `json:"hash"`
+	HTML string    `json:"html"`
+	TOC  []Heading `json:"toc"`
+	// BrokenLinks are the lesson: links to lessons not in the course;
+	// they are rendered as plain text.
+	BrokenLinks []string `json:"broken_links,omitempty"`
+}
+
+// Renderer renders Markdown texts to sanitized HTML. It is safe for
+// concurrent use.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+	cache  *Cache
+}
+
+// NewRenderer creates a renderer that caches up to cacheSize documents.
+func NewRenderer(cacheSize int) *Renderer {
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(extension.GFM, mathExtension{}),
+			goldmark.WithParserOptions(
+				parser.WithAutoHeadingID(),
+				parser.WithASTTransformers(util.Prioritized(lessonLinkTransformer{}, 100)),
+			),
+			// Raw HTML is let through to the sanitizer.
+			goldmark.WithRendererOptions(html.WithUnsafe()),
+		),
+		policy: newPolicy(),
+		cache:  NewCache(cacheSize),
+	}
+}
+
+// newPolicy allows user generated content plus the markup the renderer
+// produces: math elements, lesson links, heading IDs and task lists.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^math (inline|display)$`)).OnElements("span", "div")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^lesson-link$`)).OnElements("a")
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// Hash returns the cache key of a text rendered with links.
+func Hash(source string, links Links) string {
+	var buf bytes.Buffer
+	buf.WriteString(Version)
+	buf.WriteByte(0)
+	buf.WriteString(source)
+	buf.WriteByte(0)
+	links.writeTo(&buf)
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// Render renders a Markdown text, resolving lesson: links with links.
+func (r *Renderer) Render(source string, links Links) (*Document, error) {
+	hash := Hash(source, links)
+	if doc, ok := r.cache.Get(hash); ok {
+		return doc, nil
+	}
+
+	src := []byte(source)
+	pc := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	pc.Set(linksKey, links)
+	tree := r.md.Parser().Parse(text.NewReader(src), parser.WithContext(pc))
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, src, tree); err != nil {
+		return nil, err
+	}
+	broken, _ := pc.Get(brokenLinksKey).([]string)
+	doc := &Document{
+		Hash:        hash,
+		HTML:        string(r.policy.SanitizeBytes(buf.Bytes())),
+		TOC:         tableOfContents(tree, src),
+		BrokenLinks: broken,
+	}
+	r.cache.Put(doc)
+	return doc, nil
+}
@@ -0,0 +1,134 @@
+package render_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/render"
+)
+
+var (
+	courseID = uuid.MustParse("6f1c2a4e-0000-4000-8000-000000000001")
+	lessonID = uuid.MustParse("6f1c2a4e-0000-4000-8000-000000000002")
+	links    = render.CourseLinks(courseID, []models.Lesson{{Base: models.Base{ID: lessonID}, LessonOrder: 2}})
+)
+
+func TestRender(t *testing.T) {
+	lessonURL := render.LessonURL(courseID, lessonID)
+	tests := []struct {
+		name   string
+		source string
+		want   string
+		broken []string
+	}{
+		{
+			name:   "inline math",
+			source: `Let $x_1 < y$ and $$E=mc^2$$.`,
+			want:   `<p>Let <span class="math inline">\(x_1 &lt; y\)</span> and <span class="math display">\[E=mc^2\]</span>.</p>`,
+		},
+		{
+			name:   "amounts and code",
+			source: "Costs $5 and $10, `$x$` and \\$y$.",
+			want:   "<p>Costs $5 and $10, <code>$x$</code> and $y$.</p>",
+		},
+		{
+			name:   "display math block",
+			source: "Sum:\n$$\n\\sum_{i=1}^n i = \\frac{n(n+1)}{2}\n$$\nDone.",
+			want:   "<p>Sum:</p>\n<div class=\"math display\">\\[\\sum_{i=1}^n i = \\frac{n(n+1)}{2}\\]</div>\n<p>Done.</p>",
+		},
+		{
+			name:   "one line math block",
+			source: "$$a<b$$",
+			want:   `<div class="math display">\[a&lt;b\]</div>`,
+		},
+		{
+			name:   "sanitized html",
+			source: "<b onclick=\"alert(1)\">bold</b> [x](javascript:alert(1))\n\n<script>alert(1)</script>",
+			want:   `<p><b>bold</b> x</p>`,
+		},
+		{
+			name:   "lesson links",
+			source: "[by order](lesson:2#task) [by id](lesson:" + lessonID.String() + ") [gone](lesson:7)",
+			want: `<p><a href="` + lessonURL + `#task" class="lesson-link" rel="nofollow">by order</a> ` +
+				`<a href="` + lessonURL + `" class="lesson-link" rel="nofollow">by id</a> gone</p>`,
+			broken: []string{"lesson:7"},
+		},
+	}
+
+	r := render.NewRenderer(render.DefaultCacheSize)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := r.Render(tt.source, links)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(doc.HTML); got != tt.want {
+				t.Errorf("HTML =\n%s\nwant\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(doc.BrokenLinks, tt.broken) {
+				t.Errorf("broken links = %v, want %v", doc.BrokenLinks, tt.broken)
+			}
+		})
+	}
+}
+
+func TestRenderTOC(t *testing.T) {
+	source := "# Дроби $\\frac{a}{b}$\n\ntext\n\n## Practice\n\n## Practice\n"
+	doc, err := render.NewRenderer(0).Render(source, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []render.Heading{
+		{Level: 1, ID: "дроби-fracab", Text: `Дроби $\frac{a}{b}$`},
+		{Level: 2, ID: "practice", Text: "Practice"},
+		{Level: 2, ID: "practice-1", Text: "Practice"},
+	}
+	if !reflect.DeepEqual(doc.TOC, want) {
+		t.Errorf("TOC = %+v, want %+v", doc.TOC, want)
+	}
+	if !strings.Contains(doc.HTML, `<h1 id="дроби-fracab">`) {
+		t.Errorf("HTML lacks the heading ID: %s", doc.HTML)
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	r := render.NewRenderer(1)
+	first, _ := r.Render("See [next](lesson:2).", links)
+	again, _ := r.Render("See [next](lesson:2).", links)
+	if first != again {
+		t.Error("rendering the same text twice did not use the cache")
+	}
+
+	moved := render.CourseLinks(courseID, []models.Lesson{{Base: models.Base{ID: lessonID}, LessonOrder: 3}})
+	relinked, _ := r.Render("See [next](lesson:2).", moved)
+	if relinked.Hash == first.Hash || len(relinked.BrokenLinks) != 1 {
+		t.Errorf("rendering with other links = %+v, want a new document with a broken link", relinked)
+	}
+	if again, _ := r.Render("See [next](lesson:2).", links); again == first {
+		t.Error("cache of size 1 kept the evicted document")
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := render.NewCache(2)
+	c.Put(&render.Document{Hash: "a"})
+	c.Put(&render.Document{Hash: "b"})
+	c.Get("a")
+	c.Put(&render.Document{Hash: "c"})
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used document was not evicted")
+	}
+	for _, hash := range []string{"a", "c"} {
+		if _, ok := c.Get(hash); !ok {
+			t.Errorf("document %s was evicted", hash)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+}
@@ -0,0 +1,64 @@
+package render
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/repository"
+)
+
+// Service renders the theory content of lessons.
+type Service struct {
+	repos    *repository.Repositories
+	renderer *Renderer
+}
+
+// NewService creates a rendering service.
+func NewService(repos *repository.Repositories, renderer *Renderer) *Service {
+	return &Service{repos: repos, renderer: renderer}
+}
+
+// Contents renders the theory items among the contents of a lesson and
+// returns their documents by content ID. Lesson links resolve to the
+// lessons of the lesson's course. It returns db.ErrNotFound when the
+// lesson does not exist.
+func (s *Service) Contents(ctx context.Context, lessonID uuid.UUID, items []models.Content) (map[uuid.UUID]*Document, error) {
+	docs := map[uuid.UUID]*Document{}
+	var theory []models.Content
+	for _, item := range items {
+		if item.Theory != nil {
+			theory = append(theory, item)
+		}
+	}
+	if len(theory) == 0 {
+		return docs, nil
+	}
+
+	var links Links
+	err := s.repos.Tx.WithReadOnlyTx(ctx, func(ctx context.Context) error {
+		lesson, err := s.repos.Lessons.Get(ctx, lessonID)
+		if err != nil {
+			return err
+		}
+		lessons, err := s.repos.Lessons.ListByCourse(ctx, lesson.CourseID)
+		if err != nil {
+			return err
+		}
+		links = CourseLinks(lesson.CourseID, lessons)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range theory {
+		doc, err := s.renderer.Render(item.Theory.TextContent, links)
+		if err != nil {
+			return nil, err
+		}
+		docs[item.ID] = doc
+	}
+	return docs, nil
+}
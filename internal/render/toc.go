@@ -0,0 +1,101 @@
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Heading is an entry of the table of contents of a text.
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// headingIDs generates the IDs of headings from their text. Unlike the
+// goldmark default it keeps non-ASCII letters, so Ukrainian headings get
+// readable IDs.
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: map[string]bool{}}
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var b strings.Builder
+	for _, r := range string(bytes.TrimSpace(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			b.WriteByte('-')
+		}
+	}
+	id := b.String()
+	if id == "" {
+		id = "heading"
+		if kind != ast.KindHeading {
+			id = "id"
+		}
+	}
+	unique := id
+	for i := 1; h.used[unique]; i++ {
+		unique = id + "-" + strconv.Itoa(i)
+	}
+	h.used[unique] = true
+	return []byte(unique)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
+
+// tableOfContents returns the headings of a document in order.
+func tableOfContents(doc ast.Node, source []byte) []Heading {
+	toc := []Heading{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		var h Heading
+		h.Level = heading.Level
+		if id, ok := heading.AttributeString("id"); ok {
+			h.ID = string(id.([]byte))
+		}
+		var b strings.Builder
+		plainText(&b, heading, source)
+		h.Text = strings.TrimSpace(b.String())
+		toc = append(toc, h)
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+// plainText writes the text of the inline children of n, with math as
+// its TeX source.
+func plainText(b *strings.Builder, n ast.Node, source []byte) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			b.Write(c.Segment.Value(source))
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(c.Value)
+		case *mathInline:
+			b.WriteByte('$')
+			b.Write(c.Segment.Value(source))
+			b.WriteByte('$')
+		default:
+			plainText(b, c, source)
+		}
+	}
+}
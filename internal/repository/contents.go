@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+)
+
+// ContentRepository stores lesson contents.
+type ContentRepository interface {
+	// Get returns a content item with its type-specific details.
+	Get(ctx context.Context, id uuid.UUID) (*models.Content, error)
+	// ListByLesson returns the contents of a lesson in order.
+	ListByLesson(ctx context.Context, lessonID uuid.UUID) ([]models.Content, error)
+	// Create inserts a content item with its type-specific details at its
+	// Order, or appends it when the order is zero.
+	Create(ctx context.Context, c models.Content) (*models.Content, error)
+	Update(ctx context.Context, id uuid.UUID, p db.ContentPatch) (*models.Content, error)
+	// CourseID returns the ID of the course a content item belongs to.
+	CourseID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+}
+
+type pgContents struct {
+	tx *db.TxManager
+}
+
+func (r *pgContents) Get(ctx context.Context, id uuid.UUID) (*models.Content, error) {
+	return db.GetContent(ctx, r.tx.Conn(ctx), id)
+}
+
+func (r *pgContents) ListByLesson(ctx context.Context, lessonID uuid.UUID) ([]models.Content, error) {
+	return db.ListLessonContents(ctx, r.tx.Conn(ctx), lessonID)
+}
+
+func (r *pgContents) Create(ctx context.Context, c models.Content) (*models.Content, error) {
+	return db.CreateContent(ctx, r.tx.Conn(ctx), c)
+}
+
+func (r *pgContents) Update(ctx context.Context, id uuid.UUID, p db.ContentPatch) (*models.Content, error) {
+	return db.UpdateContent(ctx, r.tx.Conn(ctx), id, p)
+}
+
+func (r *pgContents) CourseID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return db.GetContentCourseID(ctx, r.tx.Conn(ctx), id)
+}
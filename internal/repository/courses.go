@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+	"mathtermind-go/internal/pagination"
+)
+
+// CourseRepository stores courses.
+type CourseRepository interface {
+	// List returns a page of the courses matching the filter.
+	List(ctx context.Context, f db.CourseFilter, p db.CoursePage) (*pagination.Page[models.Course], error)
+	// Get returns a course with its lessons, their contents and its tags.
+	Get(ctx context.Context, id uuid.UUID) (*models.Course, error)
+	// GetSummary returns a course without its lessons and tags.
+	GetSummary(ctx context.Context, id uuid.UUID) (*models.Course, error)
+	Create(ctx context.Context, c models.Course) (*models.Course, error)
+	Update(ctx context.Context, id uuid.UUID, p db.CoursePatch) (*models.Course, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Owner returns the ID of the user who created the course, or nil.
+	Owner(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
+}
+
+type pgCourses struct {
+	tx *db.TxManager
+}
+
+func (r *pgCourses) List(ctx context.Context, f db.CourseFilter, p db.CoursePage) (*pagination.Page[models.Course], error) {
+	return db.ListCourses(ctx, r.tx.Conn(ctx), f, p)
+}
+
+func (r *pgCourses) Get(ctx context.Context, id uuid.UUID) (*models.Course, error) {
+	var course *models.Course
+	err := r.tx.WithReadOnlyTx(ctx, func(ctx context.Context) error {
+		var err error
+		course, err = db.GetCourse(ctx, r.tx.Conn(ctx), id)
+		return err
+	})
+	return course, err
+}
+
+func (r *pgCourses) GetSummary(ctx context.Context, id uuid.UUID) (*models.Course, error) {
+	return db.GetCourseSummary(ctx, r.tx.Conn(ctx), id)
+}
+
+func (r *pgCourses) Create(ctx context.Context, c models.Course) (*models.Course, error) {
+	return db.CreateCourse(ctx, r.tx.Conn(ctx), c)
+}
+
+func (r *pgCourses) Update(ctx context.Context, id uuid.UUID, p db.CoursePatch) (*models.Course, error) {
+	return db.UpdateCourse(ctx, r.tx.Conn(ctx), id, p)
+}
+
+func (r *pgCourses) Delete(ctx context.Context, id uuid.UUID) error {
+	return db.DeleteCourse(ctx, r.tx.Conn(ctx), id)
+}
+
+func (r *pgCourses) Owner(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	return db.GetCourseOwner(ctx, r.tx.Conn(ctx), id)
+}
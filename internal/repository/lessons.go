@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+)
+
+// LessonRepository stores the lessons of courses and keeps their order
+// within a course contiguous.
+type LessonRepository interface {
+	// Get returns a lesson without its contents.
+	Get(ctx context.Context, id uuid.UUID) (*models.Lesson, error)
+	// ListByCourse returns the lessons of a course in order, without their
+	// contents.
+	ListByCourse(ctx context.Context, courseID uuid.UUID) ([]models.Lesson, error)
+	// Create inserts a lesson at its LessonOrder, or appends it when the
+	// order is zero.
+	Create(ctx context.Context, l models.Lesson) (*models.Lesson, error)
+	Update(ctx context.Context, id uuid.UUID, p db.LessonPatch) (*models.Lesson, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Reorder sets the order of the course's lessons to the order of
+	// lessonIDs; see db.ReorderLessons.
+	Reorder(ctx context.Context, courseID uuid.UUID, lessonIDs []uuid.UUID) ([]models.Lesson, error)
+}
+
+type pgLessons struct {
+	tx *db.TxManager
+}
+
+func (r *pgLessons) Get(ctx context.Context, id uuid.UUID) (*models.Lesson, error) {
+	return db.GetLesson(ctx, r.tx.Conn(ctx), id)
+}
+
+func (r *pgLessons) ListByCourse(ctx context.Context, courseID uuid.UUID) ([]models.Lesson, error) {
+	return db.ListLessons(ctx, r.tx.Conn(ctx), courseID)
+}
+
+func (r *pgLessons) Create(ctx context.Context, l models.Lesson) (*models.Lesson, error) {
+	return db.CreateLesson(ctx, r.tx.Conn(ctx), l)
+}
+
+func (r *pgLessons) Update(ctx context.Context, id uuid.UUID, p db.LessonPatch) (*models.Lesson, error) {
+	return db.UpdateLesson(ctx, r.tx.Conn(ctx), id, p)
+}
+
+func (r *pgLessons) Delete(ctx context.Context, id uuid.UUID) error {
+	return db.DeleteLesson(ctx, r.tx.Conn(ctx), id)
+}
+
+func (r *pgLessons) Reorder(ctx context.Context, courseID uuid.UUID, lessonIDs []uuid.UUID) ([]models.Lesson, error) {
+	return db.ReorderLessons(ctx, r.tx.Conn(ctx), courseID, lessonIDs)
+}
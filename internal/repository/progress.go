@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+)
+
+// ProgressRepository stores learners' progress through courses and the
+// resumable state they saved for content items.
+//
+// The Lock methods must run in a transaction, which holds the lock until it
+// ends; outside one they return db.ErrNoTx. Locks are taken in the order
+// documented on db.LockCourseProgress.
+type ProgressRepository interface {
+	// List returns the user's progress in every course they started.
+	List(ctx context.Context, userID uuid.UUID) ([]models.Progress, error)
+	// Get returns the user's progress summary in a course.
+	Get(ctx context.Context, userID, courseID uuid.UUID) (*models.Progress, error)
+	// ListContents returns the user's progress on each content item of a course.
+	ListContents(ctx context.Context, userID, courseID uuid.UUID) ([]models.UserContentProgress, error)
+	ListCompletedLessons(ctx context.Context, userID, courseID uuid.UUID) ([]models.CompletedLesson, error)
+	// GetCompletedCourse returns db.ErrNotFound until the course is completed.
+	GetCompletedCourse(ctx context.Context, userID, courseID uuid.UUID) (*models.CompletedCourse, error)
+	// Last returns the content progress the user interacted with most
+	// recently.
+	Last(ctx context.Context, userID uuid.UUID) (*models.UserContentProgress, error)
+
+	// LockCourse returns the user's progress row for a course, creating it
+	// if needed, and locks it.
+	LockCourse(ctx context.Context, userID, courseID uuid.UUID) (*models.Progress, error)
+	// LockContent returns the user's progress row for a content item,
+	// creating it if needed, and locks it.
+	LockContent(ctx context.Context, userID, contentID uuid.UUID) (*models.UserContentProgress, error)
+	// CompleteContent marks a content item completed and adds timeSpentMin
+	// to the time spent on it.
+	CompleteContent(ctx context.Context, userID, contentID uuid.UUID, timeSpentMin int) (*models.UserContentProgress, error)
+	// CompleteLessonIfDone records the completion of a lesson once all its
+	// contents are completed, and returns db.ErrNotFound otherwise or when
+	// it was already recorded.
+	CompleteLessonIfDone(ctx context.Context, userID, lessonID uuid.UUID) (*models.CompletedLesson, error)
+	// CompleteCourseIfDone is CompleteLessonIfDone for a course.
+	CompleteCourseIfDone(ctx context.Context, userID, courseID uuid.UUID) (*models.CompletedCourse, error)
+	// SetCourseAchievements stores the achievements earned in a course on
+	// its completion record.
+	SetCourseAchievements(ctx context.Context, userID, courseID uuid.UUID) error
+	// Refresh recomputes a progress row from the user's content progress
+	// and adds timeSpentMin to the time spent in the course.
+	Refresh(ctx context.Context, progressID uuid.UUID, timeSpentMin int) (*models.Progress, error)
+
+	SaveState(ctx context.Context, s models.ContentState) (*models.ContentState, error)
+	ListStates(ctx context.Context, userID, contentID uuid.UUID) ([]models.ContentState, error)
+	DeleteState(ctx context.Context, userID, contentID uuid.UUID, stateType string) error
+}
+
+type pgProgress struct {
+	tx *db.TxManager
+}
+
+func (r *pgProgress) List(ctx context.Context, userID uuid.UUID) ([]models.Progress, error) {
+	return db.ListUserProgress(ctx, r.tx.Conn(ctx), userID)
+}
+
+func (r *pgProgress) Get(ctx context.Context, userID, courseID uuid.UUID) (*models.Progress, error) {
+	return db.GetCourseProgress(ctx, r.tx.Conn(ctx), userID, courseID)
+}
+
+func (r *pgProgress) ListContents(ctx context.Context, userID, courseID uuid.UUID) ([]models.UserContentProgress, error) {
+	return db.ListCourseContentProgress(ctx, r.tx.Conn(ctx), userID, courseID)
+}
+
+func (r *pgProgress) ListCompletedLessons(ctx context.Context, userID, courseID uuid.UUID) ([]models.CompletedLesson, error) {
+	return db.ListCompletedLessons(ctx, r.tx.Conn(ctx), userID, courseID)
+}
+
+func (r *pgProgress) GetCompletedCourse(ctx context.Context, userID, courseID uuid.UUID) (*models.CompletedCourse, error) {
+	return db.GetCompletedCourse(ctx, r.tx.Conn(ctx), userID, courseID)
+}
+
+func (r *pgProgress) Last(ctx context.Context, userID uuid.UUID) (*models.UserContentProgress, error) {
+	return db.GetLastContentProgress(ctx, r.tx.Conn(ctx), userID)
+}
+
+func (r *pgProgress) LockCourse(ctx context.Context, userID, courseID uuid.UUID) (*models.Progress, error) {
+	tx, err := db.RequireTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return db.LockCourseProgress(ctx, tx, userID, courseID)
+}
+
+func (r *pgProgress) LockContent(ctx context.Context, userID, contentID uuid.UUID) (*models.UserContentProgress, error) {
+	tx, err := db.RequireTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return db.LockContentProgress(ctx, tx, userID, contentID)
+}
+
+func (r *pgProgress) CompleteContent(ctx context.Context, userID, contentID uuid.UUID, timeSpentMin int) (*models.UserContentProgress, error) {
+	return db.CompleteContentProgress(ctx, r.tx.Conn(ctx), userID, contentID, timeSpentMin)
+}
+
+func (r *pgProgress) CompleteLessonIfDone(ctx context.Context, userID, lessonID uuid.UUID) (*models.CompletedLesson, error) {
+	return db.CompleteLessonIfDone(ctx, r.tx.Conn(ctx), userID, lessonID)
+}
+
+func (r *pgProgress) CompleteCourseIfDone(ctx context.Context, userID, courseID uuid.UUID) (*models.CompletedCourse, error) {
+	return db.CompleteCourseIfDone(ctx, r.tx.Conn(ctx), userID, courseID)
+}
+
+func (r *pgProgress) SetCourseAchievements(ctx context.Context, userID, courseID uuid.UUID) error {
+	return db.SetCourseAchievements(ctx, r.tx.Conn(ctx), userID, courseID)
+}
+
+func (r *pgProgress) Refresh(ctx context.Context, progressID uuid.UUID, timeSpentMin int) (*models.Progress, error) {
+	return db.RefreshCourseProgress(ctx, r.tx.Conn(ctx), progressID, timeSpentMin)
+}
+
+func (r *pgProgress) SaveState(ctx context.Context, s models.ContentState) (*models.ContentState, error) {
+	return db.UpsertContentState(ctx, r.tx.Conn(ctx), s)
+}
+
+func (r *pgProgress) ListStates(ctx context.Context, userID, contentID uuid.UUID) ([]models.ContentState, error) {
+	return db.ListContentStates(ctx, r.tx.Conn(ctx), userID, contentID)
+}
+
+func (r *pgProgress) DeleteState(ctx context.Context, userID, contentID uuid.UUID, stateType string) error {
+	return db.DeleteContentState(ctx, r.tx.Conn(ctx), userID, contentID, stateType)
+}
@@ -0,0 +1,54 @@
+// Package repository defines the persistence interfaces of the aggregates
+// the API works with, and their PostgreSQL implementations on top of the db
+// package.
+//
+// Repository methods run in the transaction carried by their context when
+// there is one (see TxManager), and on the connection pool otherwise.
+// Errors are those of the db package: missing rows are db.ErrNotFound.
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+)
+
+// TxManager runs units of work in transactions carried by their context.
+type TxManager interface {
+	// WithTx runs fn in a transaction, committing it when fn returns nil.
+	// Called inside a transaction, fn joins it.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// WithReadOnlyTx runs fn in a read-only transaction reading from one
+	// snapshot. Called inside a transaction, fn joins it.
+	WithReadOnlyTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Repositories bundles the repositories of every aggregate with the
+// transaction manager they take part in.
+type Repositories struct {
+	Tx       TxManager
+	Courses  CourseRepository
+	Lessons  LessonRepository
+	Contents ContentRepository
+	Tags     TagRepository
+	Users    UserRepository
+	Sessions SessionRepository
+	Progress ProgressRepository
+}
+
+// New returns the PostgreSQL repositories on the pool.
+func New(pool *pgxpool.Pool) *Repositories {
+	tx := db.NewTxManager(pool)
+	return &Repositories{
+		Tx:       tx,
+		Courses:  &pgCourses{tx: tx},
+		Lessons:  &pgLessons{tx: tx},
+		Contents: &pgContents{tx: tx},
+		Tags:     &pgTags{tx: tx},
+		Users:    &pgUsers{tx: tx},
+		Sessions: &pgSessions{tx: tx},
+		Progress: &pgProgress{tx: tx},
+	}
+}
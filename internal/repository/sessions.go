@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+)
+
+// SessionRepository stores the hashes of refresh tokens. The tokens of one
+// login form a family: each rotation revokes a token and adds its
+// replacement to the family.
+type SessionRepository interface {
+	// Start stores a refresh token starting a new family.
+	Start(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// Rotate revokes the token identified by oldHash, stores newHash as its
+	// replacement and returns the owner's ID. It returns
+	// db.ErrRefreshTokenExpired for an expired token, and revokes the
+	// whole family and returns db.ErrRefreshTokenReused for a revoked one.
+	Rotate(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (uuid.UUID, error)
+	// Revoke revokes the family of the token identified by tokenHash.
+	Revoke(ctx context.Context, tokenHash string) error
+}
+
+type pgSessions struct {
+	tx *db.TxManager
+}
+
+func (r *pgSessions) Start(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	return db.InsertRefreshToken(ctx, r.tx.Conn(ctx), userID, tokenHash, expiresAt)
+}
+
+func (r *pgSessions) Rotate(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (uuid.UUID, error) {
+	return db.RotateRefreshToken(ctx, r.tx.Conn(ctx), oldHash, newHash, expiresAt)
+}
+
+func (r *pgSessions) Revoke(ctx context.Context, tokenHash string) error {
+	return db.RevokeRefreshToken(ctx, r.tx.Conn(ctx), tokenHash)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+)
+
+// TagRepository stores tags and their attachment to courses.
+type TagRepository interface {
+	// List returns the tags of a category, or of every category when it is
+	// empty, with their course counts.
+	List(ctx context.Context, category string) ([]db.TagSummary, error)
+	Get(ctx context.Context, id uuid.UUID) (*models.Tag, error)
+	GetByName(ctx context.Context, name string) (*models.Tag, error)
+	// Create and Update return db.ErrTagNameTaken for duplicate names.
+	Create(ctx context.Context, name, category string) (*models.Tag, error)
+	Update(ctx context.Context, id uuid.UUID, name, category *string) (*models.Tag, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByCourse returns the tags attached to a course.
+	ListByCourse(ctx context.Context, courseID uuid.UUID) ([]models.Tag, error)
+	Attach(ctx context.Context, courseID, tagID uuid.UUID) error
+	Detach(ctx context.Context, courseID, tagID uuid.UUID) error
+}
+
+type pgTags struct {
+	tx *db.TxManager
+}
+
+func (r *pgTags) List(ctx context.Context, category string) ([]db.TagSummary, error) {
+	return db.ListTags(ctx, r.tx.Conn(ctx), category)
+}
+
+func (r *pgTags) Get(ctx context.Context, id uuid.UUID) (*models.Tag, error) {
+	return db.GetTag(ctx, r.tx.Conn(ctx), id)
+}
+
+func (r *pgTags) GetByName(ctx context.Context, name string) (*models.Tag, error) {
+	return db.GetTagByName(ctx, r.tx.Conn(ctx), name)
+}
+
+func (r *pgTags) Create(ctx context.Context, name, category string) (*models.Tag, error) {
+	return db.CreateTag(ctx, r.tx.Conn(ctx), name, category)
+}
+
+func (r *pgTags) Update(ctx context.Context, id uuid.UUID, name, category *string) (*models.Tag, error) {
+	return db.UpdateTag(ctx, r.tx.Conn(ctx), id, name, category)
+}
+
+func (r *pgTags) Delete(ctx context.Context, id uuid.UUID) error {
+	return db.DeleteTag(ctx, r.tx.Conn(ctx), id)
+}
+
+func (r *pgTags) ListByCourse(ctx context.Context, courseID uuid.UUID) ([]models.Tag, error) {
+	return db.ListCourseTags(ctx, r.tx.Conn(ctx), courseID)
+}
+
+func (r *pgTags) Attach(ctx context.Context, courseID, tagID uuid.UUID) error {
+	return db.AttachTag(ctx, r.tx.Conn(ctx), courseID, tagID)
+}
+
+func (r *pgTags) Detach(ctx context.Context, courseID, tagID uuid.UUID) error {
+	return db.DetachTag(ctx, r.tx.Conn(ctx), courseID, tagID)
+}
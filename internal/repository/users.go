@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/models"
+)
+
+// UserRepository stores users and their personal settings.
+type UserRepository interface {
+	// Create inserts a user. A taken username or email is reported as
+	// db.ErrUsernameTaken or db.ErrEmailTaken.
+	Create(ctx context.Context, u models.User) (*models.User, error)
+	Get(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	// GetByEmail compares emails case insensitively.
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) (*models.User, error)
+	// Settings returns the user's settings, storing the defaults first for
+	// users who have none yet.
+	Settings(ctx context.Context, userID uuid.UUID) (*models.UserSetting, error)
+	// UpdateSettings applies a patch to the user's settings, creating them
+	// first if needed.
+	UpdateSettings(ctx context.Context, userID uuid.UUID, p db.UserSettingPatch) (*models.UserSetting, error)
+}
+
+type pgUsers struct {
+	tx *db.TxManager
+}
+
+func (r *pgUsers) Create(ctx context.Context, u models.User) (*models.User, error) {
+	return db.CreateUser(ctx, r.tx.Conn(ctx), u)
+}
+
+func (r *pgUsers) Get(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return db.GetUserByID(ctx, r.tx.Conn(ctx), id)
+}
+
+func (r *pgUsers) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return db.GetUserByUsername(ctx, r.tx.Conn(ctx), username)
+}
+
+func (r *pgUsers) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return db.GetUserByEmail(ctx, r.tx.Conn(ctx), email)
+}
+
+func (r *pgUsers) UpdateRole(ctx context.Context, id uuid.UUID, role string) (*models.User, error) {
+	return db.UpdateUserRole(ctx, r.tx.Conn(ctx), id, role)
+}
+
+func (r *pgUsers) Settings(ctx context.Context, userID uuid.UUID) (*models.UserSetting, error) {
+	q := r.tx.Conn(ctx)
+	if err := db.EnsureUserSettings(ctx, q, userID); err != nil {
+		return nil, err
+	}
+	return db.GetUserSettings(ctx, q, userID)
+}
+
+func (r *pgUsers) UpdateSettings(ctx context.Context, userID uuid.UUID, p db.UserSettingPatch) (*models.UserSetting, error) {
+	q := r.tx.Conn(ctx)
+	if err := db.EnsureUserSettings(ctx, q, userID); err != nil {
+		return nil, err
+	}
+	return db.UpdateUserSettings(ctx, q, userID, p)
+}
@@ -0,0 +1,120 @@
+// Package scheduler runs periodic background jobs. A database lease makes
+// each job run on only one replica at a time; the other replicas stand by
+// and take over when the lease holder stops renewing it.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+)
+
+// Job is work run every Interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// Run does one round of the job. Its context is not canceled when the
+	// scheduler stops, so a round in progress can finish; it is bounded by
+	// Interval.
+	Run func(ctx context.Context) error
+}
+
+// Scheduler runs jobs under leases.
+type Scheduler struct {
+	pool   *pgxpool.Pool
+	holder string
+	jobs   []Job
+	wg     sync.WaitGroup
+}
+
+// New creates a scheduler. Its leases are held under an ID unique to this
+// process.
+func New(pool *pgxpool.Pool) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		pool:   pool,
+		holder: fmt.Sprintf("%s/%d/%s", host, os.Getpid(), uuid.NewString()[:8]),
+	}
+}
+
+// Add registers a job. Jobs must be added before Start.
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every job until ctx is done. Use Wait to wait for the rounds
+// in progress to finish.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.run(ctx, job)
+		}()
+	}
+}
+
+// Wait waits until every job stopped after the context passed to Start is
+// done, or until ctx is done.
+func (s *Scheduler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run runs rounds of job while this process holds its lease, until ctx is
+// done, and then releases the lease so another replica can take over.
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	// The lease outlives one missed renewal.
+	ttl := 2 * job.Interval
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		held, err := db.AcquireLease(ctx, s.pool, job.Name, s.holder, ttl)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.Error("Failed to acquire job lease", "job", job.Name, "error", err)
+		case held:
+			s.round(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			if err := db.ReleaseLease(releaseCtx, s.pool, job.Name, s.holder); err != nil {
+				slog.Warn("Failed to release job lease", "job", job.Name, "error", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// round runs one round of job, letting it finish when ctx is done meanwhile.
+func (s *Scheduler) round(ctx context.Context, job Job) {
+	roundCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), job.Interval)
+	defer cancel()
+
+	start := time.Now()
+	if err := job.Run(roundCtx); err != nil {
+		slog.Error("Scheduled job failed", "job", job.Name, "error", err, "duration", time.Since(start))
+	}
+}
@@ -0,0 +1,110 @@
+// Package search finds courses, lessons and theory content by full-text
+// search in English and Ukrainian.
+package search
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+)
+
+// Type is the kind of item a hit refers to.
+type Type string
+
+const (
+	TypeCourse  Type = "course"
+	TypeLesson  Type = "lesson"
+	TypeContent Type = "content"
+)
+
+// MaxQueryLength is the longest query text accepted, in characters.
+const MaxQueryLength = 200
+
+// ParseTypes parses the hit types to search for; no types means all of them.
+func ParseTypes(values []string) ([]Type, error) {
+	if len(values) == 0 {
+		return []Type{TypeCourse, TypeLesson, TypeContent}, nil
+	}
+	types := make([]Type, 0, len(values))
+	for _, v := range values {
+		switch t := Type(v); t {
+		case TypeCourse, TypeLesson, TypeContent:
+			types = append(types, t)
+		default:
+			return nil, fmt.Errorf("unknown type %q", v)
+		}
+	}
+	return types, nil
+}
+
+// Highlight escapes a snippet for HTML and wraps its matched words in
+// <mark> elements.
+func Highlight(snippet string) string {
+	s := html.EscapeString(snippet)
+	s = strings.ReplaceAll(s, db.HighlightStart, "<mark>")
+	return strings.ReplaceAll(s, db.HighlightStop, "</mark>")
+}
+
+// Query is a search request.
+type Query struct {
+	// Text is a web-search style query: words, "quoted phrases", OR and
+	// -excluded words.
+	Text   string
+	Types  []Type
+	Limit  int
+	Offset int
+}
+
+// Results is a page of search hits, best ranked first. Hit snippets are
+// HTML with the matched words in <mark> elements.
+type Results struct {
+	Query  string         `json:"query"`
+	Items  []db.SearchHit `json:"items"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// Service searches the course catalog.
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+// NewService creates a search service.
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Search returns a page of the hits matching q.
+func (s *Service) Search(ctx context.Context, q Query) (*Results, error) {
+	text := strings.TrimSpace(q.Text)
+	if text == "" || utf8.RuneCountInString(text) > MaxQueryLength {
+		return nil, apperrors.Errorf(apperrors.ErrCodeValidation, "query must be 1 to %d characters", MaxQueryLength)
+	}
+	types := make([]string, len(q.Types))
+	for i, t := range q.Types {
+		types[i] = string(t)
+	}
+
+	hits, err := db.Search(ctx, s.pool, text, types, q.Limit, q.Offset)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to search")
+	}
+	for i := range hits {
+		hits[i].Snippet = Highlight(hits[i].Snippet)
+	}
+	return &Results{Query: text, Items: hits, Limit: q.Limit, Offset: q.Offset}, nil
+}
+
+// UkrainianStemming reports whether Ukrainian words are stemmed. Without
+// the hunspell uk_UA dictionary on the database server they only match in
+// the form they are written; English words are always stemmed.
+func (s *Service) UkrainianStemming(ctx context.Context) (bool, error) {
+	return db.UkrainianStemming(ctx, s.pool)
+}
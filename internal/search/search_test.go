@@ -0,0 +1,55 @@
+package search_test
+
+import (
+	"slices"
+	"testing"
+
+	"mathtermind-go/internal/db"
+	"mathtermind-go/internal/search"
+)
+
+func TestParseTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    []search.Type
+		wantErr bool
+	}{
+		{"none", nil, []search.Type{search.TypeCourse, search.TypeLesson, search.TypeContent}, false},
+		{"one", []string{"lesson"}, []search.Type{search.TypeLesson}, false},
+		{"several", []string{"content", "course"}, []search.Type{search.TypeContent, search.TypeCourse}, false},
+		{"unknown", []string{"course", "user"}, nil, true},
+		{"case", []string{"Course"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := search.ParseTypes(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTypes(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseTypes(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	mark := func(s string) string { return db.HighlightStart + s + db.HighlightStop }
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"no matches", "no matches"},
+		{"solve " + mark("linear") + " equations", "solve <mark>linear</mark> equations"},
+		{mark("Дроби") + " і " + mark("дроби"), "<mark>Дроби</mark> і <mark>дроби</mark>"},
+		{"a < b & " + mark("<b>"), "a &lt; b &amp; <mark>&lt;b&gt;</mark>"},
+	}
+
+	for _, tt := range tests {
+		if got := search.Highlight(tt.in); got != tt.want {
+			t.Errorf("Highlight(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
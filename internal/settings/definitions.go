@@ -0,0 +1,79 @@
+package settings
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Keys of the runtime settings the application reads.
+const (
+	KeyLeaderboardSize        = "leaderboard.size"
+	KeyLessonPointsMultiplier = "points.lesson_multiplier"
+)
+
+// Definition describes a runtime setting the application reads. Settings
+// without a definition may be stored but are not used.
+type Definition struct {
+	Key         string
+	Default     string
+	Description string
+	// Validate checks a value of the setting.
+	Validate func(value string) error
+}
+
+var definitions = map[string]Definition{
+	KeyLeaderboardSize: {
+		Key:         KeyLeaderboardSize,
+		Default:     "10",
+		Description: "Number of top entries a leaderboard shows by default",
+		Validate:    intRange(1, 100),
+	},
+	KeyLessonPointsMultiplier: {
+		Key:         KeyLessonPointsMultiplier,
+		Default:     "1",
+		Description: "Factor applied to the points reward of every completed lesson",
+		Validate:    floatRange(0, 10),
+	},
+}
+
+// Lookup returns the definition of a setting.
+func Lookup(key string) (Definition, bool) {
+	d, ok := definitions[key]
+	return d, ok
+}
+
+// Validate checks a value for the setting with the given key. Values of
+// settings without a definition are not checked.
+func Validate(key, value string) error {
+	d, ok := definitions[key]
+	if !ok {
+		return nil
+	}
+	return d.Validate(value)
+}
+
+func intRange(lo, hi int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		if n < lo || n > hi {
+			return fmt.Errorf("must be between %d and %d", lo, hi)
+		}
+		return nil
+	}
+}
+
+func floatRange(lo, hi float64) func(string) error {
+	return func(value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		if f < lo || f > hi {
+			return fmt.Errorf("must be between %g and %g", lo, hi)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,46 @@
+package settings_test
+
+import (
+	"testing"
+
+	"mathtermind-go/internal/settings"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{settings.KeyLeaderboardSize, "25", false},
+		{settings.KeyLeaderboardSize, "0", true},
+		{settings.KeyLeaderboardSize, "101", true},
+		{settings.KeyLeaderboardSize, "ten", true},
+		{settings.KeyLessonPointsMultiplier, "1.5", false},
+		{settings.KeyLessonPointsMultiplier, "0", false},
+		{settings.KeyLessonPointsMultiplier, "-1", true},
+		{settings.KeyLessonPointsMultiplier, "", true},
+		{"feature.undefined", "anything", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			err := settings.Validate(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultsAreValid(t *testing.T) {
+	for _, key := range []string{settings.KeyLeaderboardSize, settings.KeyLessonPointsMultiplier} {
+		d, ok := settings.Lookup(key)
+		if !ok {
+			t.Fatalf("Lookup(%q) found no definition", key)
+		}
+		if err := d.Validate(d.Default); err != nil {
+			t.Errorf("default %q of %s is invalid: %v", d.Default, key, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Package settings provides runtime application settings stored in the
+// settings table. Settings are cached in memory and reloaded whenever the
+// table changes on any replica, so they can be changed without a redeploy.
+package settings
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+)
+
+// keyPattern matches valid setting keys such as "leaderboard.size".
+var keyPattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+// Service caches application settings and edits them.
+type Service struct {
+	pool *pgxpool.Pool
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewService creates a settings service. Call Load before reading settings.
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool, values: map[string]string{}}
+}
+
+// Load reads all settings into the cache.
+func (s *Service) Load(ctx context.Context) error {
+	rows, err := db.ListSettings(ctx, s.pool)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]string, len(rows))
+	for _, row := range rows {
+		values[row.Key] = row.Value
+	}
+	s.mu.Lock()
+	s.values = values
+	s.mu.Unlock()
+	return nil
+}
+
+// Run reloads the cache whenever settings change, until ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	reload := func(ctx context.Context) {
+		if err := s.Load(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to reload settings", "error", err)
+		}
+	}
+	db.Listen(ctx, s.pool, db.Listener{
+		Channel:  db.SettingsChannel,
+		OnListen: reload,
+		OnNotify: func(ctx context.Context, _ string) { reload(ctx) },
+	})
+}
+
+// String returns the value of a setting, or its default when it is unset.
+func (s *Service) String(key string) string {
+	s.mu.RLock()
+	v, ok := s.values[key]
+	s.mu.RUnlock()
+	if !ok {
+		d, _ := Lookup(key)
+		return d.Default
+	}
+	return v
+}
+
+// Int returns a setting as an integer, falling back to its default when the
+// stored value is invalid.
+func (s *Service) Int(key string) int {
+	n, err := strconv.Atoi(s.valid(key))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Float returns a setting as a number, falling back to its default when the
+// stored value is invalid.
+func (s *Service) Float(key string) float64 {
+	f, err := strconv.ParseFloat(s.valid(key), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// valid returns the value of a setting if it passes validation and its
+// default otherwise.
+func (s *Service) valid(key string) string {
+	v := s.String(key)
+	if err := Validate(key, v); err != nil {
+		d, _ := Lookup(key)
+		slog.Warn("Invalid setting value, using default", "key", key, "value", v, "error", err)
+		return d.Default
+	}
+	return v
+}
+
+// LeaderboardSize returns the number of top entries a leaderboard shows by
+// default.
+func (s *Service) LeaderboardSize() int {
+	return s.Int(KeyLeaderboardSize)
+}
+
+// LessonPointsMultiplier returns the factor applied to lesson points rewards.
+func (s *Service) LessonPointsMultiplier() float64 {
+	return s.Float(KeyLessonPointsMultiplier)
+}
+
+// List returns all stored settings.
+func (s *Service) List(ctx context.Context) ([]models.Setting, error) {
+	settings, err := db.ListSettings(ctx, s.pool)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to list settings")
+	}
+	return settings, nil
+}
+
+// Set changes the value of an unprotected setting, creating it if needed.
+// Values of defined settings are validated. The description is kept when
+// nil.
+func (s *Service) Set(ctx context.Context, key, value string, description *string) (*models.Setting, error) {
+	if !keyPattern.MatchString(key) || len(key) > 255 {
+		return nil, apperrors.Errorf(apperrors.ErrCodeValidation, "invalid setting key %q", key)
+	}
+	if err := Validate(key, value); err != nil {
+		return nil, apperrors.Validation("Invalid setting value", map[string]any{"errors": map[string]string{"value": err.Error()}})
+	}
+	if description == nil {
+		if d, ok := Lookup(key); ok {
+			description = &d.Description
+		}
+	}
+
+	var setting *models.Setting
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		current, err := db.LockSetting(ctx, tx, key)
+		if err == nil && current.IsProtected {
+			return protectedError(key)
+		}
+		if err != nil && !errors.Is(err, db.ErrNotFound) {
+			return err
+		}
+		setting, err = db.UpsertSetting(ctx, tx, key, value, description)
+		if errors.Is(err, db.ErrNotFound) {
+			// Created as protected since it was read.
+			return protectedError(key)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to update setting")
+	}
+
+	// Other replicas reload when notified; reload here too so the change
+	// is visible to the next request on this one.
+	if err := s.Load(ctx); err != nil {
+		slog.Error("Failed to reload settings", "error", err)
+	}
+	return setting, nil
+}
+
+// Reset deletes an unprotected setting, so its default applies again.
+func (s *Service) Reset(ctx context.Context, key string) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		current, err := db.LockSetting(ctx, tx, key)
+		if errors.Is(err, db.ErrNotFound) {
+			return apperrors.NotFound("setting", key)
+		}
+		if err != nil {
+			return err
+		}
+		if current.IsProtected {
+			return protectedError(key)
+		}
+		return db.DeleteSetting(ctx, tx, key)
+	})
+	if err != nil {
+		return apperrors.WrapDB(err, "failed to reset setting")
+	}
+
+	if err := s.Load(ctx); err != nil {
+		slog.Error("Failed to reload settings", "error", err)
+	}
+	return nil
+}
+
+func protectedError(key string) error {
+	return apperrors.New(apperrors.ErrCodeForbidden, "Setting is protected").WithDetails(map[string]any{"key": key})
+}
@@ -0,0 +1,164 @@
+// Package study records study sessions and reports daily study time,
+// streaks and daily goal attainment.
+package study
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"mathtermind-go/internal/db"
+	apperrors "mathtermind-go/internal/errors"
+	"mathtermind-go/internal/models"
+)
+
+// HeartbeatTimeout is the longest gap between heartbeats that still counts
+// as study time. Clients should send a heartbeat about once a minute; a
+// longer gap means the learner was away and the gap is not credited.
+const HeartbeatTimeout = 3 * time.Minute
+
+// Day is the study time of one day and whether it met the daily goal.
+type Day struct {
+	Date    string `json:"date"`
+	Minutes int    `json:"minutes"`
+	GoalMet bool   `json:"goal_met"`
+}
+
+// Stats summarizes a user's study over recent weeks.
+type Stats struct {
+	Timezone      string `json:"timezone"`
+	DailyGoalMin  int    `json:"daily_goal_min"`
+	CurrentStreak int    `json:"current_streak"`
+	LongestStreak int    `json:"longest_streak"`
+	DaysGoalMet   int    `json:"days_goal_met"`
+	TotalMinutes  int    `json:"total_minutes"`
+	Days          []Day  `json:"days"`
+}
+
+// Service records study sessions and computes study statistics.
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+// NewService creates a study service.
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Start opens a study session, ending the session the user still had open.
+func (s *Service) Start(ctx context.Context, userID uuid.UUID, contentID *uuid.UUID) (*models.StudySession, error) {
+	var session *models.StudySession
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if err := db.CloseStudySessions(ctx, tx, userID); err != nil {
+			return err
+		}
+		var err error
+		session, err = db.CreateStudySession(ctx, tx, userID, contentID)
+		return err
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to start study session")
+	}
+	return session, nil
+}
+
+// Heartbeat credits the time since the previous heartbeat to the session.
+func (s *Service) Heartbeat(ctx context.Context, userID, sessionID uuid.UUID) (*models.StudySession, error) {
+	return s.beat(ctx, userID, sessionID, false)
+}
+
+// End credits the time since the previous heartbeat and ends the session.
+func (s *Service) End(ctx context.Context, userID, sessionID uuid.UUID) (*models.StudySession, error) {
+	return s.beat(ctx, userID, sessionID, true)
+}
+
+func (s *Service) beat(ctx context.Context, userID, sessionID uuid.UUID, end bool) (*models.StudySession, error) {
+	var session *models.StudySession
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		current, err := db.LockStudySession(ctx, tx, sessionID)
+		if errors.Is(err, db.ErrNotFound) || (err == nil && current.UserID != userID) {
+			return apperrors.NotFound("study session", sessionID)
+		}
+		if err != nil {
+			return err
+		}
+		if current.EndedAt != nil {
+			return apperrors.New(apperrors.ErrCodeInvalidState, "Study session has ended")
+		}
+
+		now := time.Now()
+		if session, err = db.UpdateStudySession(ctx, tx, sessionID, now, credit(now.Sub(current.LastHeartbeatAt)), end); err != nil {
+			return err
+		}
+		return db.RefreshUserStudyTime(ctx, tx, userID)
+	})
+	if err != nil {
+		return nil, apperrors.WrapDB(err, "failed to record study time")
+	}
+	return session, nil
+}
+
+// credit returns the seconds of study credited for a gap between heartbeats.
+func credit(gap time.Duration) int {
+	if gap <= 0 || gap > HeartbeatTimeout {
+		return 0
+	}
+	return int(gap.Seconds())
+}
+
+// Stats returns the user's study time per day for the last weeks, ending
+// today in the user's timezone, together with their streaks.
+func (s *Service) Stats(ctx context.Context, userID uuid.UUID, weeks int) (*Stats, error) {
+	var st Stats
+	err := pgx.BeginTxFunc(ctx, s.pool, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, func(tx pgx.Tx) error {
+		var err error
+		st.Timezone, st.DailyGoalMin, err = db.GetStudyPreferences(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		loc, err := time.LoadLocation(st.Timezone)
+		if err != nil {
+			loc, st.Timezone = time.UTC, "UTC"
+		}
+
+		today := Date(time.Now(), loc)
+		from := today.AddDate(0, 0, -7*weeks+1)
+		studied, err := db.ListStudyDays(ctx, tx, userID, st.Timezone, from)
+		if err != nil {
+			return err
+		}
+		minutes := make(map[string]int, len(studied))
+		for _, d := range studied {
+			minutes[d.Date.Format(time.DateOnly)] = d.Minutes
+		}
+
+		for d := from; !d.After(today); d = d.AddDate(0, 0, 1) {
+			date := d.Format(time.DateOnly)
+			day := Day{Date: date, Minutes: minutes[date]}
+			day.GoalMet = day.Minutes > 0 && day.Minutes >= st.DailyGoalMin
+			if day.GoalMet {
+				st.DaysGoalMet++
+			}
+			st.TotalMinutes += day.Minutes
+			st.Days = append(st.Days, day)
+		}
+
+		dates, err := db.ListStudyDates(ctx, tx, userID, st.Timezone)
+		if err != nil {
+			return err
+		}
+		st.CurrentStreak, st.LongestStreak = Streaks(dates, today)
+		return nil
+	})
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, apperrors.NotFound("user", userID)
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrCodeDBQuery, "failed to get study stats")
+	}
+	return &st, nil
+}
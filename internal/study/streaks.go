@@ -0,0 +1,36 @@
+package study
+
+import "time"
+
+// Streaks returns the current and the longest run of consecutive study
+// days. dates must be calendar days (midnight UTC), sorted oldest first,
+// each listed once. The current streak stays alive until the end of the
+// day after the last study day, so it is not lost before today's session.
+func Streaks(dates []time.Time, today time.Time) (current, longest int) {
+	run := 0
+	var prev time.Time
+	for i, d := range dates {
+		if i > 0 && d.Equal(prev.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+		prev = d
+	}
+
+	if len(dates) == 0 {
+		return 0, 0
+	}
+	if last := dates[len(dates)-1]; last.Equal(today) || last.Equal(today.AddDate(0, 0, -1)) {
+		current = run
+	}
+	return current, longest
+}
+
+// Date returns the calendar day of t in loc as midnight UTC, the form
+// dates are compared in.
+func Date(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
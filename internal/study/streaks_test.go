@@ -0,0 +1,52 @@
+package study_test
+
+import (
+	"testing"
+	"time"
+
+	"mathtermind-go/internal/study"
+)
+
+func TestStreaks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	today := day(10)
+
+	tests := []struct {
+		name        string
+		dates       []time.Time
+		wantCurrent int
+		wantLongest int
+	}{
+		{"no study", nil, 0, 0},
+		{"today only", []time.Time{day(10)}, 1, 1},
+		{"ending today", []time.Time{day(7), day(8), day(9), day(10)}, 4, 4},
+		{"ending yesterday", []time.Time{day(8), day(9)}, 2, 2},
+		{"lapsed", []time.Time{day(5), day(6), day(7)}, 0, 3},
+		{"longer past run", []time.Time{day(1), day(2), day(3), day(4), day(9), day(10)}, 2, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := study.Streaks(tt.dates, today)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("Streaks() = (%d, %d), want (%d, %d)", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestDate(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+
+	// 23:30 UTC on March 9 is already March 10 in Kyiv.
+	at := time.Date(2024, 3, 9, 23, 30, 0, 0, time.UTC)
+	if got, want := study.Date(at, kyiv), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Date() = %v, want %v", got, want)
+	}
+	if got, want := study.Date(at, time.UTC), time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Date() = %v, want %v", got, want)
+	}
+}
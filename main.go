@@ -2,16 +2,38 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"mathtermind-go/internal/achievements"
 	"mathtermind-go/internal/api"
+	"mathtermind-go/internal/assessment"
+	"mathtermind-go/internal/auth"
 	"mathtermind-go/internal/config"
+	"mathtermind-go/internal/content"
+	"mathtermind-go/internal/coursepack"
+	"mathtermind-go/internal/gamification"
+	"mathtermind-go/internal/leaderboard"
 	"mathtermind-go/internal/logger"
+	"mathtermind-go/internal/notifications"
+	"mathtermind-go/internal/progress"
+	"mathtermind-go/internal/realtime"
+	"mathtermind-go/internal/reminders"
+	"mathtermind-go/internal/render"
+	"mathtermind-go/internal/repository"
+	"mathtermind-go/internal/scheduler"
+	"mathtermind-go/internal/search"
+	"mathtermind-go/internal/settings"
+	"mathtermind-go/internal/study"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+
 	dbconn "mathtermind-go/internal/db"
 )
 
@@ -46,7 +68,74 @@ func main() {
 	}
 	defer pool.Close()
 
-	router := api.NewRouter(pool)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, pool, os.Args[2:]); err != nil {
+			logger.Error("Migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cfg.Auth.JWTSecret == "" {
+		logger.Error("JWT_SECRET is not set")
+		os.Exit(1)
+	}
+
+	settingsSvc := settings.NewService(pool)
+	if err := settingsSvc.Load(ctx); err != nil {
+		logger.Error("Failed to load settings", "error", err)
+		os.Exit(1)
+	}
+
+	repos := repository.New(pool)
+
+	tokens := auth.NewTokenManager(cfg.Auth.JWTSecret, cfg.Auth.AccessTokenTTL)
+	authSvc := auth.NewService(repos, tokens, cfg.Auth.RefreshTokenTTL)
+
+	curve := gamification.Curve{
+		Base:     cfg.Levels.BasePoints,
+		Growth:   cfg.Levels.Growth,
+		MaxLevel: cfg.Levels.MaxLevel,
+	}
+	if err := curve.Validate(); err != nil {
+		logger.Error("Invalid level curve", "error", err)
+		os.Exit(1)
+	}
+	gamificationSvc := gamification.NewService(pool, curve, settingsSvc)
+
+	notificationSvc := notifications.NewService(pool)
+	leaderboardSvc := leaderboard.NewService(pool, settingsSvc)
+	reminderSvc := reminders.NewService(pool, notificationSvc)
+
+	hub := realtime.NewHub(pool)
+
+	searchSvc := search.NewService(pool)
+	if stemmed, err := searchSvc.UkrainianStemming(ctx); err != nil {
+		logger.Warn("Failed to check Ukrainian search stemming", "error", err)
+	} else if !stemmed {
+		logger.Warn("Ukrainian search text is not stemmed: install the hunspell uk_UA dictionary on the database server and run SELECT search_enable_ukrainian_stemming()")
+	}
+
+	achievementSvc := achievements.NewService(pool, gamificationSvc, notificationSvc)
+	progressSvc := progress.NewService(repos, gamificationSvc, achievementSvc, realtime.TxPublisher{})
+
+	router := api.NewRouter(&api.Services{
+		Repos:         repos,
+		Auth:          authSvc,
+		Content:       content.NewService(repos),
+		CoursePacks:   coursepack.NewService(repos),
+		Render:        render.NewService(repos, render.NewRenderer(render.DefaultCacheSize)),
+		Settings:      settingsSvc,
+		Gamification:  gamificationSvc,
+		Notifications: notificationSvc,
+		Achievements:  achievementSvc,
+		Progress:      progressSvc,
+		Assessment:    assessment.NewService(pool, progressSvc, gamificationSvc, achievementSvc),
+		Study:         study.NewService(pool),
+		Leaderboard:   leaderboardSvc,
+		Search:        searchSvc,
+		Hub:           hub,
+	})
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -55,6 +144,35 @@ func main() {
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Second,
 	}
+	// Disconnect realtime clients when shutdown begins; Shutdown does not
+	// wait for hijacked WebSocket connections and would otherwise wait for
+	// event streams until its deadline.
+	server.RegisterOnShutdown(hub.Close)
+
+	// Background jobs run until shutdown begins.
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go hub.Run(jobsCtx)
+	go settingsSvc.Run(jobsCtx)
+
+	sched := scheduler.New(pool)
+	sched.Add(scheduler.Job{
+		Name:     "leaderboard_refresh",
+		Interval: cfg.Leaderboard.RefreshInterval,
+		Run:      leaderboardSvc.Refresh,
+	})
+	sched.Add(scheduler.Job{
+		Name:     "study_reminders",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			sent, err := reminderSvc.SendDue(ctx)
+			if sent > 0 {
+				logger.Info("Sent study reminders", "count", sent)
+			}
+			return err
+		},
+	})
+	sched.Start(jobsCtx)
 
 	go func() {
 		logger.Info("Starting server", "port", cfg.Server.Port)
@@ -69,6 +187,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -76,6 +195,74 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", "error", err)
 	}
+	if err := sched.Wait(ctx); err != nil {
+		logger.Error("Scheduled jobs did not stop in time", "error", err)
+	}
 
 	logger.Info("Server exiting")
 }
+
+// runMigrate implements the `migrate up|down [n|all]|status|redo` subcommands.
+func runMigrate(ctx context.Context, pool *pgxpool.Pool, args []string) error {
+	migrator, err := dbconn.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [n|all]|status|redo")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			slog.Info("Applied migration", "version", m.Version, "name", m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			slog.Info("Database is up to date")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if args[1] == "all" {
+				steps = 0
+			} else if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			slog.Info("Reverted migration", "version", m.Version, "name", m.Name)
+		}
+		return err
+	case "redo":
+		m, err := migrator.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Redid migration", "version", m.Version, "name", m.Name)
+		return nil
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT\tNOTE")
+		for _, s := range statuses {
+			appliedAt, note := "pending", ""
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			if s.Modified {
+				note = "checksum mismatch"
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, appliedAt, note)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}